@@ -0,0 +1,128 @@
+package basket
+
+import (
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/store_types"
+	"go.citilink.cloud/user_types"
+	"time"
+)
+
+// AbandonmentState состояние корзины по давности последней активности в ней
+type AbandonmentState string
+
+const (
+	// AbandonmentStateActive с корзиной недавно работали
+	AbandonmentStateActive AbandonmentState = "active"
+	// AbandonmentStateIdle с корзиной давно не работали, но напоминать о ней еще рано
+	AbandonmentStateIdle AbandonmentState = "idle"
+	// AbandonmentStateAbandoned корзина брошена, пользователю можно напомнить о ней
+	AbandonmentStateAbandoned AbandonmentState = "abandoned"
+)
+
+// AbandonmentPolicy определяет брошенные корзины для кампаний напоминаний и истекшие анонимные корзины. Активностью
+// считается последнее изменение или просмотр корзины (BasketData.LastActivityAt)
+type AbandonmentPolicy struct {
+	// через сколько после последней активности корзина считается неактивной
+	idleAfter time.Duration
+	// через сколько после последней активности корзина считается брошенной
+	abandonedAfter time.Duration
+	// через сколько после последней активности анонимная корзина истекает, 0 - анонимные корзины не истекают
+	anonExpiresAfter time.Duration
+}
+
+func NewAbandonmentPolicy(
+	idleAfter time.Duration,
+	abandonedAfter time.Duration,
+	anonExpiresAfter time.Duration,
+) *AbandonmentPolicy {
+	return &AbandonmentPolicy{
+		idleAfter:        idleAfter,
+		abandonedAfter:   abandonedAfter,
+		anonExpiresAfter: anonExpiresAfter,
+	}
+}
+
+// State возвращает состояние корзины на момент now. Пустая корзина и корзина без известной активности (сохраненная до
+// появления времени изменения и с тех пор не менявшаяся) считаются активными, так как напоминать о них нечего или
+// неизвестно когда
+func (p *AbandonmentPolicy) State(bsk *Basket, now time.Time) AbandonmentState {
+	lastActivityAt := bsk.Data().LastActivityAt()
+	if bsk.Count() == 0 || lastActivityAt.IsZero() {
+		return AbandonmentStateActive
+	}
+
+	inactivity := now.Sub(lastActivityAt)
+	switch {
+	case inactivity >= p.abandonedAfter:
+		return AbandonmentStateAbandoned
+	case inactivity >= p.idleAfter:
+		return AbandonmentStateIdle
+	default:
+		return AbandonmentStateActive
+	}
+}
+
+// IsExpired проверяет, истекла ли корзина на момент now. Истекают только анонимные корзины, корзины пользователей
+// хранятся бессрочно
+func (p *AbandonmentPolicy) IsExpired(bsk *Basket, now time.Time) bool {
+	lastActivityAt := bsk.Data().LastActivityAt()
+	if bsk.IsUser() || p.anonExpiresAfter <= 0 || lastActivityAt.IsZero() {
+		return false
+	}
+
+	return now.Sub(lastActivityAt) >= p.anonExpiresAfter
+}
+
+// Summary возвращает сводку по брошенной корзине для выгрузки в кампанию напоминаний, либо nil, если корзина не брошена
+func (p *AbandonmentPolicy) Summary(bsk *Basket, now time.Time) *AbandonedBasketSummary {
+	if p.State(bsk, now) != AbandonmentStateAbandoned {
+		return nil
+	}
+
+	summary := &AbandonedBasketSummary{
+		SpaceId:        bsk.SpaceId(),
+		Cost:           bsk.Cost(),
+		LastActivityAt: bsk.Data().LastActivityAt(),
+		Items:          make([]*AbandonedBasketItem, 0, bsk.CountSelected()),
+	}
+
+	if bsk.IsUser() {
+		summary.UserId = user_types.UserId(bsk.User().GetId())
+	}
+
+	for _, item := range bsk.SelectedItems().SortByPosition() {
+		// комплектующие входят в стоимость конфигурации, поэтому в сводке отдельно не указываются
+		if item.Type().IsPartOfConfiguration() {
+			continue
+		}
+
+		summary.Items = append(summary.Items, &AbandonedBasketItem{
+			ItemId: item.ItemId(),
+			Type:   item.Type(),
+			Name:   item.Name(),
+			Count:  item.Count(),
+			Price:  item.Price(),
+		})
+	}
+
+	return summary
+}
+
+// AbandonedBasketSummary сводка по брошенной корзине для кампаний напоминаний
+type AbandonedBasketSummary struct {
+	// Идентификатор пользователя, пустой для анонимной корзины
+	UserId         user_types.UserId      `json:"userId,omitempty"`
+	SpaceId        store_types.SpaceId    `json:"spaceId"`
+	Cost           int                    `json:"cost"`
+	LastActivityAt time.Time              `json:"lastActivityAt"`
+	Items          []*AbandonedBasketItem `json:"items"`
+}
+
+// AbandonedBasketItem выбранная позиция брошенной корзины
+type AbandonedBasketItem struct {
+	ItemId basket_item.ItemId `json:"itemId"`
+	Type   basket_item.Type   `json:"type"`
+	Name   string             `json:"name"`
+	Count  int                `json:"count"`
+	Price  int                `json:"price"`
+}
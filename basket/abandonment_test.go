@@ -0,0 +1,130 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	userv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/profile/user/v1"
+	"testing"
+	"time"
+)
+
+func TestAbandonmentPolicy(t *testing.T) {
+	policy := NewAbandonmentPolicy(time.Hour, 24*time.Hour, 7*24*time.Hour)
+	lastActivityAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	newBasket := func(t *testing.T, user *userv1.User, modifiedAt time.Time, viewedAt time.Time) *Basket {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		product := basket_item.NewItem("1", basket_item.TypeProduct, "product", "", 2, 100, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+		_, err := data.Add(product)
+		require.NoError(t, err)
+		another := basket_item.NewItem("2", basket_item.TypeProduct, "another", "", 1, 50, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+		_, err = data.Add(another)
+		require.NoError(t, err)
+		unselected := basket_item.NewItem("3", basket_item.TypeProduct, "unselected", "", 1, 10, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+		unselected.SetIsSelected(false)
+		_, err = data.Add(unselected)
+		require.NoError(t, err)
+		data.createdAt, data.modifiedAt, data.viewedAt = modifiedAt, modifiedAt, viewedAt
+
+		return &Basket{data: data, user: user}
+	}
+
+	t.Run("state", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			basket func(t *testing.T) *Basket
+			now    time.Time
+			want   AbandonmentState
+		}{
+			{
+				name: "active",
+				basket: func(t *testing.T) *Basket {
+					return newBasket(t, nil, lastActivityAt, time.Time{})
+				},
+				now:  lastActivityAt.Add(time.Minute),
+				want: AbandonmentStateActive,
+			},
+			{
+				name: "idle",
+				basket: func(t *testing.T) *Basket {
+					return newBasket(t, nil, lastActivityAt, time.Time{})
+				},
+				now:  lastActivityAt.Add(time.Hour),
+				want: AbandonmentStateIdle,
+			},
+			{
+				name: "abandoned",
+				basket: func(t *testing.T) *Basket {
+					return newBasket(t, nil, lastActivityAt, time.Time{})
+				},
+				now:  lastActivityAt.Add(24 * time.Hour),
+				want: AbandonmentStateAbandoned,
+			},
+			{
+				name: "viewed after modification",
+				basket: func(t *testing.T) *Basket {
+					return newBasket(t, nil, lastActivityAt.Add(-48*time.Hour), lastActivityAt)
+				},
+				now:  lastActivityAt.Add(time.Minute),
+				want: AbandonmentStateActive,
+			},
+			{
+				name: "unknown activity",
+				basket: func(t *testing.T) *Basket {
+					return newBasket(t, nil, time.Time{}, time.Time{})
+				},
+				now:  lastActivityAt,
+				want: AbandonmentStateActive,
+			},
+			{
+				name: "empty basket",
+				basket: func(t *testing.T) *Basket {
+					data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+					data.modifiedAt = lastActivityAt
+
+					return &Basket{data: data}
+				},
+				now:  lastActivityAt.Add(48 * time.Hour),
+				want: AbandonmentStateActive,
+			},
+		}
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, policy.State(tt.basket(t), tt.now))
+			})
+		}
+	})
+
+	t.Run("is expired", func(t *testing.T) {
+		anonBasket := newBasket(t, nil, lastActivityAt, time.Time{})
+		userBasket := newBasket(t, &userv1.User{Id: "user"}, lastActivityAt, time.Time{})
+		now := lastActivityAt.Add(7 * 24 * time.Hour)
+
+		assert.False(t, policy.IsExpired(anonBasket, now.Add(-time.Second)))
+		assert.True(t, policy.IsExpired(anonBasket, now))
+		assert.False(t, policy.IsExpired(userBasket, now))
+		assert.False(t, NewAbandonmentPolicy(time.Hour, 24*time.Hour, 0).IsExpired(anonBasket, now))
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		bsk := newBasket(t, &userv1.User{Id: "user"}, lastActivityAt, time.Time{})
+
+		assert.Nil(t, policy.Summary(bsk, lastActivityAt.Add(time.Hour)))
+		assert.Equal(t, &AbandonedBasketSummary{
+			UserId:         "user",
+			SpaceId:        "msk_cl",
+			Cost:           bsk.Cost(),
+			LastActivityAt: lastActivityAt,
+			Items: []*AbandonedBasketItem{
+				{ItemId: "1", Type: basket_item.TypeProduct, Name: "product", Count: 2, Price: 100},
+				{ItemId: "2", Type: basket_item.TypeProduct, Name: "another", Count: 1, Price: 50},
+			},
+		}, policy.Summary(bsk, lastActivityAt.Add(24*time.Hour)))
+	})
+}
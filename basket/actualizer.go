@@ -25,3 +25,12 @@ type ReduceInfo interface {
 	Info() string
 	Count() int
 }
+
+// PresentChoiceActualizerItems данные о подарках на выбор. Интерфейс является необязательным расширением
+// ActualizerItems: если акция предлагает выбрать один подарок из нескольких, актуализатор возвращает все подарки группы
+// как обычные подарки, а через данный интерфейс сообщает, какие из них являются взаимозаменяемыми.
+type PresentChoiceActualizerItems interface {
+	// FindPresentChoices возвращает идентификаторы подарков, из которых можно выбрать один для родительской позиции.
+	// Если выбора нет, возвращается пустой список.
+	FindPresentChoices(parent *basket_item.Item) []basket_item.ItemId
+}
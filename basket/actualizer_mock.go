@@ -237,3 +237,40 @@ func (mr *MockReduceInfoMockRecorder) Info() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockReduceInfo)(nil).Info))
 }
+
+// MockPresentChoiceActualizerItems is a mock of PresentChoiceActualizerItems interface.
+type MockPresentChoiceActualizerItems struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresentChoiceActualizerItemsMockRecorder
+}
+
+// MockPresentChoiceActualizerItemsMockRecorder is the mock recorder for MockPresentChoiceActualizerItems.
+type MockPresentChoiceActualizerItemsMockRecorder struct {
+	mock *MockPresentChoiceActualizerItems
+}
+
+// NewMockPresentChoiceActualizerItems creates a new mock instance.
+func NewMockPresentChoiceActualizerItems(ctrl *gomock.Controller) *MockPresentChoiceActualizerItems {
+	mock := &MockPresentChoiceActualizerItems{ctrl: ctrl}
+	mock.recorder = &MockPresentChoiceActualizerItemsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresentChoiceActualizerItems) EXPECT() *MockPresentChoiceActualizerItemsMockRecorder {
+	return m.recorder
+}
+
+// FindPresentChoices mocks base method.
+func (m *MockPresentChoiceActualizerItems) FindPresentChoices(parent *basket_item.Item) []basket_item.ItemId {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPresentChoices", parent)
+	ret0, _ := ret[0].([]basket_item.ItemId)
+	return ret0
+}
+
+// FindPresentChoices indicates an expected call of FindPresentChoices.
+func (mr *MockPresentChoiceActualizerItemsMockRecorder) FindPresentChoices(parent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPresentChoices", reflect.TypeOf((*MockPresentChoiceActualizerItems)(nil).FindPresentChoices), parent)
+}
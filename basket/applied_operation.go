@@ -0,0 +1,153 @@
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+)
+
+// IdempotencyKey ключ идемпотентности операции изменения корзины, передается клиентом. Повтор запроса с тем же ключом
+// (например, после таймаута сети) возвращает результат первого выполнения и не изменяет корзину повторно. Пустой ключ
+// означает, что операция выполняется без проверки повторов
+type IdempotencyKey string
+
+// maxAppliedOperations кол-во последних примененных операций, которые хранятся в корзине. Более старые операции
+// вытесняются, и их повтор выполняется как новая операция
+const maxAppliedOperations = 100
+
+// OperationType операция изменения корзины, выполненная с ключом идемпотентности
+type OperationType string
+
+const (
+	OperationTypeAdd                   OperationType = "add"
+	OperationTypeRemove                OperationType = "remove"
+	OperationTypeConfigurationAdd      OperationType = "configuration_add"
+	OperationTypeConfigurationAssemble OperationType = "configuration_assemble"
+)
+
+//go:generate go run ../tools/msgpackgen -output=applied_operation_msgpack.go -types=AppliedOperation
+
+// AppliedOperation операция, примененная к корзине с ключом идемпотентности, и ее результат
+type AppliedOperation struct {
+	key           IdempotencyKey `msgpackidx:"1,string"`
+	operationType OperationType  `msgpackidx:"2,string"`
+	// уникальные идентификаторы позиций, которые операция вернула
+	uniqIds []basket_item.UniqId `msgpackidx:"3,slice,elem=string"`
+}
+
+func NewAppliedOperation(
+	key IdempotencyKey,
+	operationType OperationType,
+	uniqIds []basket_item.UniqId,
+) *AppliedOperation {
+	return &AppliedOperation{key: key, operationType: operationType, uniqIds: uniqIds}
+}
+
+func (o *AppliedOperation) Key() IdempotencyKey {
+	return o.key
+}
+
+func (o *AppliedOperation) OperationType() OperationType {
+	return o.operationType
+}
+
+func (o *AppliedOperation) UniqIds() []basket_item.UniqId {
+	return o.uniqIds
+}
+
+// AppliedOperation возвращает операцию, примененную к корзине с ключом идемпотентности, или nil, если операции с
+// таким ключом не было или она уже вытеснена
+func (b *BasketData) AppliedOperation(key IdempotencyKey) *AppliedOperation {
+	for _, operation := range b.appliedOperations {
+		if operation.key == key {
+			return operation
+		}
+	}
+
+	return nil
+}
+
+// AppliedOperations возвращает последние примененные операции в порядке их выполнения
+func (b *BasketData) AppliedOperations() []*AppliedOperation {
+	return b.appliedOperations
+}
+
+// addAppliedOperation запоминает примененную операцию, самые старые операции сверх maxAppliedOperations вытесняются
+func (b *BasketData) addAppliedOperation(operation *AppliedOperation) {
+	b.appliedOperations = append(b.appliedOperations, operation)
+	if len(b.appliedOperations) > maxAppliedOperations {
+		b.appliedOperations = b.appliedOperations[len(b.appliedOperations)-maxAppliedOperations:]
+	}
+}
+
+// findAppliedOperation возвращает операцию, уже примененную к корзине с ключом идемпотентности. Если ключ использовался
+// для другой операции, то возвращается ошибка валидации
+func (b *BasketData) findAppliedOperation(
+	key IdempotencyKey,
+	operationType OperationType,
+) (*AppliedOperation, error) {
+	if key == "" {
+		return nil, nil
+	}
+
+	operation := b.AppliedOperation(key)
+	if operation == nil {
+		return nil, nil
+	}
+
+	if operation.operationType != operationType {
+		return nil, internal.NewValidationError(fmt.Errorf(
+			"idempotency key '%s' is already used for operation '%s'", key, operation.operationType,
+		))
+	}
+
+	return operation, nil
+}
+
+// recordAppliedOperation запоминает результат операции, если она выполнялась с ключом идемпотентности
+func (b *BasketData) recordAppliedOperation(
+	key IdempotencyKey,
+	operationType OperationType,
+	items ...*basket_item.Item,
+) {
+	if key == "" {
+		return
+	}
+
+	uniqIds := make([]basket_item.UniqId, 0, len(items))
+	for _, item := range items {
+		if item != nil {
+			uniqIds = append(uniqIds, item.UniqId())
+		}
+	}
+
+	b.addAppliedOperation(NewAppliedOperation(key, operationType, uniqIds))
+}
+
+// appliedItems возвращает позиции, которые вернула примененная операция. Позиции, которых уже нет в корзине (например,
+// их удалили после операции), пропускаются: операция уже выполнена, и ее повтор не должен ни изменять корзину, ни
+// возвращать ошибку. Идентификаторы всех позиций результата остаются в операции
+func (b *BasketData) appliedItems(operation *AppliedOperation) (basket_item.Items, error) {
+	items := make(basket_item.Items, 0, len(operation.uniqIds))
+	for _, uniqId := range operation.uniqIds {
+		item := b.FindOneById(uniqId)
+		if item == nil {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// appliedItem возвращает позицию, которую вернула примененная операция, или nil, если операция позицию не вернула или
+// позиции уже нет в корзине
+func (b *BasketData) appliedItem(operation *AppliedOperation) (*basket_item.Item, error) {
+	items, err := b.appliedItems(operation)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[0], nil
+}
@@ -0,0 +1,72 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+func (o *AppliedOperation) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(3); err != nil {
+		return err
+	}
+
+	if err := e.EncodeString(string(o.key)); err != nil { // 1
+		return err
+	}
+	if err := e.EncodeString(string(o.operationType)); err != nil { // 2
+		return err
+	}
+	if err := e.EncodeArrayLen(len(o.uniqIds)); err != nil { // 3
+		return err
+	}
+	for _, v := range o.uniqIds {
+		if err := e.EncodeString(string(v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *AppliedOperation) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 0, "AppliedOperation array len")
+	}
+
+	if length != 3 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("(basket.AppliedOperation) incorrect len: %d", length), 0, "(basket.AppliedOperation) incorrect len")
+	}
+
+	key, err := d.DecodeString() // 1
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 1, "AppliedOperation key")
+	}
+	o.key = IdempotencyKey(key)
+
+	operationType, err := d.DecodeString() // 2
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 2, "AppliedOperation operationType")
+	}
+	o.operationType = OperationType(operationType)
+
+	uniqIdsLen, err := d.DecodeArrayLen() // 3
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 3, "AppliedOperation uniqIds")
+	}
+	uniqIds := make([]basket_item.UniqId, uniqIdsLen)
+	for j := 0; j < uniqIdsLen; j++ {
+		v, err := d.DecodeString()
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 3, "AppliedOperation uniqIds")
+		}
+		uniqIds[j] = basket_item.UniqId(v)
+	}
+	o.uniqIds = uniqIds
+
+	return nil
+}
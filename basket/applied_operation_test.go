@@ -0,0 +1,104 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"strconv"
+	"testing"
+)
+
+func TestBasketData_AppliedOperation(t *testing.T) {
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	for i := 0; i < maxAppliedOperations+1; i++ {
+		data.recordAppliedOperation(IdempotencyKey(strconv.Itoa(i)), OperationTypeRemove)
+	}
+
+	assert.Len(t, data.AppliedOperations(), maxAppliedOperations)
+	assert.Nil(t, data.AppliedOperation("0"))
+	assert.Equal(t, NewAppliedOperation("1", OperationTypeRemove, []basket_item.UniqId{}), data.AppliedOperation("1"))
+
+	data.recordAppliedOperation("", OperationTypeRemove)
+	assert.Nil(t, data.AppliedOperation(""))
+	assert.Len(t, data.AppliedOperations(), maxAppliedOperations)
+}
+
+func TestBasketData_findAppliedOperation(t *testing.T) {
+	item := basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	data.recordAppliedOperation("key", OperationTypeAdd, item)
+
+	tests := []struct {
+		name          string
+		key           IdempotencyKey
+		operationType OperationType
+		want          *AppliedOperation
+		wantErr       string
+	}{
+		{
+			name:          "empty key",
+			operationType: OperationTypeAdd,
+		},
+		{
+			name:          "new key",
+			key:           "new",
+			operationType: OperationTypeAdd,
+		},
+		{
+			name:          "applied operation",
+			key:           "key",
+			operationType: OperationTypeAdd,
+			want:          NewAppliedOperation("key", OperationTypeAdd, []basket_item.UniqId{item.UniqId()}),
+		},
+		{
+			name:          "key of another operation",
+			key:           "key",
+			operationType: OperationTypeConfigurationAdd,
+			wantErr:       "idempotency key 'key' is already used for operation 'add'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := data.findAppliedOperation(tt.key, tt.operationType)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBasketData_appliedItems(t *testing.T) {
+	first := basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	second := basket_item.NewItem("2", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	_, err := data.Add(first)
+	assert.NoError(t, err)
+	_, err = data.Add(second)
+	assert.NoError(t, err)
+	data.recordAppliedOperation("key", OperationTypeConfigurationAdd, first, second)
+	operation := data.AppliedOperation("key")
+
+	got, err := data.appliedItems(operation)
+	assert.NoError(t, err)
+	assert.Equal(t, basket_item.Items{first, second}, got)
+
+	// удаленные после операции позиции пропускаются, а результат операции не меняется
+	data.Remove(first)
+	got, err = data.appliedItems(operation)
+	assert.NoError(t, err)
+	assert.Equal(t, basket_item.Items{second}, got)
+	assert.Equal(t, []basket_item.UniqId{first.UniqId(), second.UniqId()}, operation.uniqIds)
+
+	data.Remove(second)
+	item, err := data.appliedItem(operation)
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+}
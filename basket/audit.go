@@ -0,0 +1,344 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=audit.go -destination=audit_mock.go -package=basket
+
+// maxAuditTail кол-во последних записей журнала изменений, которые хранятся в самой корзине для быстрого разбора
+// обращений в поддержку. Полный журнал пишется в AuditSink
+const maxAuditTail = 50
+
+// AuditActor инициатор изменения корзины
+type AuditActor string
+
+const (
+	// AuditActorUser корзину изменил пользователь
+	AuditActorUser AuditActor = "user"
+	// AuditActorSystem корзину изменила система, например, при обновлении корзины
+	AuditActorSystem AuditActor = "system"
+)
+
+// AuditOperation изменение позиции корзины
+type AuditOperation string
+
+const (
+	AuditOperationAdd         AuditOperation = "add"
+	AuditOperationRemove      AuditOperation = "remove"
+	AuditOperationChangeCount AuditOperation = "change_count"
+	AuditOperationChangePrice AuditOperation = "change_price"
+)
+
+// AuditReason причина изменения позиции корзины
+type AuditReason string
+
+const (
+	// AuditReasonUserRequest позицию изменили по запросу пользователя
+	AuditReasonUserRequest AuditReason = "user_request"
+	// AuditReasonRefresh позицию изменили при обновлении корзины по данным БД
+	AuditReasonRefresh AuditReason = "refresh"
+	// AuditReasonNotInCatalog позиции нет в каталоге выбранного региона
+	AuditReasonNotInCatalog AuditReason = "not_in_catalog"
+	// AuditReasonNotAllowedForUser позиция недоступна для текущего типа пользователя (b2c/b2b)
+	AuditReasonNotAllowedForUser AuditReason = "not_allowed_for_user"
+	// AuditReasonReplaced позицию заменили другой позицией: аналогичной услугой субподряда или позицией, которая может
+	// быть только одна в корзине или у родителя
+	AuditReasonReplaced AuditReason = "replaced"
+	// AuditReasonOrphan дочерняя позиция потеряла родительскую позицию
+	AuditReasonOrphan AuditReason = "orphan"
+	// AuditReasonZeroCount у позиции не осталось кол-ва
+	AuditReasonZeroCount AuditReason = "zero_count"
+	// AuditReasonMoveToConfiguration позицию переместили в конфигурацию
+	AuditReasonMoveToConfiguration AuditReason = "move_to_configuration"
+	// AuditReasonMoveFromConfiguration позицию переместили из конфигурации
+	AuditReasonMoveFromConfiguration AuditReason = "move_from_configuration"
+)
+
+//go:generate go run ../tools/msgpackgen -output=audit_msgpack.go -types=AuditEntry
+
+// AuditEntry запись журнала изменений корзины: кто, как и почему изменил позицию. У добавленной позиции кол-во и цена
+// до изменения нулевые, у удаленной - нулевые кол-во и цена после изменения
+type AuditEntry struct {
+	at          time.Time          `msgpackidx:"1,unixnano"`
+	actor       AuditActor         `msgpackidx:"2,string"`
+	operation   AuditOperation     `msgpackidx:"3,string"`
+	uniqId      basket_item.UniqId `msgpackidx:"4,string"`
+	itemId      basket_item.ItemId `msgpackidx:"5,string"`
+	countBefore int                `msgpackidx:"6,int"`
+	countAfter  int                `msgpackidx:"7,int"`
+	priceBefore int                `msgpackidx:"8,int"`
+	priceAfter  int                `msgpackidx:"9,int"`
+	reason      AuditReason        `msgpackidx:"10,string"`
+}
+
+func NewAuditEntry(
+	at time.Time,
+	actor AuditActor,
+	operation AuditOperation,
+	uniqId basket_item.UniqId,
+	itemId basket_item.ItemId,
+	countBefore int,
+	countAfter int,
+	priceBefore int,
+	priceAfter int,
+	reason AuditReason,
+) *AuditEntry {
+	return &AuditEntry{
+		at:          at,
+		actor:       actor,
+		operation:   operation,
+		uniqId:      uniqId,
+		itemId:      itemId,
+		countBefore: countBefore,
+		countAfter:  countAfter,
+		priceBefore: priceBefore,
+		priceAfter:  priceAfter,
+		reason:      reason,
+	}
+}
+
+func (e *AuditEntry) At() time.Time {
+	return e.at
+}
+
+func (e *AuditEntry) Actor() AuditActor {
+	return e.actor
+}
+
+func (e *AuditEntry) Operation() AuditOperation {
+	return e.operation
+}
+
+func (e *AuditEntry) UniqId() basket_item.UniqId {
+	return e.uniqId
+}
+
+func (e *AuditEntry) ItemId() basket_item.ItemId {
+	return e.itemId
+}
+
+func (e *AuditEntry) CountBefore() int {
+	return e.countBefore
+}
+
+func (e *AuditEntry) CountAfter() int {
+	return e.countAfter
+}
+
+func (e *AuditEntry) PriceBefore() int {
+	return e.priceBefore
+}
+
+func (e *AuditEntry) PriceAfter() int {
+	return e.priceAfter
+}
+
+func (e *AuditEntry) Reason() AuditReason {
+	return e.reason
+}
+
+func (e *AuditEntry) String() string {
+	return fmt.Sprintf(
+		"%s %s %s %s(%s): count %d -> %d, price %d -> %d, reason %s",
+		e.at.Format(time.RFC3339Nano), e.actor, e.operation, e.itemId, e.uniqId,
+		e.countBefore, e.countAfter, e.priceBefore, e.priceAfter, e.reason,
+	)
+}
+
+// auditScope инициатор и причина изменений, которые корзина выполняет в данный момент
+type auditScope struct {
+	actor  AuditActor
+	reason AuditReason
+}
+
+// AuditTail возвращает последние записи журнала изменений корзины в порядке их появления
+func (b *BasketData) AuditTail() []*AuditEntry {
+	return b.auditTail
+}
+
+// PendingAuditEntries возвращает записи журнала изменений, которые еще не записаны в AuditSink
+func (b *BasketData) PendingAuditEntries() []*AuditEntry {
+	return b.pendingAuditEntries
+}
+
+// withAudit задает инициатора и причину последующих изменений корзины. Возвращаемая функция восстанавливает
+// предыдущие значения, пустые actor и reason оставляют текущие:
+//
+//	defer b.withAudit(AuditActorSystem, AuditReasonRefresh)()
+func (b *BasketData) withAudit(actor AuditActor, reason AuditReason) (restore func()) {
+	previous := b.auditScope
+	if actor != "" {
+		b.auditScope.actor = actor
+	}
+
+	if reason != "" {
+		b.auditScope.reason = reason
+	}
+
+	return func() {
+		b.auditScope = previous
+	}
+}
+
+// audit добавляет запись в журнал изменений корзины. Если инициатор и причина не заданы через withAudit, то
+// изменение считается выполненным по запросу пользователя
+func (b *BasketData) audit(
+	operation AuditOperation,
+	item *basket_item.Item,
+	countBefore int,
+	priceBefore int,
+) {
+	scope := b.auditScope
+	if scope.actor == "" {
+		scope.actor = AuditActorUser
+	}
+
+	if scope.reason == "" {
+		scope.reason = AuditReasonUserRequest
+	}
+
+	countAfter, priceAfter := item.Count(), item.Price()
+	if operation == AuditOperationRemove {
+		countAfter, priceAfter = 0, 0
+	}
+
+	entry := NewAuditEntry(
+		time.Now().UTC(),
+		scope.actor,
+		operation,
+		item.UniqId(),
+		item.ItemId(),
+		countBefore,
+		countAfter,
+		priceBefore,
+		priceAfter,
+		scope.reason,
+	)
+
+	b.auditMx.Lock()
+	defer b.auditMx.Unlock()
+
+	b.pendingAuditEntries = append(b.pendingAuditEntries, entry)
+	b.auditTail = append(b.auditTail, entry)
+	if len(b.auditTail) > maxAuditTail {
+		b.auditTail = b.auditTail[len(b.auditTail)-maxAuditTail:]
+	}
+}
+
+// auditCountChange добавляет в журнал изменение кол-ва позиции, если кол-во действительно изменилось
+func (b *BasketData) auditCountChange(item *basket_item.Item, countBefore int) {
+	if item.Count() == countBefore {
+		return
+	}
+
+	b.audit(AuditOperationChangeCount, item, countBefore, item.Price())
+}
+
+// auditPriceChange добавляет в журнал изменение цены позиции, если цена действительно изменилась
+func (b *BasketData) auditPriceChange(item *basket_item.Item, priceBefore int) {
+	if item.Price() == priceBefore {
+		return
+	}
+
+	b.audit(AuditOperationChangePrice, item, item.Count(), priceBefore)
+}
+
+// AuditSink получатель журнала изменений корзин (лог, шина событий, хранилище для поддержки)
+type AuditSink interface {
+	// Write записывает записи журнала изменений корзины в порядке их появления
+	Write(ctx context.Context, id BasketId, entries []*AuditEntry) error
+}
+
+// AuditBasketRepository хранилище корзин, которое после успешного сохранения корзины записывает накопленные записи
+// журнала изменений в AuditSink. Записи журнала попадают в AuditSink, только если сами изменения сохранены
+type AuditBasketRepository struct {
+	BasketRepository
+	sink AuditSink
+}
+
+func NewAuditBasketRepository(repository BasketRepository, sink AuditSink) *AuditBasketRepository {
+	return &AuditBasketRepository{BasketRepository: repository, sink: sink}
+}
+
+// Save сохраняет данные корзины и записывает журнал ее изменений. Если журнал записать не удалось, то корзина
+// остается сохраненной с новой версией, записи остаются в BasketData.PendingAuditEntries до следующего сохранения, а
+// возвращается AuditWriteError. Повторять сохранение после такой ошибки не нужно
+func (r *AuditBasketRepository) Save(
+	ctx context.Context,
+	id BasketId,
+	data *BasketData,
+	expectedVersion int64,
+) error {
+	err := r.BasketRepository.Save(ctx, id, data, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	if len(data.pendingAuditEntries) == 0 {
+		return nil
+	}
+
+	err = r.sink.Write(ctx, id, data.pendingAuditEntries)
+	if err != nil {
+		return NewAuditWriteError(id, err)
+	}
+
+	data.pendingAuditEntries = nil
+
+	return nil
+}
+
+// AuditWriteError ошибка записи журнала изменений уже сохраненной корзины. В отличие от остальных ошибок Save,
+// корзина при этой ошибке сохранена, поэтому вызывающий код может только залогировать ее
+type AuditWriteError struct {
+	id  BasketId
+	err error
+}
+
+func NewAuditWriteError(id BasketId, err error) *AuditWriteError {
+	return &AuditWriteError{id: id, err: err}
+}
+
+func (e *AuditWriteError) Error() string {
+	return fmt.Sprintf("basket '%s' is saved, but audit entries can't be written: %s", e.id, e.err)
+}
+
+func (e *AuditWriteError) Unwrap() error {
+	return e.err
+}
+
+func (e *AuditWriteError) Id() BasketId {
+	return e.id
+}
+
+// InMemoryAuditSink журнал изменений корзин в памяти
+type InMemoryAuditSink struct {
+	mx      sync.Mutex
+	entries map[BasketId][]*AuditEntry
+}
+
+func NewInMemoryAuditSink() *InMemoryAuditSink {
+	return &InMemoryAuditSink{entries: make(map[BasketId][]*AuditEntry)}
+}
+
+func (s *InMemoryAuditSink) Write(_ context.Context, id BasketId, entries []*AuditEntry) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	s.entries[id] = append(s.entries[id], entries...)
+
+	return nil
+}
+
+// Entries возвращает все записанные записи журнала изменений корзины
+func (s *InMemoryAuditSink) Entries(id BasketId) []*AuditEntry {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	return append([]*AuditEntry(nil), s.entries[id]...)
+}
@@ -0,0 +1,129 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"time"
+)
+
+func (a *AuditEntry) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(10); err != nil {
+		return err
+	}
+
+	var at int64
+	if !a.at.IsZero() {
+		at = a.at.UnixNano()
+	}
+	if err := e.EncodeInt64(at); err != nil { // 1
+		return err
+	}
+	if err := e.EncodeString(string(a.actor)); err != nil { // 2
+		return err
+	}
+	if err := e.EncodeString(string(a.operation)); err != nil { // 3
+		return err
+	}
+	if err := e.EncodeString(string(a.uniqId)); err != nil { // 4
+		return err
+	}
+	if err := e.EncodeString(string(a.itemId)); err != nil { // 5
+		return err
+	}
+	if err := e.EncodeInt(a.countBefore); err != nil { // 6
+		return err
+	}
+	if err := e.EncodeInt(a.countAfter); err != nil { // 7
+		return err
+	}
+	if err := e.EncodeInt(a.priceBefore); err != nil { // 8
+		return err
+	}
+	if err := e.EncodeInt(a.priceAfter); err != nil { // 9
+		return err
+	}
+	if err := e.EncodeString(string(a.reason)); err != nil { // 10
+		return err
+	}
+
+	return nil
+}
+
+func (a *AuditEntry) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 0, "AuditEntry array len")
+	}
+
+	if length != 10 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("(basket.AuditEntry) incorrect len: %d", length), 0, "(basket.AuditEntry) incorrect len")
+	}
+
+	at, err := d.DecodeInt64() // 1
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 1, "AuditEntry at")
+	}
+	if at != 0 {
+		a.at = time.Unix(0, at).UTC()
+	}
+
+	actor, err := d.DecodeString() // 2
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 2, "AuditEntry actor")
+	}
+	a.actor = AuditActor(actor)
+
+	operation, err := d.DecodeString() // 3
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 3, "AuditEntry operation")
+	}
+	a.operation = AuditOperation(operation)
+
+	uniqId, err := d.DecodeString() // 4
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 4, "AuditEntry uniqId")
+	}
+	a.uniqId = basket_item.UniqId(uniqId)
+
+	itemId, err := d.DecodeString() // 5
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 5, "AuditEntry itemId")
+	}
+	a.itemId = basket_item.ItemId(itemId)
+
+	countBefore, err := d.DecodeInt() // 6
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 6, "AuditEntry countBefore")
+	}
+	a.countBefore = countBefore
+
+	countAfter, err := d.DecodeInt() // 7
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 7, "AuditEntry countAfter")
+	}
+	a.countAfter = countAfter
+
+	priceBefore, err := d.DecodeInt() // 8
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 8, "AuditEntry priceBefore")
+	}
+	a.priceBefore = priceBefore
+
+	priceAfter, err := d.DecodeInt() // 9
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 9, "AuditEntry priceAfter")
+	}
+	a.priceAfter = priceAfter
+
+	reason, err := d.DecodeString() // 10
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 10, "AuditEntry reason")
+	}
+	a.reason = AuditReason(reason)
+
+	return nil
+}
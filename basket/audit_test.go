@@ -0,0 +1,192 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestBasketData_Audit(t *testing.T) {
+	newItem := func(itemId basket_item.ItemId, itemType basket_item.Type, count int, price int) *basket_item.Item {
+		item := basket_item.NewItem(itemId, itemType, "name", "", count, price, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+		item.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+
+		return item
+	}
+
+	t.Run("add and remove", func(t *testing.T) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		product, err := data.Add(newItem("1", basket_item.TypeProduct, 2, 100))
+		require.NoError(t, err)
+		_, err = data.Add(newItem("1", basket_item.TypeProduct, 1, 100))
+		require.NoError(t, err)
+		insurance := newItem("2", basket_item.TypeInsuranceServiceForProduct, 1, 10)
+		require.NoError(t, product.AddChild(insurance))
+		_, err = data.Add(insurance)
+		require.NoError(t, err)
+
+		data.Remove(product)
+
+		assert.Equal(t, []*AuditEntry{
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationAdd, product.UniqId(), "1", 0, 2, 0, 100,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationChangeCount, product.UniqId(), "1", 2, 3, 100, 100,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationAdd, insurance.UniqId(), "2", 0, 1, 0, 10,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationRemove, insurance.UniqId(), "2", 1, 0, 10, 0,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationRemove, product.UniqId(), "1", 3, 0, 100, 0,
+				AuditReasonUserRequest),
+		}, withoutAuditTime(data.AuditTail()))
+		assert.Equal(t, data.AuditTail(), data.PendingAuditEntries())
+	})
+
+	t.Run("only one position replacement by system", func(t *testing.T) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		previous, err := data.Add(newItem("1", basket_item.TypePropertyInsurance, 1, 500))
+		require.NoError(t, err)
+
+		restore := data.withAudit(AuditActorSystem, AuditReasonRefresh)
+		replacement, err := data.Add(newItem("2", basket_item.TypePropertyInsurance, 1, 700))
+		require.NoError(t, err)
+		restore()
+		data.Clear()
+
+		assert.Equal(t, []*AuditEntry{
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationAdd, previous.UniqId(), "1", 0, 1, 0, 500,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorSystem, AuditOperationRemove, previous.UniqId(), "1", 1, 0, 500, 0,
+				AuditReasonReplaced),
+			NewAuditEntry(time.Time{}, AuditActorSystem, AuditOperationAdd, replacement.UniqId(), "2", 0, 1, 0, 700,
+				AuditReasonRefresh),
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationRemove, replacement.UniqId(), "2", 1, 0, 700, 0,
+				AuditReasonUserRequest),
+		}, withoutAuditTime(data.AuditTail()))
+	})
+
+	t.Run("price change by system", func(t *testing.T) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		product, err := data.Add(newItem("1", basket_item.TypeProduct, 2, 100))
+		require.NoError(t, err)
+
+		restore := data.withAudit(AuditActorSystem, AuditReasonRefresh)
+		product.SetPrice(120)
+		data.auditPriceChange(product, 100)
+		// цена не изменилась, запись в журнал не добавляется
+		data.auditPriceChange(product, 120)
+		restore()
+
+		assert.Equal(t, []*AuditEntry{
+			NewAuditEntry(time.Time{}, AuditActorUser, AuditOperationAdd, product.UniqId(), "1", 0, 2, 0, 100,
+				AuditReasonUserRequest),
+			NewAuditEntry(time.Time{}, AuditActorSystem, AuditOperationChangePrice, product.UniqId(), "1", 2, 2, 100, 120,
+				AuditReasonRefresh),
+		}, withoutAuditTime(data.AuditTail()))
+	})
+
+	t.Run("bounded tail", func(t *testing.T) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		for i := 0; i <= maxAuditTail; i++ {
+			_, err := data.Add(newItem(basket_item.ItemId(strconv.Itoa(i)), basket_item.TypeProduct, 1, 100))
+			require.NoError(t, err)
+		}
+
+		require.Len(t, data.AuditTail(), maxAuditTail)
+		assert.Equal(t, basket_item.ItemId("1"), data.AuditTail()[0].ItemId())
+		assert.Len(t, data.PendingAuditEntries(), maxAuditTail+1)
+	})
+
+	t.Run("stored tail", func(t *testing.T) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		_, err := data.Add(newItem("1", basket_item.TypeProduct, 1, 100))
+		require.NoError(t, err)
+
+		encoded, err := msgpack.Marshal(data)
+		require.NoError(t, err)
+		decoded := &BasketData{}
+		require.NoError(t, msgpack.Unmarshal(encoded, decoded))
+
+		assert.Equal(t, data.AuditTail(), decoded.AuditTail())
+		assert.Empty(t, decoded.PendingAuditEntries())
+	})
+}
+
+func TestAuditBasketRepository(t *testing.T) {
+	ctx := context.Background()
+	newData := func(t *testing.T) *BasketData {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		_, err := data.Add(basket_item.NewItem("1", basket_item.TypeProduct, "name", "", 1, 100, 0, "msk_cl",
+			catalog_types.PriceColumnRetail))
+		require.NoError(t, err)
+
+		return data
+	}
+
+	t.Run("entries are written after save", func(t *testing.T) {
+		sink := NewInMemoryAuditSink()
+		repository := NewAuditBasketRepository(NewInMemoryBasketRepository(), sink)
+		data := newData(t)
+		entries := data.PendingAuditEntries()
+
+		require.NoError(t, repository.Save(ctx, "basket", data, 0))
+		assert.Equal(t, entries, sink.Entries("basket"))
+		assert.Empty(t, data.PendingAuditEntries())
+
+		require.NoError(t, repository.Save(ctx, "basket", data, data.Version()))
+		assert.Len(t, sink.Entries("basket"), 1)
+	})
+
+	t.Run("entries are not written on conflict", func(t *testing.T) {
+		sink := NewInMemoryAuditSink()
+		repository := NewAuditBasketRepository(NewInMemoryBasketRepository(), sink)
+		require.NoError(t, repository.Save(ctx, "basket", NewBasketData("msk_cl", catalog_types.PriceColumnRetail,
+			"msk"), 0))
+		data := newData(t)
+
+		err := repository.Save(ctx, "basket", data, 0)
+
+		assert.EqualError(t, err, "basket 'basket' version conflict: expected version 0, actual version 1")
+		assert.Empty(t, sink.Entries("basket"))
+		assert.Len(t, data.PendingAuditEntries(), 1)
+	})
+
+	t.Run("sink error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		sink := NewMockAuditSink(ctrl)
+		repository := NewAuditBasketRepository(NewInMemoryBasketRepository(), sink)
+		data := newData(t)
+		sink.EXPECT().Write(ctx, BasketId("basket"), data.PendingAuditEntries()).Return(errors.New("unavailable"))
+
+		err := repository.Save(ctx, "basket", data, 0)
+
+		assert.EqualError(t, err, "basket 'basket' is saved, but audit entries can't be written: unavailable")
+		var auditWriteErr *AuditWriteError
+		if assert.True(t, errors.As(err, &auditWriteErr)) {
+			assert.Equal(t, BasketId("basket"), auditWriteErr.Id())
+		}
+		assert.Equal(t, int64(1), data.Version())
+		assert.Len(t, data.PendingAuditEntries(), 1)
+	})
+}
+
+// withoutAuditTime копирует записи журнала без времени, чтобы сравнивать их с ожидаемыми
+func withoutAuditTime(entries []*AuditEntry) []*AuditEntry {
+	result := make([]*AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		withoutTime := *entry
+		withoutTime.at = time.Time{}
+		result = append(result, &withoutTime)
+	}
+
+	return result
+}
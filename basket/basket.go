@@ -9,7 +9,6 @@ import (
 	"go.citilink.cloud/catalog_types"
 	"go.citilink.cloud/citizap"
 	citizap_factory "go.citilink.cloud/citizap/factory"
-	database "go.citilink.cloud/libdatabase"
 	"go.citilink.cloud/order/internal"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	"go.citilink.cloud/order/internal/order/bonus/bonuses_for_payment"
@@ -20,12 +19,14 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 type RefresherBasket interface {
 	SpaceId() store_types.SpaceId
 	AddInfo(infos ...*Info)
-	Remove(item *basket_item.Item, force bool) error
+	RemoveBySystem(item *basket_item.Item, reason AuditReason) error
+	ChangePrice(item *basket_item.Item, price int)
 	User() *userv1.User
 	Find(finder Finder) basket_item.Items
 	FindOneById(id basket_item.UniqId) *basket_item.Item
@@ -47,10 +48,11 @@ func NewBasket(
 	productClient productv1.ProductAPIClient,
 	itemRefresher itemRefresher,
 	loggerFactory citizap_factory.Factory,
-	db database.DB,
+	confRegistry ConfigurationRegistry,
 	mrkOptions *markingOptions,
 	subcontractOptions *subcontractServiceChangeOptions,
 	bonusesForPaymentsCalculator *bonuses_for_payment.BonusesForPaymentAgent,
+	confOptions *ConfigurationOptions,
 ) *Basket {
 	basket := &Basket{
 		data:          basketData,
@@ -69,7 +71,7 @@ func NewBasket(
 		bonusAgent: bonusesForPaymentsCalculator,
 	}
 
-	basket.configuration = NewConfiguration(basket, productClient, db)
+	basket.configuration = NewConfiguration(basket, productClient, confRegistry, confOptions)
 
 	// именно в данном месте мы отслеживаем изменения у пользователя. Это необходимо в связи с тем, что сам
 	// заказ/корзина никак не могут отслеживать изменения пользователя, хотя у него в любой момент может измениться
@@ -129,6 +131,8 @@ type Basket struct {
 	*markingOptions
 	*subcontractServiceChangeOptions
 	bonusAgent *bonuses_for_payment.BonusesForPaymentAgent
+	// реестр сценариев внедрения сбоев, которым оборачиваются данные актуализатора при обновлении корзины
+	faultScenarios *FaultScenarioRegistry
 }
 
 // Add добавляет позицию в корзину. Данный метод сделает всю работу за вас, нужно только передать необходимые параметры.
@@ -140,12 +144,22 @@ func (b *Basket) Add(
 	parentUniqId basket_item.UniqId,
 	count int,
 	ignoreFairPrice bool,
+	idempotencyKey IdempotencyKey,
 ) (*basket_item.Item, error) {
+	operation, err := b.data.findAppliedOperation(idempotencyKey, OperationTypeAdd)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation != nil {
+		return b.data.appliedItem(operation)
+	}
+
 	if itemId == "" {
 		return nil, internal.NewValidationError(errors.New("itemId is empty"))
 	}
 
-	err := itemType.Validate()
+	err = itemType.Validate()
 	if err != nil {
 		return nil, internal.NewValidationError(errors.New("itemType invalid"))
 	}
@@ -193,7 +207,7 @@ func (b *Basket) Add(
 	}
 
 	item, err := b.itemFactory.Create(
-		ctx,
+		withFaultScenarios(ctx, b.data.faultScenarios),
 		itemId,
 		b.SpaceId(),
 		itemType,
@@ -207,7 +221,14 @@ func (b *Basket) Add(
 		return nil, fmt.Errorf("can't create item with item factory: %w", err)
 	}
 
-	return b.AddItem(item)
+	addedItem, err := b.AddItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	b.data.recordAppliedOperation(idempotencyKey, OperationTypeAdd, addedItem)
+
+	return addedItem, nil
 }
 
 func (b *Basket) BonusesForPayment(ctx context.Context) (*bonuses_for_payment.BonusesForPayment, error) {
@@ -235,14 +256,158 @@ func (b *Basket) AddItem(item *basket_item.Item) (*basket_item.Item, error) {
 	return addedItem, nil
 }
 
+// ChoosePresent выбирает подарок из группы подарков на выбор родительской позиции. Ранее выбранный подарок из этой
+// группы удаляется из корзины, а вместо него добавляется выбранный, таким образом у родительской позиции всегда
+// находится только один подарок из группы.
+func (b *Basket) ChoosePresent(
+	ctx context.Context,
+	parentUniqId basket_item.UniqId,
+	itemId basket_item.ItemId,
+) (*basket_item.Item, error) {
+	parentItem := b.data.FindOneById(parentUniqId)
+	if parentItem == nil {
+		return nil, internal.NewNotFoundError(fmt.Errorf("parent item '%s' not found in basket", parentUniqId))
+	}
+
+	group := parentItem.PresentChoiceGroup()
+	if group == nil {
+		return nil, internal.NewLogicError(fmt.Errorf("item '%s' has no present choice", parentUniqId))
+	}
+
+	if !group.HasCandidate(itemId) {
+		return nil, internal.NewValidationError(fmt.Errorf("present '%s' can't be chosen for item '%s'", itemId, parentUniqId))
+	}
+
+	presents := b.findPresentsOfChoiceGroup(parentItem)
+	for _, present := range presents {
+		if present.ItemId() == itemId {
+			return present, nil
+		}
+	}
+
+	response, err := b.productClient.FindFull(
+		withFaultScenarios(ctx, b.data.faultScenarios),
+		&productv1.FindFullRequest{Ids: []string{string(itemId)}, SpaceId: string(b.SpaceId())},
+	)
+	if err != nil {
+		return nil, internal.NewCatalogError(
+			fmt.Errorf("can't get present '%s' from catalog: %w", itemId, err),
+			b.SpaceId(),
+		)
+	}
+
+	if len(response.GetInfos()) == 0 {
+		return nil, internal.NewNotFoundError(fmt.Errorf("present '%s' not found in region '%s'", itemId, b.SpaceId()))
+	}
+	presentInfo := response.GetInfos()[0]
+
+	// кол-во подарков контролирует БД, поэтому до очередного обновления корзины сохраняем кол-во предыдущего подарка
+	count := 1
+	for _, previousPresent := range presents {
+		count = previousPresent.Count()
+	}
+
+	present := basket_item.NewItem(
+		itemId,
+		basket_item.TypePresent,
+		presentInfo.GetRegional().GetName(),
+		presentInfo.GetRegional().GetImageName(),
+		count,
+		0,
+		0,
+		b.SpaceId(),
+		b.PriceColumn(),
+	)
+	err = parentItem.AddChild(present)
+	if err != nil {
+		return nil, fmt.Errorf("can't add present to item: %w", err)
+	}
+
+	// предыдущие подарки удаляются только после того, как новый подарок добавлен, чтобы при ошибке выбор не менялся
+	addedPresent, err := b.data.Add(present)
+	if err != nil {
+		return nil, fmt.Errorf("can't add present to basket: %w", err)
+	}
+
+	err = group.Choose(itemId)
+	if err != nil {
+		b.data.Remove(addedPresent)
+		return nil, internal.NewValidationError(err)
+	}
+
+	for _, previousPresent := range presents {
+		b.data.Remove(previousPresent)
+	}
+
+	return addedPresent, nil
+}
+
+// findPresentsOfChoiceGroup находит подарки родительской позиции, которые относятся к ее группе подарков на выбор
+func (b *Basket) findPresentsOfChoiceGroup(parentItem *basket_item.Item) basket_item.Items {
+	group := parentItem.PresentChoiceGroup()
+	if group == nil {
+		return nil
+	}
+
+	var presents basket_item.Items
+	for _, child := range b.data.ChildrenOf(parentItem) {
+		if child.Type() != basket_item.TypePresent {
+			continue
+		}
+
+		if group.HasCandidate(child.ItemId()) || child.ItemId() == group.ChosenItemId() {
+			presents = append(presents, child)
+		}
+	}
+
+	return presents
+}
+
 func (b *Basket) Configuration() *Configuration {
 	return b.configuration
 }
 
-// Remove удаляет позицию. Если в спецификации позиции указано, что нельзя удалять товар, то будет возвращена ошибка,
-// это специальная защита от "плохих" пользователей. Но, если при работе возникает необходимость удаления позиции
-// (например удалить подарок, или какую-нибудь не удаляемую услугу), то нужно передать флаг force=true
-func (b *Basket) Remove(item *basket_item.Item, force bool) error {
+// Remove удаляет позицию вместе с дочерними позициями. Если в спецификации позиции указано, что нельзя удалять товар,
+// то будет возвращена ошибка, это специальная защита от "плохих" пользователей. Но, если при работе возникает
+// необходимость удаления позиции (например удалить подарок, или какую-нибудь не удаляемую услугу), то нужно передать
+// флаг force=true. При повторе операции с тем же ключом идемпотентности корзина не изменяется, поэтому позиция, уже
+// удаленная первым выполнением, может быть nil
+func (b *Basket) Remove(item *basket_item.Item, force bool, idempotencyKey IdempotencyKey) error {
+	operation, err := b.data.findAppliedOperation(idempotencyKey, OperationTypeRemove)
+	if err != nil {
+		return err
+	}
+
+	if operation != nil {
+		return nil
+	}
+
+	err = b.remove(item, force)
+	if err != nil {
+		return err
+	}
+
+	b.data.recordAppliedOperation(idempotencyKey, OperationTypeRemove, item)
+
+	return nil
+}
+
+// RemoveBySystem удаляет позицию вместе с дочерними позициями по решению системы (например, позиции нет в каталоге
+// региона). Удаление попадает в журнал изменений корзины с указанной причиной
+func (b *Basket) RemoveBySystem(item *basket_item.Item, reason AuditReason) error {
+	defer b.data.withAudit(AuditActorSystem, reason)()
+
+	return b.remove(item, false)
+}
+
+// ChangePrice изменяет цену позиции, изменение попадает в журнал изменений корзины
+func (b *Basket) ChangePrice(item *basket_item.Item, price int) {
+	priceBefore := item.Price()
+	item.SetPrice(price)
+	b.data.auditPriceChange(item, priceBefore)
+}
+
+func (b *Basket) remove(item *basket_item.Item, force bool) error {
 	if item.Type() == basket_item.TypeConfiguration {
 		// Просто берем и удаляем конфигурацию (метод удаления в корзине сам удалит рекурсивно всех детей). Не прибегая
 		// к рекурсивному вызову методов удаления детей, в связи с тем, что у позиции в составе конфигурации
@@ -256,8 +421,8 @@ func (b *Basket) Remove(item *basket_item.Item, force bool) error {
 		return internal.NewLogicError(fmt.Errorf("item with type %s can't be deleted", item.Type()))
 	}
 
-	for _, child := range b.data.Find(Finders.ChildrenOf(item)) {
-		err := b.Remove(child, force)
+	for _, child := range b.data.ChildrenOf(item) {
+		err := b.remove(child, force)
 		if err != nil {
 			return fmt.Errorf("can't delete child(%s:%s) of item(%s:%s): %w", child.ItemId(), child.UniqId(),
 				item.ItemId(), item.UniqId(), err)
@@ -269,6 +434,40 @@ func (b *Basket) Remove(item *basket_item.Item, force bool) error {
 	return nil
 }
 
+// Move перемещает позицию верхнего уровня вместе с ее дочерними позициями перед другой позицией верхнего уровня. Если
+// beforeUniqId пустой, то позиция перемещается в конец корзины. Используется для ручной сортировки позиций
+// пользователем
+func (b *Basket) Move(uniqId basket_item.UniqId, beforeUniqId basket_item.UniqId) error {
+	if uniqId == beforeUniqId {
+		return internal.NewValidationError(fmt.Errorf("item '%s' can't be moved before itself", uniqId))
+	}
+
+	item := b.data.FindOneById(uniqId)
+	if item == nil {
+		return internal.NewNotFoundError(fmt.Errorf("item '%s' not found in basket", uniqId))
+	}
+
+	if item.IsChild() {
+		return internal.NewLogicError(fmt.Errorf("item '%s' is child and can't be moved", uniqId))
+	}
+
+	var beforeItem *basket_item.Item
+	if beforeUniqId != "" {
+		beforeItem = b.data.FindOneById(beforeUniqId)
+		if beforeItem == nil {
+			return internal.NewNotFoundError(fmt.Errorf("item '%s' not found in basket", beforeUniqId))
+		}
+
+		if beforeItem.IsChild() {
+			return internal.NewLogicError(fmt.Errorf("item '%s' is child, items can't be moved before it", beforeUniqId))
+		}
+	}
+
+	b.data.move(item, beforeItem)
+
+	return nil
+}
+
 // Fingerprint собирает информацию по всей корзине и выводит это в виде хэша. Данный хэш при сборе так же
 // сортирует позиции заказа по идентификатору позиции, таким образом увеличивается кол-во одинаковых отпечатков у
 // одинаковых корзин
@@ -276,6 +475,11 @@ func (b *Basket) Fingerprint() string {
 	return b.data.Fingerprint()
 }
 
+// ScopedFingerprint отпечаток корзины в указанной области, см. BasketData.ScopedFingerprint
+func (b *Basket) ScopedFingerprint(scope basket_item.FingerprintScope) string {
+	return b.data.ScopedFingerprint(scope)
+}
+
 func (b *Basket) FindOneById(id basket_item.UniqId) *basket_item.Item {
 	return b.data.FindOneById(id)
 }
@@ -345,6 +549,11 @@ func (b *Basket) HasFnsTrackedProducts() bool {
 	return false
 }
 
+// FaultScenarios сценарии внедрения сбоев, активированные для корзины
+func (b *Basket) FaultScenarios() []*FaultScenario {
+	return b.data.FaultScenarios()
+}
+
 func (b *Basket) CancelSimulateProblems() {
 	for _, it := range b.All() {
 		it.CancelSimulateProblems()
@@ -359,6 +568,17 @@ func (b *Basket) SelectedItems() basket_item.Items {
 	return b.data.SelectedItems()
 }
 
+// Tree возвращает позиции корзины в виде дерева. Позиции каждого уровня упорядочиваются переданными сортировками по
+// очереди, если сортировки не переданы, то используется TreeSorters.Default
+func (b *Basket) Tree(sorters ...TreeSorter) []*TreeNode {
+	sorter := TreeSorters.Default()
+	if len(sorters) > 0 {
+		sorter = TreeSorters.Chain(sorters...)
+	}
+
+	return buildTree(b.data.All(), sorter)
+}
+
 func (b *Basket) ToXItems() []*basket_item.XItem {
 	return b.data.ToXItems()
 }
@@ -367,6 +587,11 @@ func (b *Basket) Problems() []*Problem {
 	return b.data.Problems()
 }
 
+// CheckoutReadiness проверяет, можно ли оформить заказ из корзины, см. BasketData.CheckoutReadiness
+func (b *Basket) CheckoutReadiness() *CheckoutReadiness {
+	return b.data.CheckoutReadiness()
+}
+
 func (b *Basket) Infos() []*Info {
 	return b.data.Infos()
 }
@@ -384,6 +609,16 @@ func (b *Basket) CommitInfo(infoId basket_item.InfoId) {
 	}
 }
 
+// PendingInfos возвращает неподтвержденную информацию корзины, появившуюся после since, см. BasketData.PendingInfos
+func (b *Basket) PendingInfos(since time.Time) []*Info {
+	return b.data.PendingInfos(since)
+}
+
+// CommitInfos подтверждает всю информацию корзины и ее позиций с указанными идентификаторами
+func (b *Basket) CommitInfos(ids ...basket_item.InfoId) {
+	b.data.CommitInfos(ids...)
+}
+
 func (b *Basket) CommitAllInfos() {
 	b.data.infos = nil
 }
@@ -412,6 +647,11 @@ func (b *Basket) CommitChanges() {
 	b.data.CommitChanges()
 }
 
+// MarkViewed запоминает время просмотра корзины пользователем, вызывается при каждом показе корзины
+func (b *Basket) MarkViewed() {
+	b.data.MarkViewed()
+}
+
 func (b *Basket) IsChanged() bool {
 	return b.data.IsChanged()
 }
@@ -495,6 +735,22 @@ func (b *Basket) SetSpaceId(spaceId store_types.SpaceId) error {
 }
 
 func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, logger *zap.Logger) error {
+	// изменения, внесенные пользователем до обновления, фиксируются во времени изменения корзины до того, как
+	// система внесет свои
+	if b.data.IsChanged() {
+		b.data.touch()
+	}
+
+	// все изменения позиций при обновлении выполняет система, а не пользователь
+	defer b.data.withAudit(AuditActorSystem, AuditReasonRefresh)()
+
+	// сценарии внедрения сбоев применяют обернутые клиенты каталога и актуализатор при обновлении позиций
+	ctx = withFaultScenarios(ctx, b.data.faultScenarios)
+	actualizerItems = b.faultScenarios.WrapActualizerItems(b, actualizerItems)
+
+	// устаревшая информация не показывается пользователю, даже если он ее не подтвердил
+	b.data.ExpireInfos(basket_item.DefaultInfoExpiryPolicy(), time.Now().UTC())
+
 	// предварительно удаляем все проблемы, потому что они будут пересчитываться по ходу алгоритма
 	for _, item := range b.All() {
 		item.DeleteProblems()
@@ -526,7 +782,9 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 			)
 
 			// удаляем специально из данных, чтобы не нарваться на правила и так далее
+			restore := b.data.withAudit("", AuditReasonOrphan)
 			b.data.Remove(item)
+			restore()
 			continue
 		}
 
@@ -545,7 +803,10 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 				}
 
 				if st.Code() == codes.NotFound {
-					item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "товара с услугой нет в наличии"))
+					item.AddProblem(basket_item.NewLocalizedProblem(
+						basket_item.ProblemNotAvailable,
+						basket_item.MessageProductWithServiceNotAvailable,
+					))
 					continue
 				}
 
@@ -555,7 +816,10 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 			servicesForProduct := response.GetSubcontractServices()
 			subcontractService, ok := servicesForProduct[string(item.ItemId())]
 			if !ok {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга субподряда не предоставляется"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessageSubcontractServiceNotProvided,
+				))
 				continue
 			}
 
@@ -568,18 +832,23 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 					// получаем инфу по подменной услуге
 					replacementSubcontractService, ok := servicesForProduct[subcontractService.LinkedServiceId]
 					if !ok {
-						item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга субподряда не предоставляется"))
+						item.AddProblem(basket_item.NewLocalizedProblem(
+							basket_item.ProblemNotAvailable,
+							basket_item.MessageSubcontractServiceNotProvided,
+						))
 						continue
 					}
 
+					restore := b.data.withAudit("", AuditReasonReplaced)
 					replacesedSubcontractService, err := b.subcontractServiceAdd(ctx, item.ParentUniqId(), item.Count(), replacementSubcontractService)
+					restore()
 					if err != nil {
 						return fmt.Errorf("can't add subcontract service: %w", err)
 					}
 
-					info := basket_item.NewInfo(
+					info := basket_item.NewLocalizedInfo(
 						basket_item.InfoIdPositionChanged,
-						"Услуга субподряда была заменена на аналогичную, доступную для текущего типа пользователя",
+						basket_item.MessageSubcontractServiceReplaced,
 					)
 					info.SetAdditions(&basket_item.InfoAdditions{
 						ChangedItem: basket_item.ChangedItemInfoAdditions{
@@ -605,9 +874,9 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 
 				// если заменили услугу - 2ое оповещение не нужно
 				if !serviceReplaced {
-					deleteInfo := basket_item.NewInfo(
+					deleteInfo := basket_item.NewLocalizedInfo(
 						basket_item.InfoIdPositionRemoved,
-						"Услуга субподряда удалена, в связи с недоступностью для текущего типа пользователя",
+						basket_item.MessageSubcontractServiceRemoved,
 					)
 					deleteInfo.SetAdditions(&basket_item.InfoAdditions{
 						ChangedItem: basket_item.ChangedItemInfoAdditions{
@@ -621,7 +890,13 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 					b.AddInfo(NewInfo(item, deleteInfo))
 				}
 
-				err := b.Remove(item, false)
+				reason := AuditReasonNotAllowedForUser
+				if serviceReplaced {
+					reason = AuditReasonReplaced
+				}
+				restore := b.data.withAudit("", reason)
+				err := b.remove(item, false)
+				restore()
 				if err != nil {
 					return fmt.Errorf("can't remove product: %w", err)
 				}
@@ -637,7 +912,9 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 
 	for _, item := range b.data.All() {
 		if item.Count() == 0 {
+			restore := b.data.withAudit("", AuditReasonZeroCount)
 			b.data.Remove(item)
+			restore()
 			continue
 		}
 
@@ -653,9 +930,9 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 			if item.Spec().IsCountLessOrEqualThenParent() && !item.Spec().IsCountEqualToParentCount() {
 				item.Rules().SetMaxCount(parentItem.Count())
 				if item.Count() > parentItem.Count() {
-					item.AddProblem(basket_item.NewProblem(
+					item.AddProblem(basket_item.NewLocalizedProblem(
 						basket_item.ProblemMaxCountExcess,
-						"Кол-во позиции не должно превышать родительскую"))
+						basket_item.MessageCountExceedsParent))
 				}
 			}
 		}
@@ -665,14 +942,20 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 		if aItem == nil {
 			// Если в корзине есть подарок, но актуализатор его не вернул, то помечаем как "нет в наличии"
 			if item.Type() == basket_item.TypePresent {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "Товар не в наличии"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessagePositionNotAvailable,
+				))
 			} else if !item.Type().IsPartOfConfiguration() {
 				// Проверяем все типы позиций, кроме тех, которые являются частью состава конфигурации, так как
 				// обработка ошибок, связанных с комплектующими конфигурациями берет на себя сама конфигурация и в ней будет указана проблема
 				// непонятно почему эту позицию не вернул актуалазер.
 
 				// Если актуализатор не вернул товар, то выставляем соответствующий problem
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "Позицию невозможно заказать"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessagePositionNotOrderable,
+				))
 			}
 
 			continue
@@ -680,7 +963,9 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 
 		// Актуализируем количество подарков
 		if item.Type() == basket_item.TypePresent {
+			countBefore := item.Count()
 			item.FixCount(aItem.GetCount())
+			b.data.auditCountChange(item, countBefore)
 		}
 
 		// при добавлении позиции типа "Сборка компьютера" для конфигарации мы отправляем только идентификатор
@@ -689,16 +974,19 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 			item.FixName(aItem.GetName())
 			if item.Price() == 0 {
 				// в случае, если цену мы еще не обновляли, то и нечего сообщать, что цена изменилась с 0 на нормальную
-				item.SetPrice(aItem.GetPrice())
+				b.ChangePrice(item, aItem.GetPrice())
 			} else if item.Price() != aItem.GetPrice() {
 				// приходится вот тут вот проверять а изменилась ли цена...
-				info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу сборки конфигурации изменилась")
+				info := basket_item.NewLocalizedInfo(
+					basket_item.InfoIdPriceChanged,
+					basket_item.MessageAssemblyServicePriceChanged,
+				)
 				info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 					From: item.Price(),
 					To:   aItem.GetPrice(),
 				}
 				item.AddInfo(info)
-				item.SetPrice(aItem.GetPrice())
+				b.ChangePrice(item, aItem.GetPrice())
 			}
 		}
 
@@ -713,26 +1001,34 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 			item.FixName(aItem.GetName())
 			if item.Price() != aItem.GetPrice() {
 				// приходится вот тут вот проверять а изменилась ли цена...
-				info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу в конфигурации изменилась")
+				info := basket_item.NewLocalizedInfo(
+					basket_item.InfoIdPriceChanged,
+					basket_item.MessageConfigurationServicePriceChanged,
+				)
 				info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 					From: item.Price(),
 					To:   aItem.GetPrice(),
 				}
 				item.AddInfo(info)
-				item.SetPrice(aItem.GetPrice())
+				b.ChangePrice(item, aItem.GetPrice())
 			}
 		}
 
 		// На данный момент мы не можем сами руководствоваться количеством позиции в составе конфигурации, так что
 		// мы надеемся на правила в БД
 		if item.Type().IsPartOfConfiguration() {
+			countBefore := item.Count()
 			item.FixCount(aItem.GetCount())
+			b.data.auditCountChange(item, countBefore)
 		}
 
 		// из БД нам приходит признак, о том, что такого товара не существует в регионе
 		if aItem.GetNotExist() {
 			// товар не существует в данном регионе
-			item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "Позиция не в наличии"))
+			item.AddProblem(basket_item.NewLocalizedProblem(
+				basket_item.ProblemNotAvailable,
+				basket_item.MessagePositionNotAvailable,
+			))
 		}
 
 		// К нам из БД приходят данные об особых ограничениях на кол-во товаров в один заказ
@@ -751,17 +1047,25 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 		}
 
 		if item.Price() == 0 && item.Type() != basket_item.TypePresent && item.Type() != basket_item.TypeConfiguration {
-			item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "Товар не в наличии"))
+			item.AddProblem(basket_item.NewLocalizedProblem(
+				basket_item.ProblemNotAvailable,
+				basket_item.MessagePositionNotAvailable,
+			))
 		}
 	}
 
 	// добавление заменённой услуги субподряда
 
+	err = b.refreshPresentChoiceGroups(actualizerItems)
+	if err != nil {
+		return err
+	}
+
 	// особая обработка подарков, так как они пока полностью контролируются БД, нам приходится выдумывать, чтобы
 	// следить за тем, появились ли подарки или наоборот убрались
 	presentAItems := actualizerItems.FindByType(basket_item.TypePresent)
 	if len(presentAItems) > 0 {
-		presentItems := b.data.Find(Finders.ByType(basket_item.TypePresent))
+		presentItems := b.data.FindByType(basket_item.TypePresent)
 		if len(presentAItems) > len(presentItems) {
 			for _, aItem := range presentAItems {
 				found := false
@@ -772,6 +1076,11 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 					}
 				}
 
+				// подарки на выбор, которые пользователь не выбрал, в корзину не добавляем
+				if !found && b.isNotChosenPresent(aItem) {
+					continue
+				}
+
 				if !found {
 					addedPresent, err := b.data.Add(
 						basket_item.NewItem(
@@ -796,6 +1105,7 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 						if err != nil {
 							return fmt.Errorf("can`t set parent for present, %w", err)
 						}
+						b.data.reindex(addedPresent)
 					}
 				}
 			}
@@ -809,31 +1119,27 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 
 	// Костыль для правильного подсчета стоимости конфигурации. На данный момент в БД есть баг, связанный с тем, что
 	// при добавлении товара, который является ПО, то к нему добавляется услуга на установку, но вот БД считает это
-	// дело без добавленной услуги.
-	configuration := b.data.Find(Finders.ByType(basket_item.TypeConfiguration)).First()
-	if configuration != nil {
-		confPrice := 0
-		confBonus := 0
-		for _, item := range b.data.All() {
-			if item.Type().IsPartOfConfiguration() {
-				confPrice += item.Cost()
-				confBonus += item.Bonus() * item.Count()
-			}
-		}
+	// дело без добавленной услуги. Стоимость каждой конфигурации считается только по ее составу.
+	for _, configuration := range b.data.FindByType(basket_item.TypeConfiguration) {
+		breakdown := calculateConfiguration(configuration, b.data.ChildrenOfRecursive(configuration))
+		confPrice := breakdown.Price
 
-		configuration.SetBonus(confBonus)
+		configuration.SetBonus(breakdown.Bonus)
 		if configuration.Price() == 0 {
 			// в случае, если цену мы еще не обновляли, то и нечего сообщать, что цена изменилась с 0 на нормальную
-			configuration.SetPrice(confPrice)
+			b.ChangePrice(configuration, confPrice)
 		} else if configuration.Price() != confPrice {
 			// приходится вот тут вот проверять а изменилась ли цена...
-			info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на конфигурацию изменилась")
+			info := basket_item.NewLocalizedInfo(
+				basket_item.InfoIdPriceChanged,
+				basket_item.MessageConfigurationPriceChanged,
+			)
 			info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 				From: configuration.Price(),
 				To:   confPrice,
 			}
 			configuration.AddInfo(info)
-			configuration.SetPrice(confPrice)
+			b.ChangePrice(configuration, confPrice)
 		}
 	}
 
@@ -842,6 +1148,82 @@ func (b *Basket) Refresh(ctx context.Context, actualizerItems ActualizerItems, l
 	return nil
 }
 
+// refreshPresentChoiceGroups актуализирует группы подарков на выбор.
+//
+// Если актуализатор сообщает о подарках на выбор, то список возможных подарков у родительской позиции обновляется.
+// Далее проверяется, что выбранный подарок все еще можно выбрать (иначе выбирается первый доступный) и что у
+// родительской позиции в корзине находится только один подарок из группы.
+func (b *Basket) refreshPresentChoiceGroups(actualizerItems ActualizerItems) error {
+	presentChoices, hasPresentChoices := actualizerItems.(PresentChoiceActualizerItems)
+	for _, item := range b.data.All() {
+		if !item.Spec().CanHaveChild(basket_item.TypePresent) {
+			continue
+		}
+
+		if hasPresentChoices {
+			candidateItemIds := presentChoices.FindPresentChoices(item)
+			if len(candidateItemIds) > 1 {
+				if item.PresentChoiceGroup() == nil {
+					item.SetPresentChoiceGroup(basket_item.NewPresentChoiceGroup(candidateItemIds, ""))
+				} else {
+					item.PresentChoiceGroup().SetCandidateItemIds(candidateItemIds)
+				}
+			} else {
+				item.SetPresentChoiceGroup(nil)
+			}
+		}
+
+		group := item.PresentChoiceGroup()
+		if group == nil {
+			continue
+		}
+
+		if len(group.CandidateItemIds()) == 0 {
+			item.SetPresentChoiceGroup(nil)
+			continue
+		}
+
+		presents := b.findPresentsOfChoiceGroup(item)
+		chosenItemId := group.ChosenItemId()
+		if !group.HasCandidate(chosenItemId) {
+			// выбранный подарок больше не участвует в акции, оставляем подарок, который уже есть в корзине, либо
+			// первый из возможных
+			chosenItemId = group.CandidateItemIds()[0]
+			for _, present := range presents {
+				if group.HasCandidate(present.ItemId()) {
+					chosenItemId = present.ItemId()
+					break
+				}
+			}
+
+			err := group.Choose(chosenItemId)
+			if err != nil {
+				return fmt.Errorf("can't choose present: %w", err)
+			}
+		}
+
+		for _, present := range presents {
+			if present.ItemId() != chosenItemId {
+				b.data.Remove(present)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isNotChosenPresent проверяет, является ли подарок из актуализатора подарком на выбор, который пользователь не выбрал
+func (b *Basket) isNotChosenPresent(aItem ActualizerItem) bool {
+	for _, parentItem := range b.data.Find(Finders.ByItemIds(aItem.GetParentItemId())) {
+		group := parentItem.PresentChoiceGroup()
+		if group != nil && group.HasCandidate(aItem.GetItemId()) && group.ChosenItemId() != aItem.GetItemId() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // fixServiceForConfProduct исправляет отсутствие позиций установки ПО в случае добавления товара, который является ПО
 //
 // при добавлении позиций в состав конфигурации, которые являются программным обеспечением (windows и т.п.), за их
@@ -853,7 +1235,7 @@ func (b *Basket) fixServiceForConfProduct(actualizerItems ActualizerItems) error
 		return nil
 	}
 
-	items := b.data.Find(Finders.ByType(basket_item.TypeConfigurationProductService))
+	items := b.data.FindByType(basket_item.TypeConfigurationProductService)
 	for _, aItem := range aItems {
 		var foundedItem *basket_item.Item
 		for _, item := range items {
@@ -928,6 +1310,7 @@ func (b *Basket) subcontractServiceAdd(
 		parentUniqId,
 		count,
 		false,
+		"",
 	)
 	if err != nil {
 		return nil, fmt.Errorf("can't add subcontractService to basket: %w", err)
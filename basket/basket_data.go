@@ -1,32 +1,68 @@
 package basket
 
 import (
-	"encoding/binary"
 	"fmt"
 	"go.citilink.cloud/catalog_types"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	"go.citilink.cloud/store_types"
-	"hash/fnv"
 	"sort"
 	"strconv"
+	"sync"
+	"time"
 )
 
 type CityId string
 
+//go:generate go run ../tools/msgpackgen -output=basket_data_msgpack.go -types=BasketData
+
+// BasketData данные корзины, сохраняемые в хранилище
+//
+//msgpack:deleted 3 int
+//msgpack:deleted 4 int
+//msgpack:min 2
+//msgpack:after-decode afterDecode
+//msgpack:err 3 BasketData DecodeInt
+//msgpack:err 4 BasketData DecodeInt
+//msgpack:err 6 BasketData array len
+//msgpack:elem-err 6 6 BasketData decode infos
+//msgpack:decode-err 8
+//msgpack:err 9 Basket hasPossibleConfiguration
 type BasketData struct {
 	// Идентификатор региона, относительно которого рассчитывается наличие и цены в корзине
-	spaceId store_types.SpaceId // 1
-	items   basket_item.ItemMap // 2
-	// 3 deleted
-	// 4 deleted
+	spaceId store_types.SpaceId `msgpackidx:"1,string"`
+	items   basket_item.ItemMap `msgpackidx:"2"`
 	// ценовая колонка относительно которой рассчитываются цены в корзине
-	priceColumn       catalog_types.PriceColumn // 5
-	infos             []*Info                   // 6
-	commitFingerprint string                    // 7
-	cityId            CityId                    // 8
+	priceColumn       catalog_types.PriceColumn `msgpackidx:"5,int"`
+	infos             []*Info                   `msgpackidx:"6,slice"`
+	commitFingerprint string                    `msgpackidx:"7,string"`
+	cityId            CityId                    `msgpackidx:"8,string"`
 	// Флаг показывающий можно ли из товаров корзины собрать конфигурацию
-	hasPossibleConfiguration bool // 9
-
+	hasPossibleConfiguration bool `msgpackidx:"9,bool"`
+	// Версия данных корзины в хранилище, увеличивается при каждом сохранении через BasketRepository
+	version int64 `msgpackidx:"10,int64"`
+	// Последние операции, примененные к корзине с ключом идемпотентности
+	appliedOperations []*AppliedOperation `msgpackidx:"11,slice,nilempty"`
+	// Время создания корзины - первого изменения ее данных. У корзин, сохраненных до появления поля, это время
+	// первого изменения после загрузки
+	createdAt time.Time `msgpackidx:"12,unixnano"`
+	// Время последнего изменения данных корзины
+	modifiedAt time.Time `msgpackidx:"13,unixnano"`
+	// Время последнего просмотра корзины пользователем
+	viewedAt time.Time `msgpackidx:"14,unixnano"`
+	// Последние записи журнала изменений корзины
+	auditTail []*AuditEntry `msgpackidx:"15,slice,nilempty"`
+	// Сценарии внедрения сбоев, активированные для корзины через FaultScenarioRegistry
+	faultScenarios []*FaultScenario `msgpackidx:"16,slice,nilempty"`
+
+	// записи журнала изменений, еще не записанные в AuditSink, не сериализуются
+	pendingAuditEntries []*AuditEntry
+	// защищает журнал изменений, в который пишут обновления позиций, выполняемые параллельно
+	auditMx sync.Mutex
+	// инициатор и причина текущих изменений, не сериализуются
+	auditScope auditScope
+
+	// индексы позиций, не сериализуются. Обращаться к ним нужно только через BasketData.index()
+	itemsIndex *basketDataIndex
 }
 
 // NewBasketData создает данные корзины
@@ -46,44 +82,66 @@ func NewBasketData(spaceId store_types.SpaceId, priceColumn catalog_types.PriceC
 // сортирует позиции заказа по идентификатору позиции, таким образом увеличивается кол-во одинаковых отпечатков у
 // одинаковых корзин
 func (b *BasketData) Fingerprint() string {
-	// данная реализация никогда не сможет вернуть ошибку, поэтому они здесь опущены + так же это крайне затрудняет
-	// тестирование, так как для подмены хэша придется городить какую-то фабрику или любую другую решение, которое
-	// только усложнит код.
-	h := fnv.New64a()
-	_, _ = h.Write([]byte(b.SpaceId()))
-	_ = binary.Write(h, binary.LittleEndian, int32(b.PriceColumn()))
-
-	hashes := make([]string, 0)
+	return b.ScopedFingerprint(basket_item.FingerprintScopeFull)
+}
+
+// afterDecode вызывается после декодирования данных корзины. Индексы строятся заново по загруженным позициям, а
+// отпечатки прошлых версий несравнимы с текущими, поэтому считается, что корзина не менялась с момента сохранения
+func (b *BasketData) afterDecode() {
+	b.itemsIndex = nil
+	if b.commitFingerprint != "" && !basket_item.IsCurrentFingerprintVersion(b.commitFingerprint) {
+		b.commitFingerprint = b.Fingerprint()
+	}
+}
+
+// ScopedFingerprint отпечаток корзины в указанной области. В отпечаток входят только позиции, участвующие в области,
+// поэтому изменение остальных позиций его не меняет
+func (b *BasketData) ScopedFingerprint(scope basket_item.FingerprintScope) string {
+	builder := basket_item.NewFingerprintBuilder(scope).
+		Add("space_id", string(b.SpaceId())).
+		Add("price_column", strconv.Itoa(int(b.PriceColumn())))
+	if scope == basket_item.FingerprintScopeDelivery {
+		builder.Add("city_id", string(b.CityId()))
+	}
+
+	hashes := make([]string, 0, len(b.items))
 	for _, item := range b.items {
-		hashes = append(hashes, item.Fingerprint())
+		if item.IsFingerprintIncluded(scope) {
+			hashes = append(hashes, item.ScopedFingerprint(scope))
+		}
 	}
 
 	sort.Strings(hashes)
-	for _, i := range hashes {
-		_, _ = h.Write([]byte(i))
+	for _, itemHash := range hashes {
+		builder.Add("item", itemHash)
 	}
 
-	return strconv.FormatUint(h.Sum64(), 10)
+	return builder.String()
 }
 
 // Add добавляет позицию в корзину и производит набор проверок на основе спецификаций типа и правил самой позиции.
 //
 // Одним из возвращаемых значений является позиция, в большинстве случаев она является той же позицией, что и
 // добавляемая, НО, если производится добавление уже существующей позиции (с одним и тем же идентификатором позиции и
-// родительской позицией), то вернется уже существующая позиция, а не добавляемая.
+// родительской позицией), то вернется уже существующая позиция, а не добавляемая. Исключением являются
+// конфигурации: у всех конфигураций один и тот же идентификатор позиции, но каждая из них является отдельной сборкой.
 func (b *BasketData) Add(item *basket_item.Item) (*basket_item.Item, error) {
-	for _, existItem := range b.items {
-		if existItem.ItemId() == item.ItemId() && existItem.ParentUniqId() == item.ParentUniqId() {
+	for _, existItem := range b.index().childrenOf(item.ParentUniqId()) {
+		if !item.Type().IsConfiguration() &&
+			existItem.ItemId() == item.ItemId() && existItem.ParentUniqId() == item.ParentUniqId() {
 			newCount := existItem.Count() + item.Count()
 			if existItem.Rules().MaxCount() > 0 && newCount > existItem.Rules().MaxCount() {
 				newCount = existItem.Rules().MaxCount()
 			}
 
 			if existItem.Spec().IsCountChangeable() {
+				countBefore := existItem.Count()
 				err := existItem.SetCount(newCount)
 				if err != nil {
 					return nil, fmt.Errorf("can't change count: %w", err)
 				}
+				b.touch()
+				b.auditCountChange(existItem, countBefore)
 			}
 
 			return existItem, nil
@@ -111,33 +169,48 @@ func (b *BasketData) Add(item *basket_item.Item) (*basket_item.Item, error) {
 
 	// если такой тип позиции может быть только один у родителя, тогда удалим уже существующую позицию
 	if parentItem != nil && item.Spec().IsOnlyOnePositionPerParent() {
-		for _, foundedItem := range b.All() {
-			if foundedItem.Type() == item.Type() && foundedItem.ParentUniqId() == parentItem.UniqId() {
+		restore := b.withAudit("", AuditReasonReplaced)
+		for _, foundedItem := range b.ChildrenOf(parentItem) {
+			if foundedItem.Type() == item.Type() {
 				b.Remove(foundedItem)
 			}
 		}
+		restore()
 	}
 
 	// если родительская позиция не выбрана для выкупа - отмечаем ее выбранной обязательно, как и все ее дочерние
 	if parentItem != nil && !parentItem.IsSelected() {
 		parentItem.SetIsSelected(true)
-		for _, bItem := range b.items {
-			if bItem.ParentUniqId() == parentItem.UniqId() {
-				bItem.SetIsSelected(true)
-			}
+		for _, bItem := range b.ChildrenOf(parentItem) {
+			bItem.SetIsSelected(true)
 		}
 	}
 
 	if item.Spec().IsOnlyOnePositionPossible() {
 		// в случае, если добавляется еще одна позиция, которая может быть только одна в корзине, то предыдущая
 		// позиция с этим типом удаляется из корзины и таким образом мы ее как будто"заменяем"
-		itemsOfSameType := b.Find(Finders.ByType(item.Type()))
+		restore := b.withAudit("", AuditReasonReplaced)
+		itemsOfSameType := b.FindByType(item.Type())
 		for _, foundedItem := range itemsOfSameType {
 			b.Remove(foundedItem)
 		}
+		restore()
+	}
+
+	if item.Position() == 0 {
+		item.SetPosition(b.nextPosition())
+	}
+
+	if item.AddedAt().IsZero() {
+		item.SetAddedAt(time.Now().UTC())
 	}
 
+	// индекс берется до изменения позиций, иначе он будет построен заново
+	index := b.index()
 	b.items[item.UniqId()] = item
+	index.add(item)
+	b.touch()
+	b.audit(AuditOperationAdd, item, 0, 0)
 
 	return item, nil
 }
@@ -170,24 +243,136 @@ func (b *BasketData) FindSelected(finder Finder) basket_item.Items {
 	return finder(b.SelectedItems())
 }
 
+// Remove удаляет позицию вместе со всеми ее потомками, удаление каждой из них попадает в журнал изменений
 func (b *BasketData) Remove(item *basket_item.Item) {
-	childrenRecursive := b.Find(Finders.ChildrenOfRecursive(item))
-	for _, child := range childrenRecursive {
+	index := b.index()
+	for _, child := range index.childrenOfRecursive(item.UniqId()) {
 		delete(b.items, child.UniqId())
+		index.remove(child)
+		b.audit(AuditOperationRemove, child, child.Count(), child.Price())
 	}
 
 	delete(b.items, item.UniqId())
+	index.remove(item)
+	b.touch()
+	b.audit(AuditOperationRemove, item, item.Count(), item.Price())
+}
+
+// ChildrenOf возвращает прямых потомков позиции, в отличие от Finders.ChildrenOf не перебирает всю корзину
+func (b *BasketData) ChildrenOf(parent *basket_item.Item) basket_item.Items {
+	if !parent.Spec().CanHaveChildren() {
+		return nil
+	}
+
+	return b.index().childrenOf(parent.UniqId())
+}
+
+// ChildrenOfRecursive возвращает всех потомков позиции, в отличие от Finders.ChildrenOfRecursive не перебирает всю
+// корзину на каждом уровне
+func (b *BasketData) ChildrenOfRecursive(parent *basket_item.Item) basket_item.Items {
+	if !parent.Spec().CanHaveChildren() {
+		return nil
+	}
+
+	return b.index().childrenOfRecursive(parent.UniqId())
+}
+
+// FindByType возвращает позиции переданных типов, в отличие от Finders.ByType не перебирает всю корзину
+func (b *BasketData) FindByType(itemTypes ...basket_item.Type) basket_item.Items {
+	return b.index().ofTypes(itemTypes...)
+}
+
+// reindex обновляет позицию в индексах. Вызывается, если у уже добавленной позиции сменился родитель
+func (b *BasketData) reindex(item *basket_item.Item) {
+	if _, ok := b.items[item.UniqId()]; !ok {
+		return
+	}
+
+	b.index().add(item)
+}
+
+// index возвращает индексы позиций, строя их при первом обращении. Индексы строятся заново, если позиции корзины были
+// изменены в обход BasketData (например, при заполнении корзины в тестах)
+func (b *BasketData) index() *basketDataIndex {
+	if b.itemsIndex == nil || b.itemsIndex.size != len(b.items) {
+		b.itemsIndex = newBasketDataIndex(b.items)
+	}
+
+	return b.itemsIndex
 }
 
+// All возвращает все позиции корзины в порядке, в котором они находятся в корзине
 func (b *BasketData) All() basket_item.Items {
-	return b.items.ToSlice()
+	return b.items.ToSlice().SortByPosition()
 }
 
+// SelectedItems возвращает выбранные для покупки позиции в порядке, в котором они находятся в корзине
 func (b *BasketData) SelectedItems() basket_item.Items {
-	return b.items.ToSliceOnlySelected()
+	return b.items.ToSliceOnlySelected().SortByPosition()
+}
+
+// nextPosition возвращает порядковый номер для новой позиции, она становится последней в корзине
+func (b *BasketData) nextPosition() int {
+	position := 0
+	for _, item := range b.items {
+		if item.Position() > position {
+			position = item.Position()
+		}
+	}
+
+	return position + 1
+}
+
+// move перемещает позицию верхнего уровня вместе с ее дочерними позициями перед другой позицией верхнего уровня, либо
+// в конец корзины, если beforeItem равен nil. Порядковые номера всех позиций корзины при этом пересчитываются
+func (b *BasketData) move(item *basket_item.Item, beforeItem *basket_item.Item) {
+	var roots basket_item.Items
+	for _, root := range b.index().childrenOf("") {
+		if root.UniqId() == item.UniqId() {
+			continue
+		}
+
+		if beforeItem != nil && root.UniqId() == beforeItem.UniqId() {
+			roots = append(roots, item)
+		}
+
+		roots = append(roots, root)
+	}
+
+	if beforeItem == nil {
+		roots = append(roots, item)
+	}
+	b.touch()
+
+	ordered := make(map[basket_item.UniqId]struct{}, len(b.items))
+	position := 0
+	for _, root := range roots {
+		position++
+		root.SetPosition(position)
+		ordered[root.UniqId()] = struct{}{}
+		for _, child := range b.ChildrenOfRecursive(root).SortByPosition() {
+			position++
+			child.SetPosition(position)
+			ordered[child.UniqId()] = struct{}{}
+		}
+	}
+
+	// позиции, которые не связаны с позициями верхнего уровня (например, потерявшие родителя), остаются в конце
+	for _, rest := range b.All() {
+		if _, ok := ordered[rest.UniqId()]; !ok {
+			position++
+			rest.SetPosition(position)
+		}
+	}
 }
 
+// CommitChanges фиксирует текущее состояние корзины. Если корзина изменилась с прошлой фиксации (например, у позиции
+// поменялось кол-во) по инициативе пользователя, то обновляется время изменения корзины
 func (b *BasketData) CommitChanges() {
+	if b.IsChanged() {
+		b.touch()
+	}
+
 	b.commitFingerprint = b.Fingerprint()
 	for _, item := range b.items {
 		item.CommitChanges()
@@ -259,8 +444,67 @@ func (b *BasketData) CityId() CityId {
 	return b.cityId
 }
 
+// CreatedAt время создания корзины. Нулевое, если корзина еще не менялась
+func (b *BasketData) CreatedAt() time.Time {
+	return b.createdAt
+}
+
+// ModifiedAt время последнего изменения данных корзины пользователем
+func (b *BasketData) ModifiedAt() time.Time {
+	return b.modifiedAt
+}
+
+// ViewedAt время последнего просмотра корзины пользователем
+func (b *BasketData) ViewedAt() time.Time {
+	return b.viewedAt
+}
+
+// LastActivityAt время последней активности в корзине: изменения или просмотра. Нулевое, если активности не было с
+// момента появления времени изменения и просмотра в данных корзины
+func (b *BasketData) LastActivityAt() time.Time {
+	lastActivityAt := b.createdAt
+	for _, at := range []time.Time{b.modifiedAt, b.viewedAt} {
+		if at.After(lastActivityAt) {
+			lastActivityAt = at
+		}
+	}
+
+	return lastActivityAt
+}
+
+// MarkViewed запоминает время просмотра корзины пользователем. Просмотр не меняет отпечаток корзины, поэтому
+// IsChanged не учитывает его
+func (b *BasketData) MarkViewed() {
+	b.viewedAt = time.Now().UTC()
+}
+
+// touch запоминает время изменения данных корзины пользователем. Изменения, которые выполняет система (см. withAudit),
+// не считаются активностью пользователя и время изменения не обновляют
+func (b *BasketData) touch() {
+	if b.auditScope.actor == AuditActorSystem {
+		return
+	}
+
+	b.modifiedAt = time.Now().UTC()
+	if b.createdAt.IsZero() {
+		b.createdAt = b.modifiedAt
+	}
+}
+
+// Version версия данных корзины, с которой они были загружены из хранилища или сохранены в него. У данных, которые
+// еще не сохранялись, версия 0
+func (b *BasketData) Version() int64 {
+	return b.version
+}
+
 func (b *BasketData) Clear() {
+	for _, item := range b.All() {
+		b.audit(AuditOperationRemove, item, item.Count(), item.Price())
+	}
+
 	b.items = make(map[basket_item.UniqId]*basket_item.Item)
+	b.itemsIndex = nil
+	b.touch()
 }
 
 func (b *BasketData) Cost() int {
@@ -332,6 +576,75 @@ func (b *BasketData) Infos() []*Info {
 	return infos
 }
 
+// PendingInfos возвращает всю неподтвержденную информацию корзины, появившуюся после since, в порядке появления. В
+// отличие от Infos, возвращается вся история информации позиций, а не только последняя информация по идентификатору.
+// Информация без времени появления возвращается, только если since нулевое
+func (b *BasketData) PendingInfos(since time.Time) []*Info {
+	var infos []*Info
+	isPending := func(info *basket_item.Info) bool {
+		return since.IsZero() || info.CreatedAt().After(since)
+	}
+
+	for _, info := range b.infos {
+		if isPending(info.Info()) {
+			infos = append(infos, info)
+		}
+	}
+
+	for _, item := range b.items {
+		for _, itemInfo := range item.InfoHistory() {
+			if isPending(itemInfo) {
+				infos = append(infos, NewInfo(item, itemInfo))
+			}
+		}
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Info().CreatedAt().Before(infos[j].Info().CreatedAt())
+	})
+
+	return infos
+}
+
+// CommitInfos подтверждает всю информацию корзины и ее позиций с указанными идентификаторами
+func (b *BasketData) CommitInfos(ids ...basket_item.InfoId) {
+	committed := make(map[basket_item.InfoId]bool, len(ids))
+	for _, id := range ids {
+		committed[id] = true
+	}
+
+	b.filterInfos(func(info *Info) bool {
+		return !committed[info.Info().Id()]
+	})
+
+	for _, item := range b.items {
+		item.CommitInfos(ids...)
+	}
+}
+
+// ExpireInfos удаляет информацию корзины и ее позиций, время жизни которой по правилам policy истекло на момент now
+func (b *BasketData) ExpireInfos(policy basket_item.InfoExpiryPolicy, now time.Time) {
+	b.filterInfos(func(info *Info) bool {
+		return !policy.IsExpired(info.Info(), now)
+	})
+
+	for _, item := range b.items {
+		item.ExpireInfos(policy, now)
+	}
+}
+
+// filterInfos оставляет в информации корзины только информацию, для которой keep возвращает true
+func (b *BasketData) filterInfos(keep func(info *Info) bool) {
+	var infos []*Info
+	for _, info := range b.infos {
+		if keep(info) {
+			infos = append(infos, info)
+		}
+	}
+
+	b.infos = infos
+}
+
 func (b *BasketData) AccruedBonus() int {
 	bonus := 0
 	// Начисляемые бонусы рассчитываем только для selected позиций
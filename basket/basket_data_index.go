@@ -0,0 +1,106 @@
+package basket
+
+import (
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+)
+
+// basketDataIndex индексы позиций корзины для поиска дочерних позиций и позиций по типу без перебора всей корзины.
+// Индексы не сериализуются и строятся заново по позициям корзины при первом обращении к ним. Порядковый номер позиции
+// может измениться после индексации, поэтому позиции из индекса сортируются по нему при чтении
+type basketDataIndex struct {
+	// кол-во проиндексированных позиций, по нему определяется, что позиции были изменены в обход BasketData и индекс
+	// нужно построить заново
+	size int
+	// дочерние позиции по идентификатору родительской позиции, позиции без родителя хранятся по пустому идентификатору
+	children map[basket_item.UniqId]basket_item.Items
+	// родительская позиция, под которой позиция была проиндексирована. Нужна для удаления из индекса позиции, у которой
+	// после индексации сменился родитель
+	parents map[basket_item.UniqId]basket_item.UniqId
+	byType  map[basket_item.Type]basket_item.Items
+}
+
+func newBasketDataIndex(items basket_item.ItemMap) *basketDataIndex {
+	index := &basketDataIndex{
+		children: make(map[basket_item.UniqId]basket_item.Items),
+		parents:  make(map[basket_item.UniqId]basket_item.UniqId, len(items)),
+		byType:   make(map[basket_item.Type]basket_item.Items),
+	}
+	for _, item := range items {
+		index.add(item)
+	}
+
+	return index
+}
+
+func (i *basketDataIndex) add(item *basket_item.Item) {
+	if _, ok := i.parents[item.UniqId()]; ok {
+		i.remove(item)
+	}
+
+	i.size++
+	i.parents[item.UniqId()] = item.ParentUniqId()
+	i.children[item.ParentUniqId()] = append(i.children[item.ParentUniqId()], item)
+	i.byType[item.Type()] = append(i.byType[item.Type()], item)
+}
+
+func (i *basketDataIndex) remove(item *basket_item.Item) {
+	parentUniqId, ok := i.parents[item.UniqId()]
+	if !ok {
+		return
+	}
+
+	i.size--
+	delete(i.parents, item.UniqId())
+	i.children[parentUniqId] = withoutItem(i.children[parentUniqId], item.UniqId())
+	if len(i.children[parentUniqId]) == 0 {
+		delete(i.children, parentUniqId)
+	}
+
+	i.byType[item.Type()] = withoutItem(i.byType[item.Type()], item.UniqId())
+	if len(i.byType[item.Type()]) == 0 {
+		delete(i.byType, item.Type())
+	}
+}
+
+// childrenOf возвращает прямых потомков позиции в порядке, в котором они находятся в корзине. Позиции, родитель которых сменился после индексации, пропускаются
+func (i *basketDataIndex) childrenOf(parentUniqId basket_item.UniqId) basket_item.Items {
+	var children basket_item.Items
+	for _, child := range i.children[parentUniqId] {
+		if child.ParentUniqId() == parentUniqId {
+			children = append(children, child)
+		}
+	}
+
+	return children.SortByPosition()
+}
+
+func (i *basketDataIndex) childrenOfRecursive(parentUniqId basket_item.UniqId) basket_item.Items {
+	directChildren := i.childrenOf(parentUniqId)
+	children := make(basket_item.Items, 0, len(directChildren))
+	children = append(children, directChildren...)
+	for _, child := range directChildren {
+		children = append(children, i.childrenOfRecursive(child.UniqId())...)
+	}
+
+	return children
+}
+
+// ofTypes возвращает позиции переданных типов в порядке, в котором они находятся в корзине
+func (i *basketDataIndex) ofTypes(itemTypes ...basket_item.Type) basket_item.Items {
+	var items basket_item.Items
+	for _, itemType := range itemTypes {
+		items = append(items, i.byType[itemType]...)
+	}
+
+	return items.SortByPosition()
+}
+
+func withoutItem(items basket_item.Items, uniqId basket_item.UniqId) basket_item.Items {
+	for j, item := range items {
+		if item.UniqId() == uniqId {
+			return append(items[:j:j], items[j+1:]...)
+		}
+	}
+
+	return items
+}
@@ -0,0 +1,249 @@
+package basket
+
+import (
+	"bytes"
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// Позиции полей BasketData в msgpack массиве, должны совпадать с тегами msgpackidx
+const (
+	basketDataItemsIndex = 2
+	basketDataInfosIndex = 6
+)
+
+// DecodeWarning данные корзины, которые не удалось восстановить при нестрогом декодировании
+type DecodeWarning struct {
+	// Уникальный идентификатор позиции, не попавшей в корзину. Пустой, если пропущена информация корзины или
+	// идентификатор позиции прочитать не удалось
+	UniqId basket_item.UniqId
+	// Причина, по которой данные не попали в корзину
+	Err error
+}
+
+func (w *DecodeWarning) String() string {
+	if w.UniqId == "" {
+		return w.Err.Error()
+	}
+
+	return fmt.Sprintf("item '%s': %s", w.UniqId, w.Err)
+}
+
+// DecodeBasketDataLenient декодирует данные корзины, сохраненные в msgpack, в нестрогом режиме.
+//
+// При строгом декодировании (BasketData.DecodeMsgpack) одна испорченная позиция делает недоступной всю корзину. В
+// нестрогом режиме позиции и информация корзины, которые не удается декодировать, пропускаются, вместе с ними
+// удаляются дочерние позиции пропущенных позиций. Все пропущенное возвращается в виде предупреждений, чтобы сервис
+// мог восстановить корзину и залогировать потерянные данные. Ошибка возвращается, только если испорчена сама структура
+// данных корзины или ее поля, не относящиеся к позициям
+func DecodeBasketDataLenient(encoded []byte) (*BasketData, []*DecodeWarning, error) {
+	data := &BasketData{}
+	err := msgpack.Unmarshal(encoded, data)
+	if err == nil {
+		return data, nil, nil
+	}
+
+	salvaged, warnings, err := salvageBasketData(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data = &BasketData{}
+	err = msgpack.Unmarshal(salvaged, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, append(warnings, data.removeOrphans()...), nil
+}
+
+// salvageBasketData пересобирает закодированные данные корзины без позиций и информации, которые не удается
+// декодировать. Остальные поля переносятся как есть и проверяются уже при строгом декодировании результата
+func salvageBasketData(encoded []byte) ([]byte, []*DecodeWarning, error) {
+	reader := newRawValueReader(encoded)
+	length, err := reader.decoder.DecodeArrayLen()
+	if err != nil {
+		return nil, nil, internal.NewMsgPackDecodeError(err, 0, "BasketData array len")
+	}
+
+	salvaged := &bytes.Buffer{}
+	encoder := msgpack.NewEncoder(salvaged)
+	err = encoder.EncodeArrayLen(length)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []*DecodeWarning
+	for index := 1; index <= length; index++ {
+		var fieldWarnings []*DecodeWarning
+		switch index {
+		case basketDataItemsIndex:
+			fieldWarnings, err = salvageBasketDataItems(reader, encoder, salvaged)
+		case basketDataInfosIndex:
+			fieldWarnings, err = salvageBasketDataInfos(reader, encoder, salvaged)
+		default:
+			var raw []byte
+			raw, err = reader.next()
+			salvaged.Write(raw)
+		}
+
+		if err != nil {
+			return nil, nil, internal.NewMsgPackDecodeError(err, index, "BasketData salvage")
+		}
+		warnings = append(warnings, fieldWarnings...)
+	}
+
+	return salvaged.Bytes(), warnings, nil
+}
+
+func salvageBasketDataItems(
+	reader *rawValueReader,
+	encoder *msgpack.Encoder,
+	salvaged *bytes.Buffer,
+) ([]*DecodeWarning, error) {
+	length, err := reader.decoder.DecodeMapLen()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []*DecodeWarning
+	entries := &bytes.Buffer{}
+	count := 0
+	for j := 0; j < length; j++ {
+		rawKey, err := reader.next()
+		if err != nil {
+			return nil, err
+		}
+
+		rawItem, err := reader.next()
+		if err != nil {
+			return nil, err
+		}
+
+		var uniqId basket_item.UniqId
+		err = msgpack.Unmarshal(rawKey, &uniqId)
+		if err != nil {
+			warnings = append(warnings, &DecodeWarning{Err: fmt.Errorf("can't decode item uniq id: %w", err)})
+			continue
+		}
+
+		err = msgpack.Unmarshal(rawItem, &basket_item.Item{})
+		if err != nil {
+			warnings = append(warnings, &DecodeWarning{UniqId: uniqId, Err: fmt.Errorf("can't decode item: %w", err)})
+			continue
+		}
+
+		entries.Write(rawKey)
+		entries.Write(rawItem)
+		count++
+	}
+
+	err = encoder.EncodeMapLen(count)
+	if err != nil {
+		return nil, err
+	}
+	salvaged.Write(entries.Bytes())
+
+	return warnings, nil
+}
+
+func salvageBasketDataInfos(
+	reader *rawValueReader,
+	encoder *msgpack.Encoder,
+	salvaged *bytes.Buffer,
+) ([]*DecodeWarning, error) {
+	raw, err := reader.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// информация корзины не влияет на ее содержимое, поэтому неверный тип поля не делает корзину недоступной
+	infosReader := newRawValueReader(raw)
+	length, err := infosReader.decoder.DecodeArrayLen()
+	if err != nil {
+		warning := &DecodeWarning{Err: fmt.Errorf("can't decode infos: %w", err)}
+
+		return []*DecodeWarning{warning}, encoder.EncodeArrayLen(0)
+	}
+
+	var warnings []*DecodeWarning
+	entries := &bytes.Buffer{}
+	count := 0
+	for j := 0; j < length; j++ {
+		rawInfo, err := infosReader.next()
+		if err != nil {
+			return nil, err
+		}
+
+		err = msgpack.Unmarshal(rawInfo, &Info{})
+		if err != nil {
+			warnings = append(warnings, &DecodeWarning{Err: fmt.Errorf("can't decode info %d: %w", j, err)})
+			continue
+		}
+
+		entries.Write(rawInfo)
+		count++
+	}
+
+	err = encoder.EncodeArrayLen(count)
+	if err != nil {
+		return nil, err
+	}
+	salvaged.Write(entries.Bytes())
+
+	return warnings, nil
+}
+
+// removeOrphans удаляет позиции, родительских позиций которых нет в корзине, вместе с их потомками
+func (b *BasketData) removeOrphans() []*DecodeWarning {
+	defer b.withAudit(AuditActorSystem, AuditReasonOrphan)()
+
+	var warnings []*DecodeWarning
+	for _, item := range b.All() {
+		if !item.IsChild() || b.items[item.ParentUniqId()] != nil || b.items[item.UniqId()] == nil {
+			continue
+		}
+
+		for _, child := range append(basket_item.Items{item}, b.index().childrenOfRecursive(item.UniqId())...) {
+			warnings = append(warnings, &DecodeWarning{
+				UniqId: child.UniqId(),
+				Err:    fmt.Errorf("parent item '%s' is skipped", child.ParentUniqId()),
+			})
+		}
+
+		b.Remove(item)
+	}
+
+	return warnings
+}
+
+// rawValueReader читает закодированные значения целиком, не декодируя их. Декодер читает напрямую из bytes.Reader без
+// буферизации, поэтому смещение в данных всегда указывает на начало следующего значения
+type rawValueReader struct {
+	encoded []byte
+	reader  *bytes.Reader
+	decoder *msgpack.Decoder
+}
+
+func newRawValueReader(encoded []byte) *rawValueReader {
+	reader := bytes.NewReader(encoded)
+
+	return &rawValueReader{encoded: encoded, reader: reader, decoder: msgpack.NewDecoder(reader)}
+}
+
+func (r *rawValueReader) offset() int {
+	return len(r.encoded) - r.reader.Len()
+}
+
+// next возвращает следующее закодированное значение
+func (r *rawValueReader) next() ([]byte, error) {
+	start := r.offset()
+	err := r.decoder.Skip()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.encoded[start:r.offset()], nil
+}
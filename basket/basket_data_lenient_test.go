@@ -0,0 +1,116 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"testing"
+)
+
+func TestDecodeBasketDataLenient(t *testing.T) {
+	newItem := func(itemType basket_item.Type) *basket_item.Item {
+		return basket_item.NewItem("1", itemType, "name", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail)
+	}
+
+	product := newItem(basket_item.TypeProduct)
+	brokenProduct := newItem(basket_item.TypeProduct)
+	service := newItem(basket_item.TypeInsuranceServiceForProduct)
+	require.NoError(t, service.MakeChildOf(brokenProduct))
+	info := NewInfo(product, basket_item.NewInfo(basket_item.InfoIdPositionRemoved, ""))
+
+	stored := func(items interface{}, infos interface{}) []interface{} {
+		return []interface{}{"msk_cl", items, 0, 0, catalog_types.PriceColumnRetail, infos, "", "msk", false}
+	}
+
+	tests := []struct {
+		name         string
+		stored       []interface{}
+		wantItems    []basket_item.UniqId
+		wantInfos    int
+		wantWarnings []string
+		wantErr      string
+	}{
+		{
+			name:      "valid basket",
+			stored:    stored(map[basket_item.UniqId]*basket_item.Item{product.UniqId(): product}, []*Info{info}),
+			wantItems: []basket_item.UniqId{product.UniqId()},
+			wantInfos: 1,
+		},
+		{
+			name: "broken item with child",
+			stored: stored(map[basket_item.UniqId]interface{}{
+				product.UniqId():       product,
+				brokenProduct.UniqId(): []interface{}{"broken"},
+				service.UniqId():       service,
+			}, []*Info{}),
+			wantItems: []basket_item.UniqId{product.UniqId()},
+			wantWarnings: []string{
+				"item '" + string(brokenProduct.UniqId()) + "': can't decode item: can't decode msgpack field " +
+					"`(basket_item.Item) incorrect len`[0]: (basket_item.Item) incorrect len: 1",
+				"item '" + string(service.UniqId()) + "': parent item '" + string(brokenProduct.UniqId()) +
+					"' is skipped",
+			},
+		},
+		{
+			name:      "broken item uniq id",
+			stored:    stored(map[interface{}]interface{}{product.UniqId(): product, 1: product}, []*Info{}),
+			wantItems: []basket_item.UniqId{product.UniqId()},
+			wantWarnings: []string{
+				"can't decode item uniq id: msgpack: invalid code 1 decoding bytes length",
+			},
+		},
+		{
+			name:      "broken info",
+			stored:    stored(map[basket_item.UniqId]*basket_item.Item{}, []interface{}{info, "broken"}),
+			wantItems: []basket_item.UniqId{},
+			wantInfos: 1,
+			wantWarnings: []string{
+				"can't decode info 1: can't decode msgpack field `Info array len`[0]: msgpack: invalid code a6 " +
+					"decoding array length",
+			},
+		},
+		{
+			name:      "broken infos",
+			stored:    stored(map[basket_item.UniqId]*basket_item.Item{}, "broken"),
+			wantItems: []basket_item.UniqId{},
+			wantWarnings: []string{
+				"can't decode infos: msgpack: invalid code a6 decoding array length",
+			},
+		},
+		{
+			name:    "broken space id",
+			stored:  []interface{}{1, map[basket_item.UniqId]interface{}{product.UniqId(): []interface{}{}}},
+			wantErr: "can't decode msgpack field `BasketData spaceId`[1]: msgpack: invalid code 1 decoding bytes length",
+		},
+		{
+			name:    "broken items",
+			stored:  []interface{}{"msk_cl", "broken"},
+			wantErr: "can't decode msgpack field `BasketData salvage`[2]: msgpack: invalid code a6 decoding map length",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := msgpack.Marshal(tt.stored)
+			require.NoError(t, err)
+
+			data, warnings, err := DecodeBasketDataLenient(encoded)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.wantItems, data.All().UniqIds())
+			assert.Len(t, data.Infos(), tt.wantInfos)
+
+			gotWarnings := make([]string, 0, len(warnings))
+			for _, warning := range warnings {
+				gotWarnings = append(gotWarnings, warning.String())
+			}
+			assert.ElementsMatch(t, tt.wantWarnings, gotWarnings)
+		})
+	}
+}
@@ -1,3 +1,5 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
 package basket
 
 import (
@@ -6,12 +8,14 @@ import (
 	"go.citilink.cloud/order/internal"
 	"go.citilink.cloud/store_types"
 	"gopkg.in/vmihailenco/msgpack.v2"
+	"time"
 )
 
 func (b *BasketData) EncodeMsgpack(e *msgpack.Encoder) error {
-	if err := e.EncodeArrayLen(9); err != nil {
+	if err := e.EncodeArrayLen(16); err != nil {
 		return err
 	}
+
 	if err := e.EncodeString(string(b.spaceId)); err != nil { // 1
 		return err
 	}
@@ -27,48 +31,90 @@ func (b *BasketData) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeInt(int(b.priceColumn)); err != nil { // 5
 		return err
 	}
-
 	if err := e.EncodeArrayLen(len(b.infos)); err != nil { // 6
 		return err
 	}
 	for _, v := range b.infos {
-		err := e.Encode(v)
-		if err != nil {
+		if err := e.Encode(v); err != nil {
 			return err
 		}
 	}
-
 	if err := e.EncodeString(b.commitFingerprint); err != nil { // 7
 		return err
 	}
-
 	if err := e.EncodeString(string(b.cityId)); err != nil { // 8
 		return err
 	}
-
 	if err := e.EncodeBool(b.hasPossibleConfiguration); err != nil { // 9
 		return err
 	}
+	if err := e.EncodeInt64(b.version); err != nil { // 10
+		return err
+	}
+	if err := e.EncodeArrayLen(len(b.appliedOperations)); err != nil { // 11
+		return err
+	}
+	for _, v := range b.appliedOperations {
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+	}
+	var createdAt int64
+	if !b.createdAt.IsZero() {
+		createdAt = b.createdAt.UnixNano()
+	}
+	if err := e.EncodeInt64(createdAt); err != nil { // 12
+		return err
+	}
+	var modifiedAt int64
+	if !b.modifiedAt.IsZero() {
+		modifiedAt = b.modifiedAt.UnixNano()
+	}
+	if err := e.EncodeInt64(modifiedAt); err != nil { // 13
+		return err
+	}
+	var viewedAt int64
+	if !b.viewedAt.IsZero() {
+		viewedAt = b.viewedAt.UnixNano()
+	}
+	if err := e.EncodeInt64(viewedAt); err != nil { // 14
+		return err
+	}
+	if err := e.EncodeArrayLen(len(b.auditTail)); err != nil { // 15
+		return err
+	}
+	for _, v := range b.auditTail {
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+	}
+	if err := e.EncodeArrayLen(len(b.faultScenarios)); err != nil { // 16
+		return err
+	}
+	for _, v := range b.faultScenarios {
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 func (b *BasketData) DecodeMsgpack(d *msgpack.Decoder) error {
-	var err error
-	var length int
-	if length, err = d.DecodeArrayLen(); err != nil {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "BasketData array len")
 	}
 
-	if length > 9 || length < 2 {
+	if length < 2 || length > 16 {
 		return internal.NewMsgPackDecodeError(fmt.Errorf("(basket.BasketData) incorrect len: %d", length), 0, "(basket.BasketData) incorrect len")
 	}
 
-	if v, err := d.DecodeString(); err != nil { // 1
+	spaceId, err := d.DecodeString() // 1
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 1, "BasketData spaceId")
-	} else {
-		b.spaceId = store_types.SpaceId(v)
 	}
+	b.spaceId = store_types.SpaceId(spaceId)
 
 	if err := d.Decode(&b.items); err != nil { // 2
 		return internal.NewMsgPackDecodeError(err, 2, "BasketData items")
@@ -78,52 +124,150 @@ func (b *BasketData) DecodeMsgpack(d *msgpack.Decoder) error {
 		if _, err := d.DecodeInt(); err != nil { // 3 deleted
 			return internal.NewMsgPackDecodeError(err, 3, "BasketData DecodeInt")
 		}
+	}
+
+	if length > 3 {
 		if _, err := d.DecodeInt(); err != nil { // 4 deleted
 			return internal.NewMsgPackDecodeError(err, 4, "BasketData DecodeInt")
 		}
-		if v, err := d.DecodeInt(); err != nil { // 5
+	}
+
+	if length > 4 {
+		priceColumn, err := d.DecodeInt() // 5
+		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 5, "BasketData priceColumn")
-		} else {
-			b.priceColumn = catalog_types.PriceColumn(v)
 		}
+		b.priceColumn = catalog_types.PriceColumn(priceColumn)
 	}
 
 	if length > 5 {
-		l, err := d.DecodeArrayLen() // 6
+		infosLen, err := d.DecodeArrayLen() // 6
 		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 6, "BasketData array len")
 		}
-		b.infos = make([]*Info, l)
-		for j := 0; j < l; j++ {
-			if err := d.Decode(&b.infos[j]); err != nil {
+		infos := make([]*Info, infosLen)
+		for j := 0; j < infosLen; j++ {
+			if err := d.Decode(&infos[j]); err != nil {
 				return internal.NewMsgPackDecodeError(err, 6, "BasketData decode infos")
 			}
 		}
+		b.infos = infos
 	}
 
-	if length > 6 { // 7
-		if v, err := d.DecodeString(); err != nil { // 7
+	if length > 6 {
+		commitFingerprint, err := d.DecodeString() // 7
+		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 7, "BasketData commitFingerprint")
-		} else {
-			b.commitFingerprint = v
 		}
+		b.commitFingerprint = commitFingerprint
 	}
 
-	if length > 7 { // 8
-		if v, err := d.DecodeString(); err != nil { // 8
+	if length > 7 {
+		cityId, err := d.DecodeString() // 8
+		if err != nil {
 			return internal.NewDecodeErr(err)
-		} else {
-			b.cityId = CityId(v)
 		}
+		b.cityId = CityId(cityId)
 	}
 
-	if length > 8 { // 9
-		if v, err := d.DecodeBool(); err != nil {
+	if length > 8 {
+		hasPossibleConfiguration, err := d.DecodeBool() // 9
+		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 9, "Basket hasPossibleConfiguration")
-		} else {
-			b.hasPossibleConfiguration = v
 		}
+		b.hasPossibleConfiguration = hasPossibleConfiguration
 	}
 
+	if length > 9 {
+		version, err := d.DecodeInt64() // 10
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 10, "BasketData version")
+		}
+		b.version = version
+	}
+
+	if length > 10 {
+		appliedOperationsLen, err := d.DecodeArrayLen() // 11
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 11, "BasketData appliedOperations")
+		}
+		var appliedOperations []*AppliedOperation
+		if appliedOperationsLen > 0 {
+			appliedOperations = make([]*AppliedOperation, appliedOperationsLen)
+		}
+		for j := 0; j < appliedOperationsLen; j++ {
+			if err := d.Decode(&appliedOperations[j]); err != nil {
+				return internal.NewMsgPackDecodeError(err, 11, "BasketData appliedOperations")
+			}
+		}
+		b.appliedOperations = appliedOperations
+	}
+
+	if length > 11 {
+		createdAt, err := d.DecodeInt64() // 12
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 12, "BasketData createdAt")
+		}
+		if createdAt != 0 {
+			b.createdAt = time.Unix(0, createdAt).UTC()
+		}
+	}
+
+	if length > 12 {
+		modifiedAt, err := d.DecodeInt64() // 13
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 13, "BasketData modifiedAt")
+		}
+		if modifiedAt != 0 {
+			b.modifiedAt = time.Unix(0, modifiedAt).UTC()
+		}
+	}
+
+	if length > 13 {
+		viewedAt, err := d.DecodeInt64() // 14
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 14, "BasketData viewedAt")
+		}
+		if viewedAt != 0 {
+			b.viewedAt = time.Unix(0, viewedAt).UTC()
+		}
+	}
+
+	if length > 14 {
+		auditTailLen, err := d.DecodeArrayLen() // 15
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 15, "BasketData auditTail")
+		}
+		var auditTail []*AuditEntry
+		if auditTailLen > 0 {
+			auditTail = make([]*AuditEntry, auditTailLen)
+		}
+		for j := 0; j < auditTailLen; j++ {
+			if err := d.Decode(&auditTail[j]); err != nil {
+				return internal.NewMsgPackDecodeError(err, 15, "BasketData auditTail")
+			}
+		}
+		b.auditTail = auditTail
+	}
+
+	if length > 15 {
+		faultScenariosLen, err := d.DecodeArrayLen() // 16
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 16, "BasketData faultScenarios")
+		}
+		var faultScenarios []*FaultScenario
+		if faultScenariosLen > 0 {
+			faultScenarios = make([]*FaultScenario, faultScenariosLen)
+		}
+		for j := 0; j < faultScenariosLen; j++ {
+			if err := d.Decode(&faultScenarios[j]); err != nil {
+				return internal.NewMsgPackDecodeError(err, 16, "BasketData faultScenarios")
+			}
+		}
+		b.faultScenarios = faultScenarios
+	}
+
+	b.afterDecode()
+
 	return nil
 }
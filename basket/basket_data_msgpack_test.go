@@ -8,6 +8,7 @@ import (
 	"gopkg.in/vmihailenco/msgpack.v2"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type BasketDataMgspackSuite struct {
@@ -54,8 +55,8 @@ func (s *BasketDataMgspackSuite) TestBasketData_DecodeMsgpack() {
 		},
 		{
 			name:    "incorrect len(long)",
-			data:    []interface{}{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-			wantErr: "can't decode msgpack field `(basket.BasketData) incorrect len`[0]: (basket.BasketData) incorrect len: 10",
+			data:    []interface{}{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			wantErr: "can't decode msgpack field `(basket.BasketData) incorrect len`[0]: (basket.BasketData) incorrect len: 17",
 		},
 		{
 			name:    "incorrect spaceId",
@@ -124,10 +125,78 @@ func (s *BasketDataMgspackSuite) TestBasketData_DecodeMsgpack() {
 			wantErr: "can't decode msgpack field `Basket hasPossibleConfiguration`[9]: msgpack: invalid code a8 decoding bool",
 		},
 		{
-			name: "ok",
+			name: "incorrect version",
+			data: []interface{}{"t", map[basket_item.UniqId]*basket_item.Item{
+				"test": testItem,
+			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city id", true, "1"},
+			wantErr: "can't decode msgpack field `BasketData version`[10]: msgpack: invalid code a1 decoding int64",
+		},
+		{
+			name: "incorrect appliedOperations",
+			data: []interface{}{"t", map[basket_item.UniqId]*basket_item.Item{
+				"test": testItem,
+			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city id", true, 1, []string{"key"}},
+			wantErr: "can't decode msgpack field `BasketData appliedOperations`[11]: can't decode msgpack field " +
+				"`AppliedOperation array len`[0]: msgpack: invalid code a3 decoding array length",
+		},
+		{
+			name: "incorrect viewedAt",
+			data: []interface{}{"t", map[basket_item.UniqId]*basket_item.Item{
+				"test": testItem,
+			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city id", true, 1, []*AppliedOperation{}, 0, 0, "now"},
+			wantErr: "can't decode msgpack field `BasketData viewedAt`[14]: msgpack: invalid code a3 decoding int64",
+		},
+		{
+			name: "incorrect auditTail",
+			data: []interface{}{"t", map[basket_item.UniqId]*basket_item.Item{
+				"test": testItem,
+			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city id", true, 1, []*AppliedOperation{}, 0, 0, 0,
+				[]string{"entry"}},
+			wantErr: "can't decode msgpack field `BasketData auditTail`[15]: can't decode msgpack field " +
+				"`AuditEntry array len`[0]: msgpack: invalid code a5 decoding array length",
+		},
+		{
+			name: "incorrect faultScenarios",
 			data: []interface{}{"t", map[basket_item.UniqId]*basket_item.Item{
 				"test": testItem,
-			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city Id", true},
+			}, 0, 1, 10, []*Info{testInfo}, "fingerprint", "city id", true, 1, []*AppliedOperation{}, 0, 0, 0,
+				[]*AuditEntry{}, []string{"price_jump"}},
+			wantErr: "can't decode msgpack field `BasketData faultScenarios`[16]: can't decode msgpack field " +
+				"`FaultScenario array len`[0]: msgpack: invalid code aa decoding array length",
+		},
+		{
+			name: "ok",
+			data: basketDataMsgpackFixture(testItem, testInfo),
+			want: func() *BasketData {
+				return &BasketData{
+					spaceId:                  "t",
+					items:                    map[basket_item.UniqId]*basket_item.Item{"test": testItem},
+					priceColumn:              10,
+					infos:                    []*Info{testInfo},
+					commitFingerprint:        "fingerprint",
+					cityId:                   "city Id",
+					hasPossibleConfiguration: true,
+					version:                  3,
+					appliedOperations: []*AppliedOperation{
+						NewAppliedOperation("key", OperationTypeAdd, []basket_item.UniqId{"test"}),
+					},
+					createdAt:  time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+					modifiedAt: time.Date(2024, 5, 7, 7, 8, 9, 0, time.UTC),
+					viewedAt:   time.Date(2024, 5, 8, 7, 8, 9, 0, time.UTC),
+					auditTail: []*AuditEntry{
+						NewAuditEntry(time.Date(2024, 5, 7, 7, 8, 9, 0, time.UTC), AuditActorSystem,
+							AuditOperationRemove, "removed", "2", 1, 0, 100, 0, AuditReasonNotInCatalog),
+					},
+					faultScenarios: []*FaultScenario{
+						NewFaultScenario(FaultScenarioPriceJump, "test"),
+						NewFaultScenario(FaultScenarioCatalogTimeout),
+					},
+				}
+			},
+		},
+		{
+			name: "ok without version",
+			data: basketDataMsgpackFixture(testItem, testInfo)[:9],
 			want: func() *BasketData {
 				return &BasketData{
 					spaceId:                  "t",
@@ -161,6 +230,36 @@ func (s *BasketDataMgspackSuite) TestBasketData_DecodeMsgpack() {
 	}
 }
 
+// basketDataMsgpackFixture данные корзины в том виде, в котором они сохраняются в хранилище
+func basketDataMsgpackFixture(item *basket_item.Item, info *Info) []interface{} {
+	return []interface{}{
+		"t", // spaceId 1
+		map[basket_item.UniqId]*basket_item.Item{"test": item}, // items 2
+		0,             // 3 deleted
+		1,             // 4 deleted
+		10,            // priceColumn 5
+		[]*Info{info}, // infos 6
+		"fingerprint", // commitFingerprint 7
+		"city Id",     // cityId 8
+		true,          // hasPossibleConfiguration 9
+		3,             // version 10
+		[]*AppliedOperation{ // appliedOperations 11
+			NewAppliedOperation("key", OperationTypeAdd, []basket_item.UniqId{"test"}),
+		},
+		time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC).UnixNano(), // createdAt 12
+		time.Date(2024, 5, 7, 7, 8, 9, 0, time.UTC).UnixNano(), // modifiedAt 13
+		time.Date(2024, 5, 8, 7, 8, 9, 0, time.UTC).UnixNano(), // viewedAt 14
+		[]*AuditEntry{ // auditTail 15
+			NewAuditEntry(time.Date(2024, 5, 7, 7, 8, 9, 0, time.UTC), AuditActorSystem,
+				AuditOperationRemove, "removed", "2", 1, 0, 100, 0, AuditReasonNotInCatalog),
+		},
+		[]*FaultScenario{ // faultScenarios 16
+			NewFaultScenario(FaultScenarioPriceJump, "test"),
+			NewFaultScenario(FaultScenarioCatalogTimeout),
+		},
+	}
+}
+
 func TestBasketDataMgspackSuite(t *testing.T) {
 	suite.Run(t, new(BasketDataMgspackSuite))
 }
@@ -0,0 +1,251 @@
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	basketv1 "go.citilink.cloud/order/internal/order/basket/api/v1"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/store_types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// Данные корзины для внешних потребителей (аналитика, CRM, BFF мобильного приложения) передаются в protobuf или в
+// JSON по схеме api/v1/basket.proto, а не в msgpack, который невозможно прочитать без этого пакета. Перевод в обе
+// стороны происходит без потерь: данные, восстановленные из protobuf или JSON, сохраняются в msgpack так же, как
+// исходные.
+
+var (
+	// basketDataJSONMarshalOptions поля без значений тоже попадают в JSON, чтобы набор полей не зависел от данных
+	// корзины
+	basketDataJSONMarshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+	// basketDataJSONUnmarshalOptions неизвестные поля пропускаются, чтобы читать JSON, записанный по более новой схеме
+	basketDataJSONUnmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// BasketDataToProto переводит данные корзины в protobuf представление
+func BasketDataToProto(data *BasketData) *basketv1.BasketData {
+	message := &basketv1.BasketData{
+		SpaceId:                  string(data.spaceId),
+		Items:                    make(map[string]*basketv1.Item, len(data.items)),
+		PriceColumn:              int32(data.priceColumn),
+		CommitFingerprint:        data.commitFingerprint,
+		CityId:                   string(data.cityId),
+		HasPossibleConfiguration: data.hasPossibleConfiguration,
+		Version:                  data.version,
+	}
+
+	for uniqId, item := range data.items {
+		message.Items[string(uniqId)] = basket_item.ItemToProto(item)
+	}
+
+	for _, info := range data.infos {
+		message.Infos = append(message.Infos, &basketv1.BasketInfo{
+			Item: basket_item.ItemToProto(info.Item()),
+			Info: basket_item.InfoToProto(info.Info()),
+		})
+	}
+
+	if !data.createdAt.IsZero() {
+		message.CreatedAt = timestamppb.New(data.createdAt)
+	}
+
+	if !data.modifiedAt.IsZero() {
+		message.ModifiedAt = timestamppb.New(data.modifiedAt)
+	}
+
+	if !data.viewedAt.IsZero() {
+		message.ViewedAt = timestamppb.New(data.viewedAt)
+	}
+
+	for _, operation := range data.appliedOperations {
+		uniqIds := make([]string, 0, len(operation.uniqIds))
+		for _, uniqId := range operation.uniqIds {
+			uniqIds = append(uniqIds, string(uniqId))
+		}
+
+		message.AppliedOperations = append(message.AppliedOperations, &basketv1.AppliedOperation{
+			Key:           string(operation.key),
+			OperationType: string(operation.operationType),
+			UniqIds:       uniqIds,
+		})
+	}
+
+	for _, entry := range data.auditTail {
+		entryMessage := &basketv1.AuditEntry{
+			Actor:       string(entry.actor),
+			Operation:   string(entry.operation),
+			UniqId:      string(entry.uniqId),
+			ItemId:      string(entry.itemId),
+			CountBefore: int64(entry.countBefore),
+			CountAfter:  int64(entry.countAfter),
+			PriceBefore: int64(entry.priceBefore),
+			PriceAfter:  int64(entry.priceAfter),
+			Reason:      string(entry.reason),
+		}
+		if !entry.at.IsZero() {
+			entryMessage.At = timestamppb.New(entry.at)
+		}
+
+		message.AuditTail = append(message.AuditTail, entryMessage)
+	}
+
+	for _, scenario := range data.faultScenarios {
+		itemIds := make([]string, 0, len(scenario.itemIds))
+		for _, itemId := range scenario.itemIds {
+			itemIds = append(itemIds, string(itemId))
+		}
+
+		message.FaultScenarios = append(message.FaultScenarios, &basketv1.FaultScenario{
+			Id:      string(scenario.id),
+			ItemIds: itemIds,
+		})
+	}
+
+	return message
+}
+
+// BasketDataFromProto восстанавливает данные корзины из protobuf представления, полученного через BasketDataToProto
+func BasketDataFromProto(message *basketv1.BasketData) (*BasketData, error) {
+	data := NewBasketData(
+		store_types.SpaceId(message.GetSpaceId()),
+		catalog_types.PriceColumn(message.GetPriceColumn()),
+		CityId(message.GetCityId()),
+	)
+	data.commitFingerprint = message.GetCommitFingerprint()
+	data.hasPossibleConfiguration = message.GetHasPossibleConfiguration()
+	data.version = message.GetVersion()
+	if message.GetCreatedAt() != nil {
+		data.createdAt = message.GetCreatedAt().AsTime()
+	}
+
+	if message.GetModifiedAt() != nil {
+		data.modifiedAt = message.GetModifiedAt().AsTime()
+	}
+
+	if message.GetViewedAt() != nil {
+		data.viewedAt = message.GetViewedAt().AsTime()
+	}
+
+	for uniqId, itemMessage := range message.GetItems() {
+		item, err := basket_item.ItemFromProto(itemMessage)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert item '%s': %w", uniqId, err)
+		}
+
+		data.items[basket_item.UniqId(uniqId)] = item
+	}
+
+	for _, infoMessage := range message.GetInfos() {
+		item, err := basket_item.ItemFromProto(infoMessage.GetItem())
+		if err != nil {
+			return nil, fmt.Errorf("can't convert item of info %d: %w", infoMessage.GetInfo().GetId(), err)
+		}
+
+		data.infos = append(data.infos, NewInfo(item, basket_item.InfoFromProto(infoMessage.GetInfo())))
+	}
+
+	for _, operationMessage := range message.GetAppliedOperations() {
+		uniqIds := make([]basket_item.UniqId, 0, len(operationMessage.GetUniqIds()))
+		for _, uniqId := range operationMessage.GetUniqIds() {
+			uniqIds = append(uniqIds, basket_item.UniqId(uniqId))
+		}
+
+		data.appliedOperations = append(data.appliedOperations, NewAppliedOperation(
+			IdempotencyKey(operationMessage.GetKey()),
+			OperationType(operationMessage.GetOperationType()),
+			uniqIds,
+		))
+	}
+
+	for _, entryMessage := range message.GetAuditTail() {
+		entry := NewAuditEntry(
+			time.Time{},
+			AuditActor(entryMessage.GetActor()),
+			AuditOperation(entryMessage.GetOperation()),
+			basket_item.UniqId(entryMessage.GetUniqId()),
+			basket_item.ItemId(entryMessage.GetItemId()),
+			int(entryMessage.GetCountBefore()),
+			int(entryMessage.GetCountAfter()),
+			int(entryMessage.GetPriceBefore()),
+			int(entryMessage.GetPriceAfter()),
+			AuditReason(entryMessage.GetReason()),
+		)
+		if entryMessage.GetAt() != nil {
+			entry.at = entryMessage.GetAt().AsTime()
+		}
+
+		data.auditTail = append(data.auditTail, entry)
+	}
+
+	for _, scenarioMessage := range message.GetFaultScenarios() {
+		itemIds := make([]basket_item.ItemId, 0, len(scenarioMessage.GetItemIds()))
+		for _, itemId := range scenarioMessage.GetItemIds() {
+			itemIds = append(itemIds, basket_item.ItemId(itemId))
+		}
+
+		data.faultScenarios = append(data.faultScenarios, NewFaultScenario(
+			FaultScenarioId(scenarioMessage.GetId()),
+			itemIds...,
+		))
+	}
+
+	return data, nil
+}
+
+// MarshalBasketDataProto кодирует данные корзины в protobuf
+func MarshalBasketDataProto(data *BasketData) ([]byte, error) {
+	encoded, err := proto.Marshal(BasketDataToProto(data))
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal basket data to protobuf: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// UnmarshalBasketDataProto декодирует данные корзины из protobuf
+func UnmarshalBasketDataProto(encoded []byte) (*BasketData, error) {
+	message := &basketv1.BasketData{}
+	err := proto.Unmarshal(encoded, message)
+	if err != nil {
+		return nil, internal.NewValidationError(fmt.Errorf("can't unmarshal basket data from protobuf: %w", err))
+	}
+
+	data, err := BasketDataFromProto(message)
+	if err != nil {
+		return nil, internal.NewValidationError(err)
+	}
+
+	return data, nil
+}
+
+// MarshalBasketDataJSON кодирует данные корзины в JSON. Формат JSON - стандартное JSON отображение protobuf схемы
+// api/v1/basket.proto, поэтому потребители могут читать его как сгенерированными по схеме типами, так и любым
+// JSON парсером
+func MarshalBasketDataJSON(data *BasketData) ([]byte, error) {
+	encoded, err := basketDataJSONMarshalOptions.Marshal(BasketDataToProto(data))
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal basket data to json: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// UnmarshalBasketDataJSON декодирует данные корзины из JSON, полученного через MarshalBasketDataJSON
+func UnmarshalBasketDataJSON(encoded []byte) (*BasketData, error) {
+	message := &basketv1.BasketData{}
+	err := basketDataJSONUnmarshalOptions.Unmarshal(encoded, message)
+	if err != nil {
+		return nil, internal.NewValidationError(fmt.Errorf("can't unmarshal basket data from json: %w", err))
+	}
+
+	data, err := BasketDataFromProto(message)
+	if err != nil {
+		return nil, internal.NewValidationError(err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,148 @@
+package basket
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"testing"
+	"time"
+)
+
+func TestBasketData_ExternalRoundTrip(t *testing.T) {
+	storedItem := basket_item.NewItem("item-id", basket_item.TypeProduct, "test", "", 0, 0, 0, "", 0)
+	storedInfo := NewInfo(storedItem, basket_item.NewInfo(basket_item.InfoIdPositionRemoved, ""))
+
+	product := basket_item.NewItem("1", basket_item.TypeProduct, "name", "image", 2, 100, 5, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	product.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	product.Additions().GetProduct().SetCreditPrograms([]catalog_types.CreditProgram{"credit"})
+	product.SetAddedAt(time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC))
+	product.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "not available"))
+	product.AddInfo(basket_item.NewInfo(basket_item.InfoIdPriceChanged, "price changed"))
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	_, err := data.Add(product)
+	require.NoError(t, err)
+	data.SetHasPossibleConfiguration(true)
+	data.CommitChanges()
+
+	tests := []struct {
+		name   string
+		stored func() interface{}
+	}{
+		{
+			name: "stored basket",
+			stored: func() interface{} {
+				return basketDataMsgpackFixture(storedItem, storedInfo)
+			},
+		},
+		{
+			name: "basket with product",
+			stored: func() interface{} {
+				return data
+			},
+		},
+	}
+
+	codecs := []struct {
+		name      string
+		marshal   func(data *BasketData) ([]byte, error)
+		unmarshal func(encoded []byte) (*BasketData, error)
+	}{
+		{name: "protobuf", marshal: MarshalBasketDataProto, unmarshal: UnmarshalBasketDataProto},
+		{name: "json", marshal: MarshalBasketDataJSON, unmarshal: UnmarshalBasketDataJSON},
+	}
+
+	for _, tt := range tests {
+		for _, codec := range codecs {
+			tt, codec := tt, codec
+			t.Run(tt.name+" "+codec.name, func(t *testing.T) {
+				stored, err := msgpack.Marshal(tt.stored())
+				require.NoError(t, err)
+				decoded := &BasketData{}
+				require.NoError(t, msgpack.Unmarshal(stored, decoded))
+				want, err := msgpack.Marshal(decoded)
+				require.NoError(t, err)
+
+				encoded, err := codec.marshal(decoded)
+				require.NoError(t, err)
+				restored, err := codec.unmarshal(encoded)
+				require.NoError(t, err)
+
+				got, err := msgpack.Marshal(restored)
+				require.NoError(t, err)
+				assert.Equal(t, want, got)
+				assert.Equal(t, decoded.Fingerprint(), restored.Fingerprint())
+				assert.Equal(t, decoded.IsChanged(), restored.IsChanged())
+			})
+		}
+	}
+}
+
+func TestMarshalBasketDataJSON(t *testing.T) {
+	item := basket_item.NewItem("1", basket_item.TypeProduct, "name", "", 1, 100, 0, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	_, err := data.Add(item)
+	require.NoError(t, err)
+
+	encoded, err := MarshalBasketDataJSON(data)
+	require.NoError(t, err)
+
+	document := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(encoded, &document))
+	assert.Equal(t, "msk_cl", document["spaceId"])
+	assert.Equal(t, "msk", document["cityId"])
+	assert.Equal(t, false, document["hasPossibleConfiguration"])
+	assert.Equal(t, []interface{}{}, document["infos"])
+
+	items := document["items"].(map[string]interface{})
+	encodedItem := items[string(item.UniqId())].(map[string]interface{})
+	assert.Equal(t, "1", encodedItem["itemId"])
+	assert.Equal(t, string(basket_item.TypeProduct), encodedItem["type"])
+	assert.Equal(t, "100", encodedItem["price"])
+	assert.Equal(t, true, encodedItem["isSelected"])
+}
+
+func TestUnmarshalBasketDataJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    func() *BasketData
+		wantErr string
+	}{
+		{
+			name: "unknown fields are skipped",
+			json: `{"spaceId": "msk_cl", "priceColumn": 1, "cityId": "msk", "newField": {"value": 1}}`,
+			want: func() *BasketData {
+				return NewBasketData("msk_cl", catalog_types.PriceColumn(1), "msk")
+			},
+		},
+		{
+			name:    "invalid json",
+			json:    `{"spaceId": 1}`,
+			wantErr: "can't unmarshal basket data from json",
+		},
+		{
+			name: "invalid item",
+			json: `{"items": {"uniq": {"additions": {` +
+				`"subcontractServiceForProduct": {"applyServiceInfo": {"date": "-"}}}}}}`,
+			wantErr: "can't convert item 'uniq': can't convert additions of item ''",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := UnmarshalBasketDataJSON([]byte(tt.json))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want(), data)
+		})
+	}
+}
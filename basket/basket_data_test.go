@@ -4,7 +4,10 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.citilink.cloud/catalog_types"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type BasketDataSuite struct {
@@ -27,21 +30,21 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 			name:    "success: empty msk cl retail",
 			request: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"),
 			want: func() string {
-				return "4216353138595167701"
+				return "2:516956018c4d8671f0bebc9d2dff7fd668c8bc1fd79b22bacab0aac00c94d0dd"
 			},
 		},
 		{
 			name:    "success: empty spb cl retail",
 			request: NewBasketData("spb_cl", catalog_types.PriceColumnRetail, "spb"),
 			want: func() string {
-				return "1534197782466464695"
+				return "2:77f16c5fe054d39f3846516d7334b5eef3e357b63e7ee8654aee2d1dd07a1840"
 			},
 		},
 		{
 			name:    "success: empty msk cl club",
 			request: NewBasketData("spb_cl", catalog_types.PriceColumnClub, "spb"),
 			want: func() string {
-				return "3844539885842716164"
+				return "2:f5e8f0be9727e50ebf456f4362012ba5303a5961198c06894874c0c160bdf37f"
 			},
 		},
 		{
@@ -61,7 +64,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "4754936641902033992"
+				return "2:6e005c463f328a3a65d067473f7d13e4e6873a029c5371eedc49454ee8927092"
 			},
 		},
 		{
@@ -81,7 +84,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "15399763920430204393"
+				return "2:c6613931015775a6b0276c4b78cec59b837c2eef2b361ac80a0eee1b1d2d0467"
 			},
 		},
 		{
@@ -101,7 +104,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "15780090000240964663"
+				return "2:75203e06d1a307058b13dd83f8de1153184e9a4275a950c785a4655c03139476"
 			},
 		},
 		{
@@ -121,7 +124,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "15780090000240964663"
+				return "2:75203e06d1a307058b13dd83f8de1153184e9a4275a950c785a4655c03139476"
 			},
 		},
 		{
@@ -151,7 +154,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "585458320222589841"
+				return "2:315b18dee9f1a51f65cc8979330177001f586802f346df37799d665476a2e6f9"
 			},
 		},
 		{
@@ -181,7 +184,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "585458320222589841"
+				return "2:315b18dee9f1a51f65cc8979330177001f586802f346df37799d665476a2e6f9"
 			},
 		},
 		{
@@ -215,7 +218,7 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 				return b
 			}(),
 			want: func() string {
-				return "2016431460704393095"
+				return "2:90280669c7b74c35fafb5e830ce18b78b849cb50f728ee141307bfd67de2e39f"
 			},
 		},
 	}
@@ -226,6 +229,449 @@ func (b *BasketDataSuite) TestBasketData_Fingerprint() {
 	}
 }
 
+func (b *BasketDataSuite) TestBasketData_ScopedFingerprint() {
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	product, err := data.Add(basket_item.NewItem(
+		"1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	))
+	b.Require().NoError(err)
+	conf, err := data.Add(basket_item.NewConfigurationItem(700, "msk_cl", catalog_types.PriceColumnRetail))
+	b.Require().NoError(err)
+	cpu := basket_item.NewItem(
+		"2", basket_item.TypeConfigurationProduct, "", "", 1, 700, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	)
+	b.Require().NoError(conf.AddChild(cpu))
+	_, err = data.Add(cpu)
+	b.Require().NoError(err)
+
+	b.Assert().Equal(data.Fingerprint(), data.ScopedFingerprint(basket_item.FingerprintScopeFull))
+	b.Assert().NotEqual(
+		data.ScopedFingerprint(basket_item.FingerprintScopePayment),
+		data.ScopedFingerprint(basket_item.FingerprintScopeDelivery),
+	)
+
+	b.Run("selection of configuration component", func() {
+		payment := data.ScopedFingerprint(basket_item.FingerprintScopePayment)
+		cpu.SetIsSelected(!cpu.IsSelected())
+
+		b.Assert().Equal(payment, data.ScopedFingerprint(basket_item.FingerprintScopePayment))
+	})
+
+	b.Run("price of product", func() {
+		payment := data.ScopedFingerprint(basket_item.FingerprintScopePayment)
+		delivery := data.ScopedFingerprint(basket_item.FingerprintScopeDelivery)
+		product.SetPrice(200)
+
+		b.Assert().NotEqual(payment, data.ScopedFingerprint(basket_item.FingerprintScopePayment))
+		b.Assert().Equal(delivery, data.ScopedFingerprint(basket_item.FingerprintScopeDelivery))
+	})
+
+	b.Run("city", func() {
+		delivery := data.ScopedFingerprint(basket_item.FingerprintScopeDelivery)
+		data.cityId = "spb"
+
+		b.Assert().NotEqual(delivery, data.ScopedFingerprint(basket_item.FingerprintScopeDelivery))
+	})
+
+	b.Run("legacy commit fingerprint", func() {
+		data.CommitChanges()
+		data.commitFingerprint = "4216353138595167701"
+		buf, err := msgpack.Marshal(data)
+		b.Require().NoError(err)
+		decoded := &BasketData{}
+		b.Require().NoError(msgpack.Unmarshal(buf, decoded))
+
+		b.Assert().False(decoded.IsChanged())
+	})
+}
+
+func (b *BasketDataSuite) TestBasketData_Add() {
+	newConfiguration := func(confId string) *basket_item.Item {
+		conf := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+		conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions(confId, basket_item.ConfTypeUser))
+
+		return conf
+	}
+	newProduct := func(count int) *basket_item.Item {
+		item := basket_item.NewItem(
+			"123",
+			basket_item.TypeProduct,
+			"name",
+			"image",
+			count,
+			1,
+			1,
+			"msk_cl",
+			catalog_types.PriceColumnRetail)
+		item.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+
+		return item
+	}
+
+	tests := []struct {
+		name      string
+		items     []*basket_item.Item
+		wantCount int
+	}{
+		{
+			name:      "same products are merged",
+			items:     []*basket_item.Item{newProduct(1), newProduct(2)},
+			wantCount: 1,
+		},
+		{
+			name:      "configurations are not merged",
+			items:     []*basket_item.Item{newConfiguration("1"), newConfiguration("2")},
+			wantCount: 2,
+		},
+	}
+	for _, test := range tests {
+		b.Run(test.name, func() {
+			data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+			for _, item := range test.items {
+				addedItem, err := data.Add(item)
+				b.Require().NoError(err)
+				b.Require().NotNil(addedItem)
+			}
+
+			b.Assert().Equal(test.wantCount, data.Count())
+		})
+	}
+}
+
+func (b *BasketDataSuite) TestBasketData_Index() {
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	product := basket_item.NewItem(
+		"1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	)
+	service := basket_item.NewItem(
+		"2", basket_item.TypeSubcontractServiceForProduct, "", "", 1, 10, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	)
+	present := basket_item.NewItem(
+		"3", basket_item.TypePresent, "", "", 1, 0, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	)
+
+	_, err := data.Add(product)
+	b.Require().NoError(err)
+	b.Require().NoError(product.AddChild(service))
+	_, err = data.Add(service)
+	b.Require().NoError(err)
+	_, err = data.Add(present)
+	b.Require().NoError(err)
+
+	b.Assert().Equal(basket_item.Items{service}, data.ChildrenOf(product))
+	b.Assert().Equal(basket_item.Items{product}, data.FindByType(basket_item.TypeProduct))
+	b.Assert().ElementsMatch(basket_item.Items{product, present}, data.FindByType(basket_item.TypeProduct, basket_item.TypePresent))
+
+	b.Run("parent changed after add", func() {
+		b.Require().NoError(present.MakeChildOf(product))
+		data.reindex(present)
+
+		b.Assert().ElementsMatch(basket_item.Items{service, present}, data.ChildrenOfRecursive(product))
+	})
+
+	b.Run("items changed bypassing basket data", func() {
+		another := basket_item.NewItem(
+			"4", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+		)
+		data.items[another.UniqId()] = another
+
+		b.Assert().ElementsMatch(basket_item.Items{product, another}, data.FindByType(basket_item.TypeProduct))
+		data.Remove(another)
+	})
+
+	b.Run("ordered by position", func() {
+		ordered := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		products := make(basket_item.Items, 0, 10)
+		for j := 10; j > 0; j-- {
+			item := basket_item.NewItem(
+				basket_item.ItemId(strconv.Itoa(j)), basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl",
+				catalog_types.PriceColumnRetail,
+			)
+			item.SetPosition(j)
+			ordered.items[item.UniqId()] = item
+			products = append(products, item)
+		}
+		want := append(basket_item.Items{}, products...).SortByPosition()
+
+		b.Assert().Equal(want, ordered.FindByType(basket_item.TypeProduct))
+		b.Assert().Equal(want, ordered.index().childrenOf(""))
+
+		products[0].SetPosition(0)
+		b.Assert().Equal(products[0], ordered.FindByType(basket_item.TypeProduct)[0])
+	})
+
+	b.Run("decode", func() {
+		buf, err := msgpack.Marshal(data)
+		b.Require().NoError(err)
+		decoded := &BasketData{}
+		b.Require().NoError(msgpack.Unmarshal(buf, decoded))
+
+		decodedProducts := decoded.FindByType(basket_item.TypeProduct)
+		b.Require().Len(decodedProducts, 1)
+		b.Assert().Len(decoded.ChildrenOfRecursive(decodedProducts[0]), 2)
+	})
+
+	b.Run("remove", func() {
+		data.Remove(product)
+
+		b.Assert().Empty(data.FindByType(basket_item.TypeProduct, basket_item.TypeSubcontractServiceForProduct))
+		b.Assert().Empty(data.ChildrenOf(product))
+		b.Assert().Equal(0, data.Count())
+	})
+
+	b.Run("clear", func() {
+		_, err := data.Add(product)
+		b.Require().NoError(err)
+		data.Clear()
+
+		b.Assert().Empty(data.FindByType(basket_item.TypeProduct))
+	})
+}
+
+func (b *BasketDataSuite) TestBasketData_Timestamps() {
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	b.Assert().True(data.CreatedAt().IsZero())
+	b.Assert().True(data.LastActivityAt().IsZero())
+
+	item := basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl",
+		catalog_types.PriceColumnRetail)
+	item.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	_, err := data.Add(item)
+	b.Require().NoError(err)
+	createdAt := data.CreatedAt()
+	b.Assert().False(createdAt.IsZero())
+	b.Assert().Equal(createdAt, data.ModifiedAt())
+
+	b.Run("commit unchanged basket", func() {
+		data.CommitChanges()
+		modifiedAt := createdAt.Add(-time.Hour)
+		data.modifiedAt = modifiedAt
+		data.CommitChanges()
+
+		b.Assert().Equal(modifiedAt, data.ModifiedAt())
+	})
+
+	b.Run("commit item changed outside of basket", func() {
+		modifiedAt := createdAt.Add(-time.Hour)
+		data.modifiedAt = modifiedAt
+		b.Require().NoError(item.SetCount(2))
+		data.CommitChanges()
+
+		b.Assert().True(data.ModifiedAt().After(modifiedAt))
+		b.Assert().Equal(createdAt, data.CreatedAt())
+	})
+
+	b.Run("commit system changes", func() {
+		modifiedAt := createdAt.Add(-time.Hour)
+		data.modifiedAt = modifiedAt
+		restore := data.withAudit(AuditActorSystem, AuditReasonRefresh)
+		b.Require().NoError(item.SetCount(3))
+		data.CommitChanges()
+		restore()
+
+		b.Assert().Equal(modifiedAt, data.ModifiedAt())
+		b.Assert().False(data.IsChanged())
+	})
+
+	b.Run("view", func() {
+		data.MarkViewed()
+
+		b.Assert().False(data.IsChanged())
+		b.Assert().Equal(data.ViewedAt(), data.LastActivityAt())
+	})
+
+	b.Run("remove", func() {
+		data.modifiedAt = createdAt.Add(-time.Hour)
+		data.Remove(item)
+
+		b.Assert().True(data.ModifiedAt().After(createdAt.Add(-time.Hour)))
+		b.Assert().Equal(createdAt, data.CreatedAt())
+	})
+}
+
+func (b *BasketDataSuite) TestBasketData_Position() {
+	newProduct := func(itemId basket_item.ItemId) *basket_item.Item {
+		item := basket_item.NewItem(
+			itemId, basket_item.TypeProduct, "name", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+		)
+		item.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+
+		return item
+	}
+
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	var items basket_item.Items
+	for _, itemId := range []basket_item.ItemId{"3", "1", "2"} {
+		item, err := data.Add(newProduct(itemId))
+		b.Require().NoError(err)
+		items = append(items, item)
+	}
+
+	for i, item := range items {
+		b.Assert().Equal(i+1, item.Position())
+		b.Assert().False(item.AddedAt().IsZero())
+	}
+	b.Assert().Equal(items, data.All())
+
+	b.Run("merged item keeps position", func() {
+		_, err := data.Add(newProduct("3"))
+		b.Require().NoError(err)
+
+		b.Assert().Equal(1, items[0].Position())
+		b.Assert().Equal(items, data.All())
+	})
+
+	b.Run("decode", func() {
+		buf, err := msgpack.Marshal(data)
+		b.Require().NoError(err)
+		decoded := &BasketData{}
+		b.Require().NoError(msgpack.Unmarshal(buf, decoded))
+
+		var itemIds []basket_item.ItemId
+		for _, item := range decoded.All() {
+			itemIds = append(itemIds, item.ItemId())
+		}
+		b.Assert().Equal([]basket_item.ItemId{"3", "1", "2"}, itemIds)
+	})
+}
+
+func (b *BasketDataSuite) TestBasketData_PendingInfos() {
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	newInfo := func(id basket_item.InfoId, createdAt time.Time) *basket_item.Info {
+		info := basket_item.NewInfo(id, "message")
+		info.SetCreatedAt(createdAt)
+
+		return info
+	}
+
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	product, err := data.Add(basket_item.NewItem(
+		"1", basket_item.TypeProduct, "name", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	))
+	b.Require().NoError(err)
+	firstPriceChange := newInfo(basket_item.InfoIdPriceChanged, createdAt)
+	secondPriceChange := newInfo(basket_item.InfoIdPriceChanged, createdAt.Add(2*time.Hour))
+	product.AddInfo(firstPriceChange, secondPriceChange)
+	removedProduct := basket_item.NewItem(
+		"2", basket_item.TypeProduct, "removed", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+	)
+	removed := NewInfo(removedProduct, newInfo(basket_item.InfoIdPositionRemoved, createdAt.Add(time.Hour)))
+	data.infos = append(data.infos, removed)
+
+	b.Run("all", func() {
+		b.Assert().Equal([]*Info{
+			NewInfo(product, firstPriceChange),
+			removed,
+			NewInfo(product, secondPriceChange),
+		}, data.PendingInfos(time.Time{}))
+	})
+
+	b.Run("since", func() {
+		b.Assert().Equal([]*Info{
+			removed,
+			NewInfo(product, secondPriceChange),
+		}, data.PendingInfos(createdAt))
+	})
+
+	b.Run("expire", func() {
+		policy := basket_item.InfoExpiryPolicy{basket_item.InfoIdPriceChanged: time.Hour}
+		data.ExpireInfos(policy, createdAt.Add(2*time.Hour))
+
+		b.Assert().Equal([]*Info{removed, NewInfo(product, secondPriceChange)}, data.PendingInfos(time.Time{}))
+	})
+
+	b.Run("commit", func() {
+		data.CommitInfos(basket_item.InfoIdPriceChanged, basket_item.InfoIdPositionRemoved)
+
+		b.Assert().Empty(data.PendingInfos(time.Time{}))
+		b.Assert().Empty(data.Infos())
+	})
+}
+
+// newBenchmarkBasketData корзина B2B пользователя: 100 товаров, у каждого из которых по две услуги
+func newBenchmarkBasketData(b *testing.B) *BasketData {
+	data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+	for i := 0; i < 100; i++ {
+		product := basket_item.NewItem(
+			basket_item.ItemId(strconv.Itoa(i)), basket_item.TypeProduct, "", "", 1, 100, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		)
+		if _, err := data.Add(product); err != nil {
+			b.Fatal(err)
+		}
+
+		for serviceIdPrefix, serviceType := range map[string]basket_item.Type{
+			"s": basket_item.TypeSubcontractServiceForProduct,
+			"i": basket_item.TypeInsuranceServiceForProduct,
+		} {
+			service := basket_item.NewItem(
+				basket_item.ItemId(serviceIdPrefix+strconv.Itoa(i)), serviceType, "", "", 1, 10, 0,
+				"msk_cl", catalog_types.PriceColumnRetail,
+			)
+			if err := product.AddChild(service); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := data.Add(service); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return data
+}
+
+func BenchmarkBasketData_ChildrenOfRecursive(b *testing.B) {
+	data := newBenchmarkBasketData(b)
+	products := data.FindByType(basket_item.TypeProduct)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, product := range products {
+			data.ChildrenOfRecursive(product)
+		}
+	}
+}
+
+func BenchmarkFinders_ChildrenOfRecursive(b *testing.B) {
+	data := newBenchmarkBasketData(b)
+	products := data.FindByType(basket_item.TypeProduct)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, product := range products {
+			data.Find(Finders.ChildrenOfRecursive(product))
+		}
+	}
+}
+
+func BenchmarkBasketData_FindByType(b *testing.B) {
+	data := newBenchmarkBasketData(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data.FindByType(basket_item.TypeSubcontractServiceForProduct)
+	}
+}
+
+func BenchmarkFinders_ByType(b *testing.B) {
+	data := newBenchmarkBasketData(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data.Find(Finders.ByType(basket_item.TypeSubcontractServiceForProduct))
+	}
+}
+
+func BenchmarkBasketData_AddRemove(b *testing.B) {
+	data := newBenchmarkBasketData(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		product := basket_item.NewItem(
+			"new", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+		)
+		if _, err := data.Add(product); err != nil {
+			b.Fatal(err)
+		}
+		data.Remove(product)
+	}
+}
+
 func TestBasketDataSuite(t *testing.T) {
 	suite.Run(t, new(BasketDataSuite))
 }
@@ -2,7 +2,6 @@ package basket
 
 import (
 	citizap_factory "go.citilink.cloud/citizap/factory"
-	database "go.citilink.cloud/libdatabase"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	"go.citilink.cloud/order/internal/order/bonus/bonuses_for_payment"
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
@@ -13,29 +12,35 @@ type BasketFactory struct {
 	itemFactory   basket_item.ItemFactory
 	productClient productv1.ProductAPIClient
 	itemRefresher itemRefresher
-	db            database.DB
+	confRegistry  ConfigurationRegistry
 	*markingOptions
 	*subcontractServiceChangeOptions
 	bonusesForPaymentsCalculator *bonuses_for_payment.BonusesForPaymentAgent
+	confOptions                  *ConfigurationOptions
+	faultScenarios               *FaultScenarioRegistry
 }
 
 func NewBasketFactory(
 	itemFactory basket_item.ItemFactory,
 	productClient productv1.ProductAPIClient,
 	itemRefresher itemRefresher,
-	db database.DB,
+	confRegistry ConfigurationRegistry,
 	mrkOpts *markingOptions,
 	sbcrOpts *subcontractServiceChangeOptions,
 	bonusesForPaymentsCalculator *bonuses_for_payment.BonusesForPaymentAgent,
+	confOptions *ConfigurationOptions,
+	faultScenarios *FaultScenarioRegistry,
 ) *BasketFactory {
 	return &BasketFactory{
 		itemFactory:                     itemFactory,
-		productClient:                   productClient,
+		productClient:                   faultScenarios.WrapProductClient(productClient),
 		itemRefresher:                   itemRefresher,
-		db:                              db,
+		confRegistry:                    confRegistry,
 		markingOptions:                  mrkOpts,
 		subcontractServiceChangeOptions: sbcrOpts,
 		bonusesForPaymentsCalculator:    bonusesForPaymentsCalculator,
+		confOptions:                     confOptions,
+		faultScenarios:                  faultScenarios,
 	}
 }
 
@@ -43,24 +48,32 @@ func (b *BasketFactory) CreateAnon(
 	basket *BasketData,
 	loggerFactory citizap_factory.Factory,
 ) *Basket {
-	return NewBasket(
+	bsk := NewBasket(
 		basket, b.itemFactory, nil, b.productClient, b.itemRefresher,
-		loggerFactory, b.db,
+		loggerFactory, b.confRegistry,
 		b.markingOptions,
 		b.subcontractServiceChangeOptions,
 		b.bonusesForPaymentsCalculator,
+		b.confOptions,
 	)
+	bsk.faultScenarios = b.faultScenarios
+
+	return bsk
 }
 
 func (b *BasketFactory) CreateUser(
 	basket *BasketData, user *userv1.User,
 	loggerFactory citizap_factory.Factory,
 ) *Basket {
-	return NewBasket(
+	bsk := NewBasket(
 		basket, b.itemFactory, user, b.productClient, b.itemRefresher,
-		loggerFactory, b.db,
+		loggerFactory, b.confRegistry,
 		b.markingOptions,
 		b.subcontractServiceChangeOptions,
 		b.bonusesForPaymentsCalculator,
+		b.confOptions,
 	)
+	bsk.faultScenarios = b.faultScenarios
+
+	return bsk
 }
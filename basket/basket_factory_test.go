@@ -9,10 +9,10 @@ import (
 	"testing"
 
 	"go.citilink.cloud/citizap/factory"
-	database "go.citilink.cloud/libdatabase"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
 	userv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/profile/user/v1"
+	productv1mock "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
 )
 
 func TestBasketFactorySuite(t *testing.T) {
@@ -36,7 +36,7 @@ func (s *BasketFactorySuite) TestBasketFactory_CreateAnon() {
 		itemFactory   basket_item.ItemFactory
 		productClient productv1.ProductAPIClient
 		itemRefresher itemRefresher
-		db            database.DB
+		confRegistry  ConfigurationRegistry
 	}
 	type args struct {
 		basket        *BasketData
@@ -62,7 +62,7 @@ func (s *BasketFactorySuite) TestBasketFactory_CreateAnon() {
 				itemFactory:                     tt.fields.itemFactory,
 				productClient:                   tt.fields.productClient,
 				itemRefresher:                   tt.fields.itemRefresher,
-				db:                              tt.fields.db,
+				confRegistry:                    tt.fields.confRegistry,
 				markingOptions:                  NewMarkingOptions(false, s.stringContainerMock),
 				subcontractServiceChangeOptions: NewSubcontractServiceChangeOptions(false),
 			}
@@ -77,7 +77,7 @@ func (s *BasketFactorySuite) TestBasketFactory_CreateUser() {
 		itemFactory   basket_item.ItemFactory
 		productClient productv1.ProductAPIClient
 		itemRefresher itemRefresher
-		db            database.DB
+		confRegistry  ConfigurationRegistry
 	}
 	type args struct {
 		basket        *BasketData
@@ -104,7 +104,7 @@ func (s *BasketFactorySuite) TestBasketFactory_CreateUser() {
 				itemFactory:                     tt.fields.itemFactory,
 				productClient:                   tt.fields.productClient,
 				itemRefresher:                   tt.fields.itemRefresher,
-				db:                              tt.fields.db,
+				confRegistry:                    tt.fields.confRegistry,
 				markingOptions:                  NewMarkingOptions(false, s.stringContainerMock),
 				subcontractServiceChangeOptions: NewSubcontractServiceChangeOptions(false),
 			}
@@ -119,11 +119,15 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 		itemFactory                      basket_item.ItemFactory
 		productClient                    productv1.ProductAPIClient
 		itemRefresher                    itemRefresher
-		db                               database.DB
+		confRegistry                     ConfigurationRegistry
 		markingEnabled                   bool
 		markingEnabledInCities           internal.StringsContainer
 		subcontractServicesChangeEnabled bool
+		faultScenarios                   *FaultScenarioRegistry
 	}
+	productClient := productv1mock.NewMockProductAPIClient(s.ctrl)
+	faultScenarios := NewFaultScenarioRegistry(true)
+	disabledFaultScenarios := NewFaultScenarioRegistry(false)
 	tests := []struct {
 		name    string
 		request args
@@ -135,7 +139,7 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 				itemFactory:                      nil,
 				productClient:                    nil,
 				itemRefresher:                    nil,
-				db:                               nil,
+				confRegistry:                     nil,
 				markingEnabled:                   false,
 				markingEnabledInCities:           s.stringContainerMock,
 				subcontractServicesChangeEnabled: false,
@@ -145,7 +149,7 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 					itemFactory:   nil,
 					productClient: nil,
 					itemRefresher: nil,
-					db:            nil,
+					confRegistry:  nil,
 					markingOptions: NewMarkingOptions(
 						false,
 						s.stringContainerMock,
@@ -156,6 +160,38 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 				}
 			},
 		},
+		{
+			name: "product client wrapped with enabled fault scenarios",
+			request: args{
+				productClient:          productClient,
+				markingEnabledInCities: s.stringContainerMock,
+				faultScenarios:         faultScenarios,
+			},
+			want: func() *BasketFactory {
+				return &BasketFactory{
+					productClient:                   &faultProductClient{ProductAPIClient: productClient, registry: faultScenarios},
+					markingOptions:                  NewMarkingOptions(false, s.stringContainerMock),
+					subcontractServiceChangeOptions: NewSubcontractServiceChangeOptions(false),
+					faultScenarios:                  faultScenarios,
+				}
+			},
+		},
+		{
+			name: "product client not wrapped with disabled fault scenarios",
+			request: args{
+				productClient:          productClient,
+				markingEnabledInCities: s.stringContainerMock,
+				faultScenarios:         disabledFaultScenarios,
+			},
+			want: func() *BasketFactory {
+				return &BasketFactory{
+					productClient:                   productClient,
+					markingOptions:                  NewMarkingOptions(false, s.stringContainerMock),
+					subcontractServiceChangeOptions: NewSubcontractServiceChangeOptions(false),
+					faultScenarios:                  disabledFaultScenarios,
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -165,7 +201,7 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 					tt.request.itemFactory,
 					tt.request.productClient,
 					tt.request.itemRefresher,
-					tt.request.db,
+					tt.request.confRegistry,
 					NewMarkingOptions(
 						tt.request.markingEnabled,
 						tt.request.markingEnabledInCities,
@@ -174,6 +210,8 @@ func (s *BasketFactorySuite) TestNewBasketFactory() {
 						tt.request.subcontractServicesChangeEnabled,
 					),
 					nil,
+					nil,
+					tt.request.faultScenarios,
 				)
 				want := tt.want()
 				if !reflect.DeepEqual(got, want) {
@@ -3,11 +3,16 @@ package basket_item
 import "fmt"
 
 // AllowResale структура с информацией о возможности приобретать позицию для перепродажи
+//
+//msgpack:len-err Item allowResale
+//msgpack:len-format (basket_item.AllowResale) incorrect len: %d
+//msgpack:err 1 Item allowResale isAllow
+//msgpack:err 2 Item allowResale commodityGroupName
 type AllowResale struct {
 	// Название товарной группы
-	commodityGroupName string
+	commodityGroupName string `msgpackidx:"2,string"`
 	// Признак, можно ли приобретать данную позицию с целью перепродажи
-	isAllow bool
+	isAllow bool `msgpackidx:"1,bool"`
 }
 
 func NewAllowResale(isAllow bool, commodityGroupName string) *AllowResale {
@@ -38,3 +43,20 @@ func (a *AllowResale) Message() string {
 
 	return "Товарная группа не подключена. Товар не может быть куплен для цели покупки «Перепродажа». Добавьте товарную группу в системе «Честный знак»"
 }
+
+// Problem проблема позиции, которую выбрали для перепродажи, хотя ее товарная группа не подключена. Сообщение
+// локализуется так же, как Message, и содержит название товарной группы, если оно известно
+func (a *AllowResale) Problem() *Problem {
+	if a.commodityGroupName != "" {
+		return NewLocalizedProblem(
+			ProblemPurchaseReasonNotAvailableForUser,
+			MessageCommodityGroupNotAvailableForResale,
+			NewStringMessageParam("commodityGroup", a.commodityGroupName),
+		)
+	}
+
+	return NewLocalizedProblem(
+		ProblemPurchaseReasonNotAvailableForUser,
+		MessageUnknownCommodityGroupNotAvailableForResale,
+	)
+}
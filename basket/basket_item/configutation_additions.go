@@ -2,9 +2,10 @@ package basket_item
 
 import "sync"
 
+//msgpack:len-err (basket_item.ConfiguratorItemAdditions) len doesn't match
 type ConfiguratorItemAdditions struct {
-	ConfId   string       // 1
-	ConfType ConfType     // 2
+	ConfId   string       `msgpackidx:"1,string,get=GetConfId,set=SetConfId"`
+	ConfType ConfType     `msgpackidx:"2,int,get=GetConfType,set=SetConfType"`
 	mx       sync.RWMutex `msgpack:"-"`
 }
 
@@ -76,8 +76,8 @@ func (f *digitalServiceItemFactory) Create(
 	)
 
 	if price.Price == 0 {
-		digitalServiceItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"услугу купить невозможно"))
+		digitalServiceItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageServiceNotPurchasable))
 	}
 
 	// цифровая услуга так же может быть прикреплена
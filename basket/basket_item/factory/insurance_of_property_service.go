@@ -76,8 +76,8 @@ func (i *insuranceOfPropertyServiceItemFactory) Create(
 	)
 
 	if price.Price == 0 {
-		propertyInsuranceServiceItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"услугу купить невозможно"))
+		propertyInsuranceServiceItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageServiceNotPurchasable))
 	}
 
 	propertyInsuranceServiceItem.Additions().SetService(
@@ -97,8 +97,8 @@ searchServiceLoop:
 	)
 
 	if price == 0 {
-		insuranceServiceItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"услугу купить невозможно"))
+		insuranceServiceItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageServiceNotPurchasable))
 	}
 
 	insuranceServiceItem.Additions().SetService(
@@ -19,6 +19,8 @@ type productItemFactory struct {
 	facadeProductClient facade_productv1.ProductAPIClient
 }
 
+// NewProductItemFactory создает фабрику товаров. Чтобы к создаваемым товарам применялись сценарии внедрения сбоев,
+// передается клиент каталога, обернутый basket.FaultScenarioRegistry.WrapProductClient
 func NewProductItemFactory(
 	productClient productv1.ProductAPIClient,
 	facadeProductClient facade_productv1.ProductAPIClient,
@@ -111,13 +113,13 @@ func (f *productItemFactory) Create(
 	productItem.SetIgnoreFairPrice(ignoreFairPrice)
 
 	if !facadeProductInfo.GetInfo().GetIsAvailable() {
-		productItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"товара нет в наличии"))
+		productItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageProductNotAvailable))
 	}
 
 	if price.Price == 0 {
-		productItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"товар купить невозможно"))
+		productItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageProductNotPurchasable))
 	}
 
 	productItem.SetCountMultiplicity(int(productInfo.GetRegional().GetMultiplicity()))
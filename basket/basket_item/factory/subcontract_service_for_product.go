@@ -102,8 +102,8 @@ searchServiceLoop:
 	)
 
 	if price == 0 {
-		subcontractServiceItem.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-			"услугу купить невозможно"))
+		subcontractServiceItem.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+			basket_item.MessageServiceNotPurchasable))
 	}
 
 	subcontractServiceItem.Additions().SetSubcontractServiceForProduct(&basket_item.SubcontractItemAdditions{})
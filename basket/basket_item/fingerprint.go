@@ -0,0 +1,249 @@
+package basket_item
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// FingerprintVersion версия алгоритма отпечатков. Увеличивается при любом изменении списка полей отпечатка или способа
+// их записи, отпечатки разных версий между собой не сравниваются
+const FingerprintVersion = 2
+
+// FingerprintScope область отпечатка - набор данных корзины, от которых зависит результат определенной подсистемы.
+// Изменение данных, не входящих в область, не меняет ее отпечаток и не сбрасывает кэши этой подсистемы
+type FingerprintScope string
+
+const (
+	// FingerprintScopeFull данные, по которым определяется изменение корзины (см. IsChanged)
+	FingerprintScopeFull FingerprintScope = "full"
+	// FingerprintScopePayment данные, от которых зависит доступность способов оплаты
+	FingerprintScopePayment FingerprintScope = "payment"
+	// FingerprintScopeDelivery данные, от которых зависит расчет доставки
+	FingerprintScopeDelivery FingerprintScope = "delivery"
+)
+
+// FingerprintScopes все области отпечатков
+var FingerprintScopes = []FingerprintScope{FingerprintScopeFull, FingerprintScopePayment, FingerprintScopeDelivery}
+
+type fingerprintField struct {
+	name  string
+	value func(i *Item) string
+}
+
+type fingerprintScopeOptions struct {
+	fields []fingerprintField
+	// isIncluded определяет, участвует ли позиция в отпечатке области
+	isIncluded func(i *Item) bool
+}
+
+var (
+	fingerprintFieldSpaceId = fingerprintField{"space_id", func(i *Item) string {
+		return string(i.SpaceId())
+	}}
+	fingerprintFieldPriceColumn = fingerprintField{"price_column", func(i *Item) string {
+		return strconv.Itoa(int(i.PriceColumn()))
+	}}
+	fingerprintFieldItemId = fingerprintField{"item_id", func(i *Item) string {
+		return string(i.ItemId())
+	}}
+	fingerprintFieldType = fingerprintField{"type", func(i *Item) string {
+		return string(i.Type())
+	}}
+	fingerprintFieldParentUniqId = fingerprintField{"parent_uniq_id", func(i *Item) string {
+		return string(i.ParentUniqId())
+	}}
+	fingerprintFieldCount = fingerprintField{"count", func(i *Item) string {
+		return strconv.Itoa(i.Count())
+	}}
+	fingerprintFieldPrice = fingerprintField{"price", func(i *Item) string {
+		return strconv.Itoa(i.Price())
+	}}
+	fingerprintFieldSelected = fingerprintField{"selected", func(i *Item) string {
+		return strconv.FormatBool(i.IsSelected())
+	}}
+	fingerprintFieldPrepaymentMandatory = fingerprintField{"prepayment_mandatory", func(i *Item) string {
+		return strconv.FormatBool(i.IsPrepaymentMandatory())
+	}}
+	fingerprintFieldCategoryId = fingerprintField{"category_id", func(i *Item) string {
+		if product := fingerprintProductAdditions(i); product != nil {
+			return strconv.Itoa(int(product.CategoryId()))
+		}
+
+		return ""
+	}}
+	fingerprintFieldFnsTracked = fingerprintField{"fns_tracked", func(i *Item) string {
+		if product := fingerprintProductAdditions(i); product != nil {
+			return strconv.FormatBool(product.IsFnsTracked())
+		}
+
+		return ""
+	}}
+	fingerprintFieldMarked = fingerprintField{"marked", func(i *Item) string {
+		if product := fingerprintProductAdditions(i); product != nil {
+			return strconv.FormatBool(product.IsMarked())
+		}
+
+		return ""
+	}}
+	fingerprintFieldCreditPrograms = fingerprintField{"credit_programs", func(i *Item) string {
+		product := fingerprintProductAdditions(i)
+		if product == nil {
+			return ""
+		}
+
+		programs := make([]string, 0, len(product.CreditPrograms()))
+		for _, program := range product.CreditPrograms() {
+			programs = append(programs, string(program))
+		}
+
+		return strings.Join(programs, ",")
+	}}
+	fingerprintFieldServiceCredit = fingerprintField{"service_credit", func(i *Item) string {
+		if i.Additions() == nil || i.Additions().GetService() == nil {
+			return ""
+		}
+
+		service := i.Additions().GetService()
+
+		return strconv.FormatBool(service.GetIsCreditAvail()) + "," +
+			strconv.FormatBool(service.GetIsAvailableForInstallments())
+	}}
+	fingerprintFieldAvailInStore = fingerprintField{"avail_in_store", func(i *Item) string {
+		if product := fingerprintProductAdditions(i); product != nil {
+			return strconv.FormatBool(product.IsAvailInStore())
+		}
+
+		return ""
+	}}
+	fingerprintFieldAvailForDPD = fingerprintField{"avail_for_dpd", func(i *Item) string {
+		if product := fingerprintProductAdditions(i); product != nil {
+			return strconv.FormatBool(product.IsAvailForDPD())
+		}
+
+		return ""
+	}}
+)
+
+// fingerprintScopes список полей позиции для каждой области. Порядок полей важен, при любом изменении списка нужно
+// увеличить FingerprintVersion
+var fingerprintScopes = map[FingerprintScope]*fingerprintScopeOptions{
+	// поля совпадают с полями отпечатка первой версии, чтобы не менять поведение IsChanged
+	FingerprintScopeFull: {
+		fields: []fingerprintField{
+			fingerprintFieldSpaceId,
+			fingerprintFieldPriceColumn,
+			fingerprintFieldItemId,
+			fingerprintFieldCount,
+			fingerprintFieldPrice,
+		},
+		isIncluded: func(i *Item) bool {
+			return true
+		},
+	},
+	// состав конфигурации не учитывается: его стоимость входит в цену конфигурации, а выбор для покупки совпадает с
+	// выбором самой конфигурации
+	FingerprintScopePayment: {
+		fields: []fingerprintField{
+			fingerprintFieldItemId,
+			fingerprintFieldType,
+			fingerprintFieldCount,
+			fingerprintFieldPrice,
+			fingerprintFieldSelected,
+			fingerprintFieldPrepaymentMandatory,
+			fingerprintFieldCategoryId,
+			fingerprintFieldFnsTracked,
+			fingerprintFieldMarked,
+			fingerprintFieldCreditPrograms,
+			fingerprintFieldServiceCredit,
+		},
+		isIncluded: func(i *Item) bool {
+			return !i.Type().IsPartOfConfiguration()
+		},
+	},
+	// на доставку влияют только физические товары: их кол-во и доступность, но не цена
+	FingerprintScopeDelivery: {
+		fields: []fingerprintField{
+			fingerprintFieldItemId,
+			fingerprintFieldType,
+			fingerprintFieldParentUniqId,
+			fingerprintFieldCount,
+			fingerprintFieldSelected,
+			fingerprintFieldAvailInStore,
+			fingerprintFieldAvailForDPD,
+		},
+		isIncluded: func(i *Item) bool {
+			return i.Type().IsProduct() || i.Type().IsConfiguration()
+		},
+	},
+}
+
+func fingerprintProductAdditions(i *Item) *ProductItemAdditions {
+	if i.Additions() == nil {
+		return nil
+	}
+
+	return i.Additions().GetProduct()
+}
+
+// IsFingerprintIncluded участвует ли позиция в отпечатке указанной области
+func (i *Item) IsFingerprintIncluded(scope FingerprintScope) bool {
+	options, ok := fingerprintScopes[scope]
+	if !ok {
+		return false
+	}
+
+	return options.isIncluded(i)
+}
+
+// ScopedFingerprint отпечаток позиции в указанной области. Для неизвестной области возвращается пустая строка
+func (i *Item) ScopedFingerprint(scope FingerprintScope) string {
+	options, ok := fingerprintScopes[scope]
+	if !ok {
+		return ""
+	}
+
+	builder := NewFingerprintBuilder(scope)
+	for _, field := range options.fields {
+		builder.Add(field.name, field.value(i))
+	}
+
+	return builder.String()
+}
+
+// FingerprintBuilder собирает отпечаток из именованных полей. Каждое поле записывается вместе с длиной имени и
+// значения, поэтому разные наборы полей не дают одинаковый поток данных для хэша
+type FingerprintBuilder struct {
+	h hash.Hash
+}
+
+func NewFingerprintBuilder(scope FingerprintScope) *FingerprintBuilder {
+	b := &FingerprintBuilder{h: sha256.New()}
+	b.Add("version", strconv.Itoa(FingerprintVersion))
+	b.Add("scope", string(scope))
+
+	return b
+}
+
+func (b *FingerprintBuilder) Add(name string, value string) *FingerprintBuilder {
+	// запись в хэш никогда не возвращает ошибку, поэтому ошибки здесь опущены
+	_ = binary.Write(b.h, binary.LittleEndian, uint32(len(name)))
+	_, _ = b.h.Write([]byte(name))
+	_ = binary.Write(b.h, binary.LittleEndian, uint32(len(value)))
+	_, _ = b.h.Write([]byte(value))
+
+	return b
+}
+
+// String возвращает отпечаток в виде "<версия>:<sha256 в hex>"
+func (b *FingerprintBuilder) String() string {
+	return strconv.Itoa(FingerprintVersion) + ":" + hex.EncodeToString(b.h.Sum(nil))
+}
+
+// IsCurrentFingerprintVersion проверяет, что отпечаток посчитан текущей версией алгоритма
+func IsCurrentFingerprintVersion(fingerprint string) bool {
+	return strings.HasPrefix(fingerprint, strconv.Itoa(FingerprintVersion)+":")
+}
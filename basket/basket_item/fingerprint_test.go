@@ -0,0 +1,166 @@
+package basket_item
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"testing"
+)
+
+func newTestFingerprintItem(itemType Type) *Item {
+	item := NewItem("1", itemType, "name", "image", 1, 100, 10, "msk_cl", catalog_types.PriceColumnRetail)
+	item.Additions().SetProduct(&ProductItemAdditions{})
+
+	return item
+}
+
+func TestItem_ScopedFingerprint(t *testing.T) {
+	tests := []struct {
+		name        string
+		itemType    Type
+		change      func(item *Item)
+		wantChanged map[FingerprintScope]bool
+	}{
+		{
+			name:     "selection",
+			itemType: TypeProduct,
+			change: func(item *Item) {
+				item.SetIsSelected(!item.IsSelected())
+			},
+			wantChanged: map[FingerprintScope]bool{
+				FingerprintScopeFull:     false,
+				FingerprintScopePayment:  true,
+				FingerprintScopeDelivery: true,
+			},
+		},
+		{
+			name:     "price",
+			itemType: TypeProduct,
+			change: func(item *Item) {
+				item.SetPrice(200)
+			},
+			wantChanged: map[FingerprintScope]bool{
+				FingerprintScopeFull:     true,
+				FingerprintScopePayment:  true,
+				FingerprintScopeDelivery: false,
+			},
+		},
+		{
+			name:     "bonus",
+			itemType: TypeProduct,
+			change: func(item *Item) {
+				item.SetBonus(20)
+			},
+			wantChanged: map[FingerprintScope]bool{
+				FingerprintScopeFull:     false,
+				FingerprintScopePayment:  false,
+				FingerprintScopeDelivery: false,
+			},
+		},
+		{
+			name:     "fns tracked",
+			itemType: TypeProduct,
+			change: func(item *Item) {
+				item.Additions().GetProduct().SetIsFnsTracked(true)
+			},
+			wantChanged: map[FingerprintScope]bool{
+				FingerprintScopeFull:     false,
+				FingerprintScopePayment:  true,
+				FingerprintScopeDelivery: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			item := newTestFingerprintItem(tt.itemType)
+			before := make(map[FingerprintScope]string)
+			for _, scope := range FingerprintScopes {
+				before[scope] = item.ScopedFingerprint(scope)
+				assert.True(t, IsCurrentFingerprintVersion(before[scope]))
+			}
+
+			tt.change(item)
+
+			for _, scope := range FingerprintScopes {
+				assert.Equal(t, tt.wantChanged[scope], before[scope] != item.ScopedFingerprint(scope), scope)
+			}
+		})
+	}
+}
+
+func TestItem_ScopedFingerprint_ScopesDiffer(t *testing.T) {
+	item := newTestFingerprintItem(TypeProduct)
+
+	assert.NotEqual(t, item.ScopedFingerprint(FingerprintScopePayment), item.ScopedFingerprint(FingerprintScopeDelivery))
+	assert.Equal(t, item.Fingerprint(), item.ScopedFingerprint(FingerprintScopeFull))
+	assert.Equal(t, "", item.ScopedFingerprint("unknown"))
+}
+
+func TestItem_IsFingerprintIncluded(t *testing.T) {
+	tests := []struct {
+		itemType Type
+		want     map[FingerprintScope]bool
+	}{
+		{
+			itemType: TypeProduct,
+			want: map[FingerprintScope]bool{
+				FingerprintScopeFull:     true,
+				FingerprintScopePayment:  true,
+				FingerprintScopeDelivery: true,
+			},
+		},
+		{
+			itemType: TypeDigitalService,
+			want: map[FingerprintScope]bool{
+				FingerprintScopeFull:     true,
+				FingerprintScopePayment:  true,
+				FingerprintScopeDelivery: false,
+			},
+		},
+		{
+			itemType: TypeConfigurationProductService,
+			want: map[FingerprintScope]bool{
+				FingerprintScopeFull:     true,
+				FingerprintScopePayment:  false,
+				FingerprintScopeDelivery: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.itemType), func(t *testing.T) {
+			item := newTestFingerprintItem(tt.itemType)
+			for _, scope := range FingerprintScopes {
+				assert.Equal(t, tt.want[scope], item.IsFingerprintIncluded(scope), scope)
+			}
+		})
+	}
+}
+
+func TestIsCurrentFingerprintVersion(t *testing.T) {
+	assert.True(t, IsCurrentFingerprintVersion(NewFingerprintBuilder(FingerprintScopeFull).String()))
+	assert.False(t, IsCurrentFingerprintVersion("1149408419158322160"))
+	assert.False(t, IsCurrentFingerprintVersion(""))
+}
+
+func TestFingerprintBuilder_Add(t *testing.T) {
+	// без длины полей оба набора дали бы одинаковый поток данных для хэша
+	left := NewFingerprintBuilder(FingerprintScopeFull).Add("a", "bc").String()
+	right := NewFingerprintBuilder(FingerprintScopeFull).Add("ab", "c").String()
+
+	assert.NotEqual(t, left, right)
+}
+
+func TestItem_DecodeMsgpack_LegacyFingerprint(t *testing.T) {
+	item := newTestFingerprintItem(TypeProduct)
+	item.commitFingerprint = "1149408419158322160"
+
+	buf, err := msgpack.Marshal(item)
+	assert.NoError(t, err)
+	decoded := &Item{}
+	assert.NoError(t, msgpack.Unmarshal(buf, decoded))
+
+	assert.False(t, decoded.IsChanged())
+	assert.True(t, IsCurrentFingerprintVersion(decoded.commitFingerprint))
+}
@@ -1,6 +1,9 @@
 package basket_item
 
-import "sync"
+import (
+	"sync"
+	"time"
+)
 
 type InfoId int
 
@@ -14,18 +17,43 @@ const (
 	InfoIdPositionRemoved
 	// InfoIdPositionChanged позиция заменена
 	InfoIdPositionChanged
+	// InfoIdPositionSplit позиция разделена, часть кол-ва перенесена в конфигурацию
+	InfoIdPositionSplit
 )
 
 func NewInfo(id InfoId, message string) *Info {
-	return &Info{id: id, message: message, additions: &InfoAdditions{}}
+	return &Info{id: id, message: message, additions: &InfoAdditions{}, createdAt: time.Now().UTC()}
+}
+
+// NewLocalizedInfo создает информацию с сообщением из каталога переводов. Вместе с ключом сохраняется текст
+// сообщения на русском языке, который показывается, если перевода на нужный язык нет
+func NewLocalizedInfo(id InfoId, key MessageKey, params ...*MessageParam) *Info {
+	return &Info{
+		id:            id,
+		message:       renderStored(key, params),
+		additions:     &InfoAdditions{},
+		messageKey:    key,
+		messageParams: params,
+		createdAt:     time.Now().UTC(),
+	}
 }
 
+//go:generate go run ../../tools/msgpackgen -output=info_msgpack.go -types=Info,InfoAdditions,PriceChangedInfoAddition,CountMoreThenAvailInfoAdditions,ChangedItemInfoAdditions
+
 // Info информация о позиции корзины
+//
+//msgpack:min 3
+//msgpack:len-err incorrect Info length
 type Info struct {
-	id        InfoId         // 1
-	message   string         // 2
-	additions *InfoAdditions // 3
-	mx        sync.RWMutex   `msgpack:"-"`
+	id        InfoId         `msgpackidx:"1,int,get=Id"`
+	message   string         `msgpackidx:"2,string,get=Message"`
+	additions *InfoAdditions `msgpackidx:"3,get=Additionals"`
+	// Ключ сообщения в каталоге переводов, пустой у информации, созданной до появления ключей
+	messageKey    MessageKey      `msgpackidx:"4,string,get=MessageKey"`
+	messageParams []*MessageParam `msgpackidx:"5,slice,nilempty,get=MessageParams"`
+	// Время появления информации, нулевое у информации, созданной до появления времени
+	createdAt time.Time    `msgpackidx:"6,unixnano,get=CreatedAt"`
+	mx        sync.RWMutex `msgpack:"-"`
 }
 
 func (i *Info) Id() InfoId {
@@ -42,6 +70,47 @@ func (i *Info) Message() string {
 	return i.message
 }
 
+func (i *Info) MessageKey() MessageKey {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	return i.messageKey
+}
+
+func (i *Info) MessageParams() []*MessageParam {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	return i.messageParams
+}
+
+// LocalizedMessage текст сообщения на указанном языке, либо сохраненный текст, если перевода нет
+func (i *Info) LocalizedMessage(catalog *MessageCatalog, language Language) string {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	message, ok := catalog.Render(language, i.messageKey, i.messageParams)
+	if !ok {
+		return i.message
+	}
+
+	return message
+}
+
+func (i *Info) CreatedAt() time.Time {
+	i.mx.RLock()
+	defer i.mx.RUnlock()
+
+	return i.createdAt
+}
+
+func (i *Info) SetCreatedAt(createdAt time.Time) {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	i.createdAt = createdAt.UTC()
+}
+
 func (i *Info) Additionals() *InfoAdditions {
 	i.mx.RLock()
 	defer i.mx.RUnlock()
@@ -56,35 +125,72 @@ func (i *Info) SetAdditions(additions *InfoAdditions) {
 	i.additions = additions
 }
 
+// InfoExpiryPolicy время жизни информации о позиции по идентификатору информации. Информация, для которой время жизни
+// не задано, а также информация без времени появления, хранится до подтверждения клиентом
+type InfoExpiryPolicy map[InfoId]time.Duration
+
+// DefaultInfoExpiryPolicy время жизни информации о позиции по умолчанию. Информация о нехватке товара в наличии не
+// истекает, ее снимает обновление корзины, когда кол-во становится доступным
+func DefaultInfoExpiryPolicy() InfoExpiryPolicy {
+	return InfoExpiryPolicy{
+		InfoIdPriceChanged:    7 * 24 * time.Hour,
+		InfoIdPositionRemoved: 3 * 24 * time.Hour,
+		InfoIdPositionChanged: 3 * 24 * time.Hour,
+		InfoIdPositionSplit:   24 * time.Hour,
+	}
+}
+
+// IsExpired проверяет, истекла ли информация на момент now
+func (p InfoExpiryPolicy) IsExpired(info *Info, now time.Time) bool {
+	ttl, ok := p[info.Id()]
+	createdAt := info.CreatedAt()
+	if !ok || ttl <= 0 || createdAt.IsZero() {
+		return false
+	}
+
+	return now.Sub(createdAt) >= ttl
+}
+
 // InfoAdditions уточняющая информация по позиции
+//
+//msgpack:min 1
+//msgpack:len-err incorrect InfoAdditions length
+//msgpack:decode-err 3
 type InfoAdditions struct {
-	PriceChanged       PriceChangedInfoAddition        // 1
-	CountMoreThenAvail CountMoreThenAvailInfoAdditions // 2
-	ChangedItem        ChangedItemInfoAdditions        // 3
+	PriceChanged       PriceChangedInfoAddition        `msgpackidx:"1"`
+	CountMoreThenAvail CountMoreThenAvailInfoAdditions `msgpackidx:"2"`
+	ChangedItem        ChangedItemInfoAdditions        `msgpackidx:"3"`
 }
 
 // PriceChangedInfoAddition информация об изменении цены
+//
+//msgpack:len-err incorrect PriceChangedInfoAddition length
 type PriceChangedInfoAddition struct {
-	From int // 1
-	To   int // 2
+	From int `msgpackidx:"1,int"`
+	To   int `msgpackidx:"2,int"`
 }
 
 // CountMoreThenAvailInfoAdditions информация о том, что по позиции доступно товаров меньше, чем добавлено в корзину
+//
+//msgpack:len-err incorrect CountMoreThenAvailInfoAdditions length
 type CountMoreThenAvailInfoAdditions struct {
-	AvailCount int // 1
+	AvailCount int `msgpackidx:"1,int"`
 }
 
 // ChangedItemInfoAdditions Информация о замененном товаре/услуге.
 // Указывается для идентификатора INFO_ID_POSITION_CHANGED
+//
+//msgpack:decode-err
+//msgpack:len-format (basket_item.ChangedItemInfoAdditions) len doesn't match: %d
 type ChangedItemInfoAdditions struct {
 	// Идентификатор позиции (не уникален)
-	ItemId string // 1
+	ItemId string `msgpackidx:"1,string"`
 	// Уникальный идентификатор позиции
-	UniqId string // 2
+	UniqId string `msgpackidx:"2,string"`
 	// Кол-во позиции (например 2 телефона)
-	Count int // 3
+	Count int `msgpackidx:"3,int"`
 	// Название позиции
-	Name string // 4
+	Name string `msgpackidx:"4,string"`
 	// Цена за позицию
-	Price int // 5
+	Price int `msgpackidx:"5,int"`
 }
@@ -1,101 +1,155 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
 package basket_item
 
 import (
 	"fmt"
 	"go.citilink.cloud/order/internal"
 	"gopkg.in/vmihailenco/msgpack.v2"
+	"time"
 )
 
 func (i *Info) EncodeMsgpack(e *msgpack.Encoder) error {
-	if err := e.EncodeArrayLen(3); err != nil {
+	if err := e.EncodeArrayLen(6); err != nil {
 		return err
 	}
 
 	if err := e.EncodeInt(int(i.Id())); err != nil { // 1
 		return err
 	}
-
 	if err := e.EncodeString(i.Message()); err != nil { // 2
 		return err
 	}
-
 	if err := e.Encode(i.Additionals()); err != nil { // 3
 		return err
 	}
+	if err := e.EncodeString(string(i.MessageKey())); err != nil { // 4
+		return err
+	}
+	messageParams := i.MessageParams()
+	if err := e.EncodeArrayLen(len(messageParams)); err != nil { // 5
+		return err
+	}
+	for _, v := range messageParams {
+		if err := e.Encode(v); err != nil {
+			return err
+		}
+	}
+	var createdAt int64
+	if !i.CreatedAt().IsZero() {
+		createdAt = i.CreatedAt().UnixNano()
+	}
+	if err := e.EncodeInt64(createdAt); err != nil { // 6
+		return err
+	}
 
 	return nil
 }
 
 func (i *Info) DecodeMsgpack(d *msgpack.Decoder) error {
-	var err error
-	var l int
-	if l, err = d.DecodeArrayLen(); err != nil {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "Info array len")
 	}
 
-	if l != 3 {
-		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect Info length: %d", l), 0, "incorrect Info length")
+	if length < 3 || length > 6 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect Info length: %d", length), 0, "incorrect Info length")
 	}
 
-	if v, err := d.DecodeInt(); err != nil { // 1
+	id, err := d.DecodeInt() // 1
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 1, "Info id")
-	} else {
-		i.id = InfoId(v)
 	}
-	if v, err := d.DecodeString(); err != nil { // 2
+	i.id = InfoId(id)
+
+	message, err := d.DecodeString() // 2
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 2, "Info message")
-	} else {
-		i.message = v
 	}
+	i.message = message
+
 	if err := d.Decode(&i.additions); err != nil { // 3
 		return internal.NewMsgPackDecodeError(err, 3, "Info additions")
 	}
 
+	if length > 3 {
+		messageKey, err := d.DecodeString() // 4
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 4, "Info messageKey")
+		}
+		i.messageKey = MessageKey(messageKey)
+	}
+
+	if length > 4 {
+		messageParamsLen, err := d.DecodeArrayLen() // 5
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 5, "Info messageParams")
+		}
+		var messageParams []*MessageParam
+		if messageParamsLen > 0 {
+			messageParams = make([]*MessageParam, messageParamsLen)
+		}
+		for j := 0; j < messageParamsLen; j++ {
+			if err := d.Decode(&messageParams[j]); err != nil {
+				return internal.NewMsgPackDecodeError(err, 5, "Info messageParams")
+			}
+		}
+		i.messageParams = messageParams
+	}
+
+	if length > 5 {
+		createdAt, err := d.DecodeInt64() // 6
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 6, "Info createdAt")
+		}
+		if createdAt != 0 {
+			i.createdAt = time.Unix(0, createdAt).UTC()
+		}
+	}
+
 	return nil
 }
 
-func (a *InfoAdditions) EncodeMsgpack(e *msgpack.Encoder) error {
+func (i *InfoAdditions) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeArrayLen(3); err != nil {
 		return err
 	}
 
-	if err := e.Encode(&a.PriceChanged); err != nil { // 1
+	if err := e.Encode(&i.PriceChanged); err != nil { // 1
 		return err
 	}
-
-	if err := e.Encode(&a.CountMoreThenAvail); err != nil { // 2
+	if err := e.Encode(&i.CountMoreThenAvail); err != nil { // 2
 		return err
 	}
-
-	if err := e.Encode(&a.ChangedItem); err != nil { // 3
+	if err := e.Encode(&i.ChangedItem); err != nil { // 3
 		return err
 	}
 
 	return nil
 }
 
-func (a *InfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
+func (i *InfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
 	length, err := d.DecodeArrayLen()
 	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "InfoAdditions array len")
 	}
 
-	if length < 1 {
+	if length < 1 || length > 3 {
 		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect InfoAdditions length: %d", length), 0, "incorrect InfoAdditions length")
 	}
 
-	if err := d.Decode(&a.PriceChanged); err != nil { // 1
+	if err := d.Decode(&i.PriceChanged); err != nil { // 1
 		return internal.NewMsgPackDecodeError(err, 1, "InfoAdditions PriceChanged")
 	}
 
 	if length > 1 {
-		if err := d.Decode(&a.CountMoreThenAvail); err != nil { // 2
+		if err := d.Decode(&i.CountMoreThenAvail); err != nil { // 2
 			return internal.NewMsgPackDecodeError(err, 2, "InfoAdditions CountMoreThenAvail")
 		}
 	}
 
 	if length > 2 {
-		if err := d.Decode(&a.ChangedItem); err != nil { // 3
+		if err := d.Decode(&i.ChangedItem); err != nil { // 3
 			return internal.NewDecodeErr(err)
 		}
 	}
@@ -103,76 +157,73 @@ func (a *InfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
 	return nil
 }
 
-func (a *PriceChangedInfoAddition) EncodeMsgpack(e *msgpack.Encoder) error {
+func (p *PriceChangedInfoAddition) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeArrayLen(2); err != nil {
 		return err
 	}
 
-	if err := e.EncodeInt(a.From); err != nil {
+	if err := e.EncodeInt(p.From); err != nil { // 1
 		return err
 	}
-	if err := e.EncodeInt(a.To); err != nil {
+	if err := e.EncodeInt(p.To); err != nil { // 2
 		return err
 	}
 
 	return nil
 }
 
-func (a *PriceChangedInfoAddition) DecodeMsgpack(d *msgpack.Decoder) error {
-	var err error
-	var l int
-	if l, err = d.DecodeArrayLen(); err != nil {
+func (p *PriceChangedInfoAddition) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "PriceChangedInfoAddition array len")
 	}
 
-	if l != 2 {
-		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect PriceChangedInfoAddition length: %d", l), 0, "incorrect PriceChangedInfoAddition length")
+	if length != 2 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect PriceChangedInfoAddition length: %d", length), 0, "incorrect PriceChangedInfoAddition length")
 	}
 
-	if v, err := d.DecodeInt(); err != nil { // 1
+	from, err := d.DecodeInt() // 1
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 1, "PriceChangedInfoAddition From")
-	} else {
-		a.From = v
 	}
-	if v, err := d.DecodeInt(); err != nil { // 2
+	p.From = from
+
+	to, err := d.DecodeInt() // 2
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 2, "PriceChangedInfoAddition To")
-	} else {
-		a.To = v
 	}
+	p.To = to
 
 	return nil
 }
 
-func (a *CountMoreThenAvailInfoAdditions) EncodeMsgpack(e *msgpack.Encoder) error {
+func (c *CountMoreThenAvailInfoAdditions) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeArrayLen(1); err != nil {
 		return err
 	}
 
-	if err := e.EncodeInt(a.AvailCount); err != nil {
+	if err := e.EncodeInt(c.AvailCount); err != nil { // 1
 		return err
 	}
 
 	return nil
 }
 
-func (a *CountMoreThenAvailInfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
+func (c *CountMoreThenAvailInfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
 	length, err := d.DecodeArrayLen()
 	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "CountMoreThenAvailInfoAdditions array len")
 	}
 
 	if length != 1 {
-		return internal.NewMsgPackDecodeError(
-			fmt.Errorf("incorrect CountMoreThenAvailInfoAdditions length: %d", length),
-			0,
-			"incorrect CountMoreThenAvailInfoAdditions length")
+		return internal.NewMsgPackDecodeError(fmt.Errorf("incorrect CountMoreThenAvailInfoAdditions length: %d", length), 0, "incorrect CountMoreThenAvailInfoAdditions length")
 	}
 
-	if v, err := d.DecodeInt(); err != nil { // 1
+	availCount, err := d.DecodeInt() // 1
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 1, "CountMoreThenAvailInfoAdditions AvailCount")
-	} else {
-		a.AvailCount = v
 	}
+	c.AvailCount = availCount
 
 	return nil
 }
@@ -185,19 +236,15 @@ func (c *ChangedItemInfoAdditions) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeString(c.ItemId); err != nil { // 1
 		return err
 	}
-
 	if err := e.EncodeString(c.UniqId); err != nil { // 2
 		return err
 	}
-
 	if err := e.EncodeInt(c.Count); err != nil { // 3
 		return err
 	}
-
 	if err := e.EncodeString(c.Name); err != nil { // 4
 		return err
 	}
-
 	if err := e.EncodeInt(c.Price); err != nil { // 5
 		return err
 	}
@@ -212,39 +259,38 @@ func (c *ChangedItemInfoAdditions) DecodeMsgpack(d *msgpack.Decoder) error {
 	}
 
 	if length != 5 {
-		return internal.NewDecodeErr(
-			fmt.Errorf("(basket_item.ChangedItemInfoAdditions) len doesn't match: %d", length))
+		return internal.NewDecodeErr(fmt.Errorf("(basket_item.ChangedItemInfoAdditions) len doesn't match: %d", length))
 	}
 
-	if v, err := d.DecodeString(); err != nil { // 1
+	itemId, err := d.DecodeString() // 1
+	if err != nil {
 		return internal.NewDecodeErr(err)
-	} else {
-		c.ItemId = v
 	}
+	c.ItemId = itemId
 
-	if v, err := d.DecodeString(); err != nil { // 2
+	uniqId, err := d.DecodeString() // 2
+	if err != nil {
 		return internal.NewDecodeErr(err)
-	} else {
-		c.UniqId = v
 	}
+	c.UniqId = uniqId
 
-	if v, err := d.DecodeInt(); err != nil { // 3
+	count, err := d.DecodeInt() // 3
+	if err != nil {
 		return internal.NewDecodeErr(err)
-	} else {
-		c.Count = v
 	}
+	c.Count = count
 
-	if v, err := d.DecodeString(); err != nil { // 4
+	name, err := d.DecodeString() // 4
+	if err != nil {
 		return internal.NewDecodeErr(err)
-	} else {
-		c.Name = v
 	}
+	c.Name = name
 
-	if v, err := d.DecodeInt(); err != nil { // 5
+	price, err := d.DecodeInt() // 5
+	if err != nil {
 		return internal.NewDecodeErr(err)
-	} else {
-		c.Price = v
 	}
+	c.Price = price
 
 	return nil
 }
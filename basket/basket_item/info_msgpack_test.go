@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/vmihailenco/msgpack.v2"
 	"testing"
+	"time"
 )
 
 func TestInfoMsgpackSuite(t *testing.T) {
@@ -66,6 +67,35 @@ func (s *InfoMsgpackSuite) TestBasketItemInformation_DecodeMsgpack() {
 			obj:      []interface{}{27, "message", nil},
 			expected: &Info{id: 27, message: "message", additions: nil},
 		},
+		{
+			name: "message key int negative",
+			obj:  []interface{}{27, "message", nil, 0},
+			err:  "can't decode msgpack field `Info messageKey`[4]: msgpack: invalid code 0 decoding bytes length",
+		},
+		{
+			name: "positive with message key",
+			obj:  []interface{}{27, "message", nil, "key", []interface{}{[]interface{}{"name", "string", "value", 0}}},
+			expected: &Info{
+				id:            27,
+				message:       "message",
+				messageKey:    "key",
+				messageParams: []*MessageParam{NewStringMessageParam("name", "value")},
+			},
+		},
+		{
+			name: "positive with created at",
+			obj:  []interface{}{27, "message", nil, "", nil, time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC).UnixNano()},
+			expected: &Info{
+				id:        27,
+				message:   "message",
+				createdAt: time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC),
+			},
+		},
+		{
+			name: "too long",
+			obj:  []interface{}{27, "message", nil, "key", nil, 0, nil},
+			err:  "can't decode msgpack field `incorrect Info length`[0]: incorrect Info length: 7",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -3,6 +3,7 @@ package basket_item
 import (
 	"github.com/stretchr/testify/suite"
 	"testing"
+	"time"
 )
 
 func TestInfoSuite(t *testing.T) {
@@ -201,7 +202,57 @@ func (s *InfoSuite) TestNewInfo() {
 		tt := tt
 		s.Run(tt.name, func() {
 			got := NewInfo(expectedInfo.id, expectedInfo.message)
+			s.False(got.CreatedAt().IsZero())
+			got.createdAt = time.Time{}
 			s.Equal(tt.want, got)
 		})
 	}
 }
+
+func (s *InfoSuite) TestInfoExpiryPolicy_IsExpired() {
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	policy := InfoExpiryPolicy{InfoIdPriceChanged: time.Hour}
+
+	tests := []struct {
+		name      string
+		id        InfoId
+		createdAt time.Time
+		now       time.Time
+		want      bool
+	}{
+		{
+			name:      "not expired",
+			id:        InfoIdPriceChanged,
+			createdAt: createdAt,
+			now:       createdAt.Add(time.Hour - time.Second),
+			want:      false,
+		},
+		{
+			name:      "expired",
+			id:        InfoIdPriceChanged,
+			createdAt: createdAt,
+			now:       createdAt.Add(time.Hour),
+			want:      true,
+		},
+		{
+			name:      "without ttl",
+			id:        InfoIdPositionSplit,
+			createdAt: createdAt,
+			now:       createdAt.Add(24 * time.Hour),
+			want:      false,
+		},
+		{
+			name: "without created at",
+			id:   InfoIdPriceChanged,
+			now:  createdAt,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		s.Run(tt.name, func() {
+			info := &Info{id: tt.id, createdAt: tt.createdAt}
+			s.Equal(tt.want, policy.IsExpired(info, tt.now))
+		})
+	}
+}
@@ -4,7 +4,6 @@ package basket_item
 
 import (
 	"context"
-	"encoding/binary"
 	"encoding/xml"
 	"fmt"
 	"github.com/google/uuid"
@@ -12,10 +11,9 @@ import (
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
 	userv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/profile/user/v1"
 	"go.citilink.cloud/store_types"
-	"hash/fnv"
 	"sort"
-	"strconv"
 	"sync"
+	"time"
 )
 
 // ItemId идентификатор позиции (не уникален)
@@ -133,71 +131,94 @@ const (
 	PCCaseCategoryID catalog_types.CategoryId = 41
 )
 
+//go:generate go run ../../tools/msgpackgen -output=item_msgpack.go -types=Item,AllowResale,PresentChoiceGroup,ItemDiscount,ItemAdditions,ProductItemAdditions,ConfiguratorItemAdditions,SubcontractItemAdditions,SubcontractApplyServiceInfo,Service,Rules
+
 // Item позиция корзины. Никогда не создавайте эту структуру напрямую, всегда пользуйтесь методом-конструктором NewItem
+//
+//msgpack:deleted 12 array
+//msgpack:deleted 18 int
+//msgpack:deleted 19 int
+//msgpack:min 17
+//msgpack:after-decode afterDecode
+//msgpack:err 12 Item decode array len
+//msgpack:elem-err 12 12 Item DecodeInt
+//msgpack:err 13 Item DecodeArrayLen
+//msgpack:elem-err 13 13 Item problems
+//msgpack:err 18 Item DecodeInt
+//msgpack:err 19 Item DecodeInt
 type Item struct {
-	uniqId       UniqId // 1
-	itemId       ItemId // 2
-	itemType     Type   // 3
-	parentUniqId UniqId // 4
-	parentItemId ItemId // 5
-	name         string // 6
-	image        string // 7
-
-	count             int // 8
-	price             int // 9
-	bonus             int // 10
-	countMultiplicity int // 11 - количество в коробке/упаковке
-
-	problems  []*Problem       // 13
-	infos     map[InfoId]*Info // 14
-	rules     Rules            // 15
-	additions ItemAdditions    // 16
+	uniqId       UniqId `msgpackidx:"1,string"`
+	itemId       ItemId `msgpackidx:"2,string"`
+	itemType     Type   `msgpackidx:"3,string"`
+	parentUniqId UniqId `msgpackidx:"4,string"`
+	parentItemId ItemId `msgpackidx:"5,string"`
+	name         string `msgpackidx:"6,string"`
+	image        string `msgpackidx:"7,string"`
+
+	count             int `msgpackidx:"8,int"`
+	price             int `msgpackidx:"9,int"`
+	bonus             int `msgpackidx:"10,int"`
+	countMultiplicity int `msgpackidx:"11,int"` // количество в коробке/упаковке
+
+	problems  []*Problem       `msgpackidx:"13,slice,nilempty"`
+	infos     map[InfoId]*Info `msgpackidx:"14"`
+	rules     Rules            `msgpackidx:"15"`
+	additions ItemAdditions    `msgpackidx:"16"`
 
 	// список постоянных проблем
 	//
 	// данный список для отладки проблем и функционала, который с ними связан. Он нужен в связи с тем, что порой крайне
 	// затруднительно воспроизвести ту или иную проблему, и чтобы облегчить этот труд придумали фиксированные проблемы
-	permanentProblems []*Problem // 17
-
-	// 18 deleted
-	// 19 deleted
-	spaceId               store_types.SpaceId       // 20
-	priceColumn           catalog_types.PriceColumn // 21
-	commitFingerprint     string                    // 22
-	isPrepaymentMandatory bool                      // 23
+	permanentProblems []*Problem `msgpackidx:"17"`
+
+	spaceId               store_types.SpaceId       `msgpackidx:"20,string"`
+	priceColumn           catalog_types.PriceColumn `msgpackidx:"21,int"`
+	commitFingerprint     string                    `msgpackidx:"22,string"`
+	isPrepaymentMandatory bool                      `msgpackidx:"23,bool"`
 	// Флаг отвечающий за наличие у товара честной цены. Если true - значит в old_price хранится СТАРАЯ цена, а в ПЕРВОЙ
 	// ценовой колонке находится честная(клубная) цена. Если false - необходимо игнорировать поведение описанное
 	// для ignoreFairPrice.
-	hasFairPrice bool // 24
+	hasFairPrice bool `msgpackidx:"24,bool"`
 	// Флаг отвечающий за желание пользователя игнорировать честную цену для данного товара. Если true - пользователь
 	// игнорирует честную цену и необходимо использовать old_price для получения цены товара. Иначе необходимо
 	// использовать ПЕРВУЮ ценовую колонку как цену товара.
-	ignoreFairPrice bool // 25
+	ignoreFairPrice bool `msgpackidx:"25,bool"`
 	// Не сохраняемый флаг. Отвечает за статус изменения флага ignoreFairPrice
 	ignoreFairPriceChanged bool
 	// Информация о возможности приобретать данную позицию для перепродажи.
-	allowResale *AllowResale // 26
+	allowResale *AllowResale `msgpackidx:"26"`
 	// Маркированный товар
 	markedPurchaseReason MarkedPurchaseReason
 	// Скидки по данной позиции
-	discount ItemDiscount // 27
+	discount ItemDiscount `msgpackidx:"27"`
 	// Флаг показывающий, можно ли переместить товар в конфигурацию с учетом текущей корзины
-	movableToConfiguration bool // 28
+	movableToConfiguration bool `msgpackidx:"28,bool"`
 	// Флаг показывающий, можно ли переместить товар из конфигурации в корзину
-	movableFromConfiguration bool // 29
+	movableFromConfiguration bool `msgpackidx:"29,bool"`
 	// Флаг показывающий выбрана ли позиция для покупки
-	isSelected bool // 30
+	isSelected bool `msgpackidx:"30,bool,default=true"`
+	// Группа подарков на выбор. Заполняется только у позиций, к которым акция предлагает выбрать один подарок из
+	// нескольких
+	presentChoiceGroup *PresentChoiceGroup `msgpackidx:"31"`
+	// Порядковый номер позиции в корзине, задает порядок отображения позиций. Позиции, сохраненные до появления
+	// порядкового номера, имеют номер 0
+	position int `msgpackidx:"32,int"`
+	// Время добавления позиции в корзину
+	addedAt time.Time `msgpackidx:"33,unixnano"`
+	// Вся неподтвержденная информация о позиции в порядке появления. В infos хранится последняя информация по каждому
+	// идентификатору, она сохраняется для версий сервиса, которые не знают об истории
+	infoHistory []*Info `msgpackidx:"34,slice,nilempty"`
 }
 
 type ItemDiscount struct {
 	// Скидка позиции за примененный купон
-	Coupon int
+	Coupon int `msgpackidx:"1,int"`
 	// Скидка позиции по акциям
-	Action int
+	Action int `msgpackidx:"2,int"`
 	// Общая сумма всех скидок позиции
-	Total int
+	Total int `msgpackidx:"3,int"`
 	// Список примененных к позиции акций строкой через запятую
-	AppliedPromotions string
+	AppliedPromotions string `msgpackidx:"4,string"`
 }
 
 // NewItem создает новую позицию.
@@ -263,6 +284,22 @@ func (i *Item) SetIsSelected(isSelected bool) {
 	i.isSelected = isSelected
 }
 
+func (i *Item) Position() int {
+	return i.position
+}
+
+func (i *Item) SetPosition(position int) {
+	i.position = position
+}
+
+func (i *Item) AddedAt() time.Time {
+	return i.addedAt
+}
+
+func (i *Item) SetAddedAt(addedAt time.Time) {
+	i.addedAt = addedAt
+}
+
 func (i *Item) UniqId() UniqId {
 	return i.uniqId
 }
@@ -271,21 +308,24 @@ func (i *Item) ItemId() ItemId {
 	return i.itemId
 }
 
-// Fingerprint собирает информацию по всей корзине и выводит это в виде хэша. Данный хэш при сборе так же
-// сортирует позиции заказа по идентификатору позиции, таким образом увеличивается кол-во одинаковых отпечатков у
-// одинаковых корзин
+// Fingerprint отпечаток позиции, по которому определяется ее изменение (см. IsChanged). Список полей отпечатка задан
+// в области FingerprintScopeFull
 func (i *Item) Fingerprint() string {
-	// данная реализация никогда не сможет вернуть ошибку, поэтому они здесь опущены + так же это крайне затрудняет
-	// тестирование, так как для подмены хэша придется городить какую-то фабрику или любую другую решение, которое
-	// только усложнит код.
-	h := fnv.New64a()
-	_, _ = h.Write([]byte(i.SpaceId()))
-	_ = binary.Write(h, binary.LittleEndian, int32(i.PriceColumn()))
-	_, _ = h.Write([]byte(i.ItemId()))
-	_ = binary.Write(h, binary.LittleEndian, int32(i.Count()))
-	_ = binary.Write(h, binary.LittleEndian, int32(i.Price()))
+	return i.ScopedFingerprint(FingerprintScopeFull)
+}
+
+// afterDecode вызывается после декодирования позиции
+func (i *Item) afterDecode() {
+	i.upgradeFingerprint()
+	i.restoreInfoHistory()
+}
 
-	return strconv.FormatUint(h.Sum64(), 10)
+// upgradeFingerprint вызывается после декодирования позиции. Отпечатки прошлых версий несравнимы с текущими, поэтому
+// считается, что позиция не менялась с момента сохранения
+func (i *Item) upgradeFingerprint() {
+	if i.commitFingerprint != "" && !IsCurrentFingerprintVersion(i.commitFingerprint) {
+		i.commitFingerprint = i.Fingerprint()
+	}
 }
 
 // AllowUnselect определяет, можно ли снимать галочку выкупа товара. При снятии товар не попадет в оформленный заказ,
@@ -335,6 +375,15 @@ func (i *Item) SetAllowResale(v *AllowResale) {
 	i.allowResale = v
 }
 
+// PresentChoiceGroup возвращает группу подарков на выбор. Имейте ввиду, группы может и не быть
+func (i *Item) PresentChoiceGroup() *PresentChoiceGroup {
+	return i.presentChoiceGroup
+}
+
+func (i *Item) SetPresentChoiceGroup(v *PresentChoiceGroup) {
+	i.presentChoiceGroup = v
+}
+
 func (i *Item) SetDiscount(v ItemDiscount) {
 	i.discount = v
 }
@@ -605,14 +654,89 @@ func (i *Item) Infos() map[InfoId]*Info {
 	return i.infos
 }
 
+// InfoHistory возвращает всю неподтвержденную информацию о позиции в порядке появления, в отличие от Infos, где
+// хранится только последняя информация по каждому идентификатору
+func (i *Item) InfoHistory() []*Info {
+	return i.infoHistory
+}
+
 func (i *Item) AddInfo(infos ...*Info) {
 	for _, info := range infos {
 		i.infos[info.Id()] = info
+		i.infoHistory = append(i.infoHistory, info)
 	}
 }
 
 func (i *Item) CommitInfo(id InfoId) {
-	delete(i.infos, id)
+	i.CommitInfos(id)
+}
+
+// CommitInfos подтверждает всю информацию о позиции с указанными идентификаторами
+func (i *Item) CommitInfos(ids ...InfoId) {
+	committed := make(map[InfoId]bool, len(ids))
+	for _, id := range ids {
+		committed[id] = true
+	}
+
+	i.filterInfoHistory(func(info *Info) bool {
+		return !committed[info.Id()]
+	})
+}
+
+// ExpireInfos удаляет информацию о позиции, время жизни которой по правилам policy истекло на момент now
+func (i *Item) ExpireInfos(policy InfoExpiryPolicy, now time.Time) {
+	i.filterInfoHistory(func(info *Info) bool {
+		return !policy.IsExpired(info, now)
+	})
+}
+
+// filterInfoHistory оставляет в истории только информацию, для которой keep возвращает true, и пересобирает
+// последнюю информацию по каждому идентификатору
+func (i *Item) filterInfoHistory(keep func(info *Info) bool) {
+	i.restoreInfoHistory()
+
+	var history []*Info
+	for _, info := range i.infoHistory {
+		if keep(info) {
+			history = append(history, info)
+		}
+	}
+
+	i.infoHistory = history
+	i.infos = make(map[InfoId]*Info, len(history))
+	for _, info := range history {
+		i.infos[info.Id()] = info
+	}
+}
+
+// restoreInfoHistory связывает последнюю информацию по идентификатору с историей. У позиций, сохраненных до появления
+// истории, история собирается из последней информации по каждому идентификатору
+func (i *Item) restoreInfoHistory() {
+	if i.infos == nil {
+		i.infos = make(map[InfoId]*Info)
+	}
+
+	latest := make(map[InfoId]*Info, len(i.infoHistory))
+	for _, info := range i.infoHistory {
+		latest[info.Id()] = info
+	}
+
+	ids := make([]InfoId, 0, len(i.infos))
+	for id := range i.infos {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return ids[a] < ids[b]
+	})
+
+	for _, id := range ids {
+		if _, ok := latest[id]; !ok {
+			latest[id] = i.infos[id]
+			i.infoHistory = append(i.infoHistory, i.infos[id])
+		}
+	}
+
+	i.infos = latest
 }
 
 func (i *Item) Additions() *ItemAdditions {
@@ -776,17 +900,18 @@ func (e *MaxItemCountError) Unwrap() error {
 	return e.err
 }
 
+//msgpack:min 3
 type ItemAdditions struct {
 	// Данные о товаре, заполняются для всех позиций, которые являются товаром (например товар в корзине
 	// и товар как дочерняя позиции конфигурации). У каждой позиции у которой isProduct истина должна обладать
 	// дополнительной информацией о товаре
-	Product *ProductItemAdditions // 1
+	Product *ProductItemAdditions `msgpackidx:"1,get=GetProduct"`
 	// Данные по конфигурации. Эти данные применяются для всех типов, связанных с конфигурацией
 	// (товар, услуга, сама конфигурация)
-	Configuration                *ConfiguratorItemAdditions // 2
-	SubcontractServiceForProduct *SubcontractItemAdditions  // 3
+	Configuration                *ConfiguratorItemAdditions `msgpackidx:"2,get=GetConfiguration"`
+	SubcontractServiceForProduct *SubcontractItemAdditions  `msgpackidx:"3,get=GetSubcontractServiceForProduct"`
 	// Данные о любой услуге
-	Service *Service     // 4
+	Service *Service     `msgpackidx:"4,get=GetService"`
 	mx      sync.RWMutex `msgpack:"-"`
 }
 
@@ -929,11 +1054,10 @@ var typeToSpecs = map[Type]*Spec{
 		allowedUserTypes:          specPersonType,
 	},
 	TypeConfiguration: {
-		childrenTypes:             []Type{TypeConfigurationProduct, TypeConfigurationAssemblyService},
-		isDeletable:               true,
-		isCountChangeable:         true,
-		isOnlyOnePositionPossible: true,
-		allowedUserTypes:          specPersonType | specB2bUserType,
+		childrenTypes:     []Type{TypeConfigurationProduct, TypeConfigurationAssemblyService},
+		isDeletable:       true,
+		isCountChangeable: true,
+		allowedUserTypes:  specPersonType | specB2bUserType,
 	},
 	TypeConfigurationProduct: {
 		mustBeAChild:      true,
@@ -1114,6 +1238,10 @@ func (is Items) Sort(parent *Item) Items {
 
 	sort.SliceStable(children, func(i, j int) bool {
 		if children[i].SortTypeValue() == children[j].SortTypeValue() {
+			if children[i].Position() != children[j].Position() {
+				return children[i].Position() < children[j].Position()
+			}
+
 			return children[i].Name() < children[j].Name()
 		}
 
@@ -1129,6 +1257,25 @@ func (is Items) Sort(parent *Item) Items {
 	return result
 }
 
+// SortByPosition сортирует позиции в порядке, в котором они находятся в корзине: по порядковому номеру, затем по
+// времени добавления. Позиции, у которых и то и другое совпадает (например, сохраненные до появления порядкового
+// номера), сортируются по уникальному идентификатору, чтобы порядок всегда был одним и тем же
+func (is Items) SortByPosition() Items {
+	sort.Slice(is, func(i, j int) bool {
+		if is[i].Position() != is[j].Position() {
+			return is[i].Position() < is[j].Position()
+		}
+
+		if !is[i].AddedAt().Equal(is[j].AddedAt()) {
+			return is[i].AddedAt().Before(is[j].AddedAt())
+		}
+
+		return is[i].UniqId() < is[j].UniqId()
+	})
+
+	return is
+}
+
 type ItemMap map[UniqId]*Item
 
 func (im ItemMap) ToSlice() Items {
@@ -1893,52 +1893,7 @@ func (s *ItemMsgpackSuite) TestItem_DecodeMsgpack() {
 		},
 		{
 			name: "positive",
-			obj: []interface{}{
-				uniqId,      // uniqId 1
-				"",          // itemId 2
-				TypeUnknown, // itemType 3
-				"",          // parentUniqId 4
-				"",          // parentItemId 5
-				"",          // name 6
-				"",          // image 7
-				0,           // count 8
-				0,           // price 9
-				0,           // bonus 10
-				0,           // countMultiplicity 11
-				[]int{0},    // deleted 12
-
-				[]interface{}(nil), // problems 13
-				map[InfoId]*Info{}, // infos 14
-				[]interface{}{0},   // rules 15
-				[]interface{}{ // additions 16
-					&[]interface{}{
-						true, 1, 1, "", 0, false,
-						1, true, []catalog_types.CreditProgram{}, "", "",
-						true, true, MarkedPurchaseReasonUnknown,
-						true, "name", "brand", "path", "short",
-					},
-					[]interface{}{"config", ConfTypeUser},
-					[]interface{}{[]interface{}{ansicStr, "street", "197", "Birobidzhan"}},
-					[]interface{}{true, false},
-				},
-				[]interface{}(nil), // permanentProblems 17
-
-				0,                               // 18 deleted
-				0,                               // 19 deleted
-				"",                              // spaceId 20
-				catalog_types.PriceColumnRetail, // priceColumn 21
-				"",                              // commitFingerprint 22
-				false,                           // isPrepaymentMandatory 23
-				false,                           // hasFairPrice 24
-				false,                           // ignoreFairPrice 25
-				[]interface{}{ // allowResale 26
-					false, "commodity group name",
-				},
-				[]interface{}{0, 0, 0, ""},
-				false,
-				false,
-				true,
-			},
+			obj:  itemMsgpackFixture(uniqId, ansicStr),
 			err: func() error {
 				return nil
 			},
@@ -1966,6 +1921,98 @@ func (s *ItemMsgpackSuite) TestItem_DecodeMsgpack() {
 	}
 }
 
+// itemMsgpackFixture позиция в том виде, в котором она сохраняется в хранилище
+func itemMsgpackFixture(uniqId UniqId, applyServiceDate string) []interface{} {
+	return []interface{}{
+		uniqId,      // uniqId 1
+		"",          // itemId 2
+		TypeUnknown, // itemType 3
+		"",          // parentUniqId 4
+		"",          // parentItemId 5
+		"",          // name 6
+		"",          // image 7
+		0,           // count 8
+		0,           // price 9
+		0,           // bonus 10
+		0,           // countMultiplicity 11
+		[]int{0},    // deleted 12
+
+		[]interface{}(nil), // problems 13
+		map[InfoId]*Info{}, // infos 14
+		[]interface{}{0},   // rules 15
+		[]interface{}{ // additions 16
+			&[]interface{}{
+				true, 1, 1, "", 0, false,
+				1, true, []catalog_types.CreditProgram{}, "", "",
+				true, true, MarkedPurchaseReasonUnknown,
+				true, "name", "brand", "path", "short",
+			},
+			[]interface{}{"config", ConfTypeUser},
+			[]interface{}{[]interface{}{applyServiceDate, "street", "197", "Birobidzhan"}},
+			[]interface{}{true, false},
+		},
+		[]interface{}(nil), // permanentProblems 17
+
+		0,                               // 18 deleted
+		0,                               // 19 deleted
+		"",                              // spaceId 20
+		catalog_types.PriceColumnRetail, // priceColumn 21
+		"",                              // commitFingerprint 22
+		false,                           // isPrepaymentMandatory 23
+		false,                           // hasFairPrice 24
+		false,                           // ignoreFairPrice 25
+		[]interface{}{ // allowResale 26
+			false, "commodity group name",
+		},
+		[]interface{}{0, 0, 0, ""},
+		false,
+		false,
+		true,
+	}
+}
+
+func (s *ItemMsgpackSuite) TestItem_PositionMsgpack() {
+	addedAt := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	tests := []struct {
+		name        string
+		position    int
+		addedAt     time.Time
+		wantAddedAt time.Time
+	}{
+		{
+			name:        "position and added at",
+			position:    3,
+			addedAt:     addedAt,
+			wantAddedAt: addedAt,
+		},
+		{
+			name:        "added at in another location is decoded in utc",
+			position:    1,
+			addedAt:     addedAt.In(time.FixedZone("MSK", 3*60*60)),
+			wantAddedAt: addedAt,
+		},
+		{
+			name: "zero values",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		s.Run(tt.name, func() {
+			item := NewItem("1", TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail)
+			item.SetPosition(tt.position)
+			item.SetAddedAt(tt.addedAt)
+
+			buf, err := msgpack.Marshal(item)
+			s.Require().NoError(err)
+
+			decodedItem := &Item{}
+			s.Require().NoError(msgpack.Unmarshal(buf, decodedItem))
+			s.Equal(tt.position, decodedItem.Position())
+			s.Equal(tt.wantAddedAt, decodedItem.AddedAt())
+		})
+	}
+}
+
 func (s *ItemMsgpackSuite) TestItemAllowResale_DecodeMsgpack() {
 	tests := []struct {
 		name string
@@ -2043,3 +2090,94 @@ func (s *ItemMsgpackSuite) TestItemAllowResale_DecodeMsgpack() {
 		})
 	}
 }
+
+func (s *ItemMsgpackSuite) TestPresentChoiceGroup_DecodeMsgpack() {
+	tests := []struct {
+		name string
+		obj  interface{}
+		err  func() error
+		want *PresentChoiceGroup
+	}{
+		{
+			name: "base type substitution negative",
+			obj:  []byte{},
+			err: func() error {
+				return fmt.Errorf("can't decode msgpack field `PresentChoiceGroup array len`[0]: msgpack: " +
+					"invalid code c4 decoding array length")
+			},
+		},
+		{
+			name: "empty",
+			obj:  []interface{}{},
+			err: func() error {
+				return fmt.Errorf("can't decode msgpack field `(basket_item.PresentChoiceGroup) incorrect len`[0]: " +
+					"(basket_item.PresentChoiceGroup) incorrect len: 0")
+			},
+		},
+		{
+			name: "candidateItemIds is int negative",
+			obj:  []interface{}{1, "2"},
+			err: func() error {
+				return fmt.Errorf("can't decode msgpack field `PresentChoiceGroup candidateItemIds`[1]: msgpack: " +
+					"invalid code 1 decoding array length")
+			},
+		},
+		{
+			name: "candidateItemId is int negative",
+			obj:  []interface{}{[]interface{}{1}, "2"},
+			err: func() error {
+				return fmt.Errorf("can't decode msgpack field `PresentChoiceGroup candidateItemIds`[1]: msgpack: " +
+					"invalid code 1 decoding bytes length")
+			},
+		},
+		{
+			name: "chosenItemId is int negative",
+			obj:  []interface{}{[]string{"1"}, 2},
+			err: func() error {
+				return fmt.Errorf("can't decode msgpack field `PresentChoiceGroup chosenItemId`[2]: msgpack: " +
+					"invalid code 2 decoding bytes length")
+			},
+		},
+		{
+			name: "positive",
+			obj:  []interface{}{[]string{"1", "2"}, "2"},
+			err: func() error {
+				return nil
+			},
+			want: NewPresentChoiceGroup([]ItemId{"1", "2"}, "2"),
+		},
+		{
+			name: "positive without chosen present",
+			obj:  []interface{}{[]string{"1", "2"}, ""},
+			err: func() error {
+				return nil
+			},
+			want: NewPresentChoiceGroup([]ItemId{"1", "2"}, ""),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		s.Run(tt.name, func() {
+			buf, err := msgpack.Marshal(tt.obj)
+			s.Nil(err)
+
+			s.reader = bytes.NewReader(buf)
+			s.decoder = msgpack.NewDecoder(s.reader)
+
+			group := &PresentChoiceGroup{}
+			err = group.DecodeMsgpack(s.decoder)
+
+			if tt.want == nil {
+				s.EqualError(err, tt.err().Error())
+				return
+			}
+
+			s.Nil(err)
+			s.Equal(tt.want, group)
+
+			encoded, err := msgpack.Marshal(group)
+			s.Nil(err)
+			s.Equal(buf, encoded)
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package basket_item
+
+import (
+	"fmt"
+	"go.citilink.cloud/catalog_types"
+	basketv1 "go.citilink.cloud/order/internal/order/basket/api/v1"
+	"go.citilink.cloud/store_types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// ItemToProto переводит позицию в protobuf представление для внешних потребителей (схема в api/v1/basket.proto).
+// Переводятся все сохраняемые в хранилище данные позиции, поэтому ItemFromProto восстанавливает позицию без потерь
+func ItemToProto(item *Item) *basketv1.Item {
+	if item == nil {
+		return nil
+	}
+
+	message := &basketv1.Item{
+		UniqId:                   string(item.uniqId),
+		ItemId:                   string(item.itemId),
+		Type:                     string(item.itemType),
+		ParentUniqId:             string(item.parentUniqId),
+		ParentItemId:             string(item.parentItemId),
+		Name:                     item.name,
+		Image:                    item.image,
+		Count:                    int64(item.count),
+		Price:                    int64(item.price),
+		Bonus:                    int64(item.bonus),
+		CountMultiplicity:        int64(item.countMultiplicity),
+		Problems:                 problemsToProto(item.problems),
+		Rules:                    &basketv1.Rules{MaxCount: int64(item.rules.MaxCount())},
+		Additions:                itemAdditionsToProto(&item.additions),
+		PermanentProblems:        problemsToProto(item.permanentProblems),
+		SpaceId:                  string(item.spaceId),
+		PriceColumn:              int32(item.priceColumn),
+		CommitFingerprint:        item.commitFingerprint,
+		IsPrepaymentMandatory:    item.isPrepaymentMandatory,
+		HasFairPrice:             item.hasFairPrice,
+		IgnoreFairPrice:          item.ignoreFairPrice,
+		MovableToConfiguration:   item.movableToConfiguration,
+		MovableFromConfiguration: item.movableFromConfiguration,
+		IsSelected:               item.isSelected,
+		Position:                 int64(item.position),
+		Discount: &basketv1.ItemDiscount{
+			Coupon:            int64(item.discount.Coupon),
+			Action:            int64(item.discount.Action),
+			Total:             int64(item.discount.Total),
+			AppliedPromotions: item.discount.AppliedPromotions,
+		},
+	}
+
+	if len(item.infos) > 0 {
+		message.Infos = make(map[int32]*basketv1.ItemInfo, len(item.infos))
+		for id, info := range item.infos {
+			message.Infos[int32(id)] = InfoToProto(info)
+		}
+	}
+
+	for _, info := range item.infoHistory {
+		message.InfoHistory = append(message.InfoHistory, InfoToProto(info))
+	}
+
+	if item.allowResale != nil {
+		message.AllowResale = &basketv1.AllowResale{
+			IsAllow:            item.allowResale.isAllow,
+			CommodityGroupName: item.allowResale.commodityGroupName,
+		}
+	}
+
+	if item.presentChoiceGroup != nil {
+		message.PresentChoiceGroup = &basketv1.PresentChoiceGroup{
+			CandidateItemIds: itemIdsToStrings(item.presentChoiceGroup.CandidateItemIds()),
+			ChosenItemId:     string(item.presentChoiceGroup.ChosenItemId()),
+		}
+	}
+
+	if !item.addedAt.IsZero() {
+		message.AddedAt = timestamppb.New(item.addedAt)
+	}
+
+	return message
+}
+
+// ItemFromProto восстанавливает позицию из protobuf представления, полученного через ItemToProto
+func ItemFromProto(message *basketv1.Item) (*Item, error) {
+	if message == nil {
+		return nil, nil
+	}
+
+	item := &Item{
+		uniqId:                   UniqId(message.GetUniqId()),
+		itemId:                   ItemId(message.GetItemId()),
+		itemType:                 Type(message.GetType()),
+		parentUniqId:             UniqId(message.GetParentUniqId()),
+		parentItemId:             ItemId(message.GetParentItemId()),
+		name:                     message.GetName(),
+		image:                    message.GetImage(),
+		count:                    int(message.GetCount()),
+		price:                    int(message.GetPrice()),
+		bonus:                    int(message.GetBonus()),
+		countMultiplicity:        int(message.GetCountMultiplicity()),
+		problems:                 problemsFromProto(message.GetProblems()),
+		infos:                    make(map[InfoId]*Info, len(message.GetInfos())),
+		permanentProblems:        problemsFromProto(message.GetPermanentProblems()),
+		spaceId:                  store_types.SpaceId(message.GetSpaceId()),
+		priceColumn:              catalog_types.PriceColumn(message.GetPriceColumn()),
+		commitFingerprint:        message.GetCommitFingerprint(),
+		isPrepaymentMandatory:    message.GetIsPrepaymentMandatory(),
+		hasFairPrice:             message.GetHasFairPrice(),
+		ignoreFairPrice:          message.GetIgnoreFairPrice(),
+		markedPurchaseReason:     MarkedPurchaseReasonUnknown,
+		movableToConfiguration:   message.GetMovableToConfiguration(),
+		movableFromConfiguration: message.GetMovableFromConfiguration(),
+		isSelected:               message.GetIsSelected(),
+		position:                 int(message.GetPosition()),
+		discount: ItemDiscount{
+			Coupon:            int(message.GetDiscount().GetCoupon()),
+			Action:            int(message.GetDiscount().GetAction()),
+			Total:             int(message.GetDiscount().GetTotal()),
+			AppliedPromotions: message.GetDiscount().GetAppliedPromotions(),
+		},
+	}
+	item.rules.SetMaxCount(int(message.GetRules().GetMaxCount()))
+
+	for id, info := range message.GetInfos() {
+		item.infos[InfoId(id)] = InfoFromProto(info)
+	}
+
+	for _, info := range message.GetInfoHistory() {
+		item.infoHistory = append(item.infoHistory, InfoFromProto(info))
+	}
+	item.restoreInfoHistory()
+
+	err := itemAdditionsFromProto(&item.additions, message.GetAdditions())
+	if err != nil {
+		return nil, fmt.Errorf("can't convert additions of item '%s': %w", item.uniqId, err)
+	}
+
+	if message.GetAllowResale() != nil {
+		item.allowResale = NewAllowResale(
+			message.GetAllowResale().GetIsAllow(),
+			message.GetAllowResale().GetCommodityGroupName(),
+		)
+	}
+
+	if message.GetPresentChoiceGroup() != nil {
+		item.presentChoiceGroup = NewPresentChoiceGroup(
+			stringsToItemIds(message.GetPresentChoiceGroup().GetCandidateItemIds()),
+			ItemId(message.GetPresentChoiceGroup().GetChosenItemId()),
+		)
+	}
+
+	if message.GetAddedAt() != nil {
+		item.addedAt = message.GetAddedAt().AsTime()
+	}
+
+	return item, nil
+}
+
+func itemAdditionsToProto(additions *ItemAdditions) *basketv1.ItemAdditions {
+	message := &basketv1.ItemAdditions{}
+
+	if product := additions.GetProduct(); product != nil {
+		creditPrograms := make([]string, 0, len(product.CreditPrograms()))
+		for _, creditProgram := range product.CreditPrograms() {
+			creditPrograms = append(creditPrograms, string(creditProgram))
+		}
+
+		message.Product = &basketv1.ProductItemAdditions{
+			IsAvailInStore:              product.IsAvailInStore(),
+			Vat:                         int64(product.Vat()),
+			CategoryId:                  int64(product.CategoryId()),
+			IsOem:                       product.IsOEM(),
+			AvailTotal:                  int64(product.AvailTotal()),
+			IsCountMoreThenAvailChecked: product.IsCountMoreThenAvailChecked(),
+			CreditPrograms:              creditPrograms,
+			IsAvailForDpd:               product.IsAvailForDPD(),
+			IsMarked:                    product.IsMarked(),
+			MarkedPurchaseReason:        int32(product.MarkedPurchaseReason()),
+			IsDiscounted:                product.IsDiscounted(),
+			CategoryName:                product.CategoryName(),
+			BrandName:                   product.BrandName(),
+			CategoryPath:                product.CategoryPath(),
+			ShortName:                   product.ShortName(),
+			IsFnsTracked:                product.IsFnsTracked(),
+		}
+	}
+
+	if configuration := additions.GetConfiguration(); configuration != nil {
+		message.Configuration = &basketv1.ConfigurationItemAdditions{
+			ConfId:   configuration.GetConfId(),
+			ConfType: int32(configuration.GetConfType()),
+		}
+	}
+
+	if subcontract := additions.GetSubcontractServiceForProduct(); subcontract != nil {
+		message.SubcontractServiceForProduct = &basketv1.SubcontractItemAdditions{}
+		if applyServiceInfo := subcontract.GetApplyServiceInfo(); applyServiceInfo != nil {
+			date := ""
+			if !applyServiceInfo.Date.IsZero() {
+				date = applyServiceInfo.Date.Format(time.RFC3339Nano)
+			}
+
+			message.SubcontractServiceForProduct.ApplyServiceInfo = &basketv1.SubcontractApplyServiceInfo{
+				Date:        date,
+				Address:     applyServiceInfo.Address,
+				CityKladrId: string(applyServiceInfo.CityKladrId),
+				CityName:    applyServiceInfo.CityName,
+			}
+		}
+	}
+
+	if service := additions.GetService(); service != nil {
+		message.Service = &basketv1.ServiceItemAdditions{
+			IsCreditAvail:              service.GetIsCreditAvail(),
+			IsAvailableForInstallments: service.GetIsAvailableForInstallments(),
+		}
+	}
+
+	return message
+}
+
+func itemAdditionsFromProto(additions *ItemAdditions, message *basketv1.ItemAdditions) error {
+	if product := message.GetProduct(); product != nil {
+		creditPrograms := make([]catalog_types.CreditProgram, 0, len(product.GetCreditPrograms()))
+		for _, creditProgram := range product.GetCreditPrograms() {
+			creditPrograms = append(creditPrograms, catalog_types.CreditProgram(creditProgram))
+		}
+
+		additions.SetProduct(&ProductItemAdditions{
+			isAvailInStore:              product.GetIsAvailInStore(),
+			vat:                         int(product.GetVat()),
+			categoryId:                  catalog_types.CategoryId(product.GetCategoryId()),
+			isOEM:                       product.GetIsOem(),
+			availTotal:                  int(product.GetAvailTotal()),
+			isCountMoreThenAvailChecked: product.GetIsCountMoreThenAvailChecked(),
+			creditPrograms:              creditPrograms,
+			isAvailForDPD:               product.GetIsAvailForDpd(),
+			isMarked:                    product.GetIsMarked(),
+			markedPurchaseReason:        MarkedPurchaseReason(product.GetMarkedPurchaseReason()),
+			isDiscounted:                product.GetIsDiscounted(),
+			categoryName:                product.GetCategoryName(),
+			brandName:                   product.GetBrandName(),
+			categoryPath:                product.GetCategoryPath(),
+			shortName:                   product.GetShortName(),
+			isFnsTracked:                product.GetIsFnsTracked(),
+		})
+	}
+
+	if configuration := message.GetConfiguration(); configuration != nil {
+		additions.SetConfiguration(&ConfiguratorItemAdditions{
+			ConfId:   configuration.GetConfId(),
+			ConfType: ConfType(configuration.GetConfType()),
+		})
+	}
+
+	if subcontract := message.GetSubcontractServiceForProduct(); subcontract != nil {
+		subcontractAdditions := &SubcontractItemAdditions{}
+		if applyServiceInfo := subcontract.GetApplyServiceInfo(); applyServiceInfo != nil {
+			var date time.Time
+			if applyServiceInfo.GetDate() != "" {
+				var err error
+				date, err = time.Parse(time.RFC3339Nano, applyServiceInfo.GetDate())
+				if err != nil {
+					return fmt.Errorf("can't parse apply service date: %w", err)
+				}
+			}
+
+			subcontractAdditions.ApplyServiceInfo = &SubcontractApplyServiceInfo{
+				Date:        date,
+				Address:     applyServiceInfo.GetAddress(),
+				CityKladrId: store_types.KladrId(applyServiceInfo.GetCityKladrId()),
+				CityName:    applyServiceInfo.GetCityName(),
+			}
+		}
+
+		additions.SetSubcontractServiceForProduct(subcontractAdditions)
+	}
+
+	if service := message.GetService(); service != nil {
+		additions.SetService(&Service{
+			IsCreditAvail:              service.GetIsCreditAvail(),
+			IsAvailableForInstallments: service.GetIsAvailableForInstallments(),
+		})
+	}
+
+	return nil
+}
+
+func problemsToProto(problems []*Problem) []*basketv1.Problem {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	messages := make([]*basketv1.Problem, 0, len(problems))
+	for _, problem := range problems {
+		messages = append(messages, &basketv1.Problem{
+			Id:      int32(problem.id),
+			Message: problem.message,
+			Additions: &basketv1.ProblemAdditions{
+				Configuration: &basketv1.ConfigurationProblemAdditions{
+					NotAvailableProductItemIds: itemIdsToStrings(
+						problem.additions.ConfigurationProblemAdditions.NotAvailableProductItemIds,
+					),
+					IncompatibleProductItemIds: itemIdsToStrings(
+						problem.additions.ConfigurationProblemAdditions.IncompatibleProductItemIds,
+					),
+				},
+			},
+			IsHidden:      problem.isHidden,
+			MessageKey:    string(problem.messageKey),
+			MessageParams: messageParamsToProto(problem.messageParams),
+		})
+	}
+
+	return messages
+}
+
+func problemsFromProto(messages []*basketv1.Problem) []*Problem {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	problems := make([]*Problem, 0, len(messages))
+	for _, message := range messages {
+		configurationAdditions := message.GetAdditions().GetConfiguration()
+		problems = append(problems, &Problem{
+			id:      ProblemId(message.GetId()),
+			message: message.GetMessage(),
+			additions: ProblemAdditions{
+				ConfigurationProblemAdditions: ConfigurationProblemAdditions{
+					NotAvailableProductItemIds: stringsToItemIds(configurationAdditions.GetNotAvailableProductItemIds()),
+					IncompatibleProductItemIds: stringsToItemIds(configurationAdditions.GetIncompatibleProductItemIds()),
+				},
+			},
+			isHidden:      message.GetIsHidden(),
+			messageKey:    MessageKey(message.GetMessageKey()),
+			messageParams: messageParamsFromProto(message.GetMessageParams()),
+		})
+	}
+
+	return problems
+}
+
+// InfoToProto переводит информацию о позиции в protobuf представление
+func InfoToProto(info *Info) *basketv1.ItemInfo {
+	if info == nil {
+		return nil
+	}
+
+	message := &basketv1.ItemInfo{
+		Id:            int32(info.Id()),
+		Message:       info.Message(),
+		MessageKey:    string(info.MessageKey()),
+		MessageParams: messageParamsToProto(info.MessageParams()),
+	}
+
+	if createdAt := info.CreatedAt(); !createdAt.IsZero() {
+		message.CreatedAt = timestamppb.New(createdAt)
+	}
+
+	if additions := info.Additionals(); additions != nil {
+		message.Additions = &basketv1.InfoAdditions{
+			PriceChanged: &basketv1.PriceChangedInfoAddition{
+				From: int64(additions.PriceChanged.From),
+				To:   int64(additions.PriceChanged.To),
+			},
+			CountMoreThenAvail: &basketv1.CountMoreThenAvailInfoAddition{
+				AvailCount: int64(additions.CountMoreThenAvail.AvailCount),
+			},
+			ChangedItem: &basketv1.ChangedItemInfoAddition{
+				ItemId: additions.ChangedItem.ItemId,
+				UniqId: additions.ChangedItem.UniqId,
+				Count:  int64(additions.ChangedItem.Count),
+				Name:   additions.ChangedItem.Name,
+				Price:  int64(additions.ChangedItem.Price),
+			},
+		}
+	}
+
+	return message
+}
+
+// InfoFromProto восстанавливает информацию о позиции из protobuf представления
+func InfoFromProto(message *basketv1.ItemInfo) *Info {
+	if message == nil {
+		return nil
+	}
+
+	info := &Info{
+		id:            InfoId(message.GetId()),
+		message:       message.GetMessage(),
+		messageKey:    MessageKey(message.GetMessageKey()),
+		messageParams: messageParamsFromProto(message.GetMessageParams()),
+	}
+
+	if message.GetCreatedAt() != nil {
+		info.createdAt = message.GetCreatedAt().AsTime()
+	}
+
+	if additions := message.GetAdditions(); additions != nil {
+		info.additions = &InfoAdditions{
+			PriceChanged: PriceChangedInfoAddition{
+				From: int(additions.GetPriceChanged().GetFrom()),
+				To:   int(additions.GetPriceChanged().GetTo()),
+			},
+			CountMoreThenAvail: CountMoreThenAvailInfoAdditions{
+				AvailCount: int(additions.GetCountMoreThenAvail().GetAvailCount()),
+			},
+			ChangedItem: ChangedItemInfoAdditions{
+				ItemId: additions.GetChangedItem().GetItemId(),
+				UniqId: additions.GetChangedItem().GetUniqId(),
+				Count:  int(additions.GetChangedItem().GetCount()),
+				Name:   additions.GetChangedItem().GetName(),
+				Price:  int(additions.GetChangedItem().GetPrice()),
+			},
+		}
+	}
+
+	return info
+}
+
+func messageParamsToProto(params []*MessageParam) []*basketv1.MessageParam {
+	if len(params) == 0 {
+		return nil
+	}
+
+	messages := make([]*basketv1.MessageParam, 0, len(params))
+	for _, param := range params {
+		messages = append(messages, &basketv1.MessageParam{
+			Name:        param.name,
+			Type:        string(param.paramType),
+			StringValue: param.stringValue,
+			IntValue:    int64(param.intValue),
+		})
+	}
+
+	return messages
+}
+
+func messageParamsFromProto(messages []*basketv1.MessageParam) []*MessageParam {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	params := make([]*MessageParam, 0, len(messages))
+	for _, message := range messages {
+		params = append(params, &MessageParam{
+			name:        message.GetName(),
+			paramType:   MessageParamType(message.GetType()),
+			stringValue: message.GetStringValue(),
+			intValue:    int(message.GetIntValue()),
+		})
+	}
+
+	return params
+}
+
+func itemIdsToStrings(itemIds []ItemId) []string {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(itemIds))
+	for _, itemId := range itemIds {
+		result = append(result, string(itemId))
+	}
+
+	return result
+}
+
+func stringsToItemIds(values []string) []ItemId {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]ItemId, 0, len(values))
+	for _, value := range values {
+		result = append(result, ItemId(value))
+	}
+
+	return result
+}
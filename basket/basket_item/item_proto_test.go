@@ -0,0 +1,155 @@
+package basket_item
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	basketv1 "go.citilink.cloud/order/internal/order/basket/api/v1"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"testing"
+	"time"
+)
+
+// filledItemMsgpackFixture позиция, сохраненная в хранилище, в которой заполнены все данные
+func filledItemMsgpackFixture() []interface{} {
+	problem := NewLocalizedProblem(
+		ProblemConfigurationIncompatible,
+		MessagePositionSplit,
+		NewIntMessageParam("moved", 1),
+		NewStringMessageParam("remaining", "2"),
+	)
+	problem.Additions().ConfigurationProblemAdditions.NotAvailableProductItemIds = []ItemId{"3"}
+	problem.Additions().ConfigurationProblemAdditions.IncompatibleProductItemIds = []ItemId{"4", "5"}
+	problem.SetIsHidden(true)
+
+	previousInfo := NewInfo(InfoIdPriceChanged, "previous")
+	previousInfo.SetAdditions(&InfoAdditions{PriceChanged: PriceChangedInfoAddition{From: 50, To: 100}})
+	info := NewLocalizedInfo(InfoIdPriceChanged, MessageServiceRemovedOnDisassemble,
+		NewStringMessageParam("service", "service"))
+	info.SetAdditions(&InfoAdditions{
+		PriceChanged:       PriceChangedInfoAddition{From: 100, To: 200},
+		CountMoreThenAvail: CountMoreThenAvailInfoAdditions{AvailCount: 1},
+		ChangedItem:        ChangedItemInfoAdditions{ItemId: "7", UniqId: "uniq-7", Count: 2, Name: "name", Price: 3},
+	})
+
+	addedAt := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	applyServiceDate := time.Date(2021, 11, 2, 11, 2, 3, 0, time.FixedZone("MSK", 3*60*60))
+	commitFingerprint := NewFingerprintBuilder(FingerprintScopeFull).String()
+
+	return []interface{}{
+		"uniq",        // uniqId 1
+		"1",           // itemId 2
+		TypeProduct,   // itemType 3
+		"parent-uniq", // parentUniqId 4
+		"2",           // parentItemId 5
+		"name",        // name 6
+		"image",       // image 7
+		3,             // count 8
+		100,           // price 9
+		10,            // bonus 10
+		1,             // countMultiplicity 11
+		[]int{0},      // deleted 12
+
+		[]*Problem{problem},               // problems 13
+		map[InfoId]*Info{info.Id(): info}, // infos 14
+		[]interface{}{5},                  // rules 15
+		[]interface{}{ // additions 16
+			&[]interface{}{
+				true, 20, 30, "", 0, true,
+				7, true, []catalog_types.CreditProgram{"credit"}, "", "",
+				true, true, MarkedPurchaseReasonForResale,
+				true, "category", "brand", "path", "short", true,
+			},
+			[]interface{}{"config", ConfTypeTemplate},
+			[]interface{}{[]interface{}{applyServiceDate.Format(time.RFC3339), "street", "197", "Birobidzhan"}},
+			[]interface{}{true, true},
+		},
+		[]*Problem{NewProblem(ProblemNotAvailable, "permanent")}, // permanentProblems 17
+
+		0,                                      // 18 deleted
+		0,                                      // 19 deleted
+		"msk_cl",                               // spaceId 20
+		catalog_types.PriceColumnRetail,        // priceColumn 21
+		commitFingerprint,                      // commitFingerprint 22
+		true,                                   // isPrepaymentMandatory 23
+		true,                                   // hasFairPrice 24
+		true,                                   // ignoreFairPrice 25
+		[]interface{}{true, "commodity group"}, // allowResale 26
+		[]interface{}{1, 2, 3, "promo"},        // discount 27
+		true,                                   // movableToConfiguration 28
+		true,                                   // movableFromConfiguration 29
+		false,                                  // isSelected 30
+		[]interface{}{[]string{"8", "9"}, "9"}, // presentChoiceGroup 31
+		4,                                      // position 32
+		addedAt.UnixNano(),                     // addedAt 33
+		[]*Info{previousInfo, info},            // infoHistory 34
+	}
+}
+
+func TestItemToProto_RoundTrip(t *testing.T) {
+	applyServiceDate := time.Date(2021, 11, 2, 11, 2, 3, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		fixture []interface{}
+	}{
+		{
+			name:    "stored item",
+			fixture: itemMsgpackFixture("uniq", applyServiceDate.Format(time.RFC3339)),
+		},
+		{
+			name:    "stored item with legacy apply service date",
+			fixture: itemMsgpackFixture("uniq", applyServiceDate.Format(time.ANSIC)),
+		},
+		{
+			name:    "stored item with all data",
+			fixture: filledItemMsgpackFixture(),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := msgpack.Marshal(tt.fixture)
+			require.NoError(t, err)
+			item := &Item{}
+			require.NoError(t, msgpack.Unmarshal(stored, item))
+			want, err := msgpack.Marshal(item)
+			require.NoError(t, err)
+
+			encoded, err := proto.Marshal(ItemToProto(item))
+			require.NoError(t, err)
+			message := &basketv1.Item{}
+			require.NoError(t, proto.Unmarshal(encoded, message))
+			restored, err := ItemFromProto(message)
+			require.NoError(t, err)
+
+			got, err := msgpack.Marshal(restored)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Equal(t, item.Fingerprint(), restored.Fingerprint())
+		})
+	}
+}
+
+func TestItemFromProto(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		item, err := ItemFromProto(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, item)
+	})
+
+	t.Run("invalid apply service date", func(t *testing.T) {
+		_, err := ItemFromProto(&basketv1.Item{
+			UniqId: "uniq",
+			Additions: &basketv1.ItemAdditions{
+				SubcontractServiceForProduct: &basketv1.SubcontractItemAdditions{
+					ApplyServiceInfo: &basketv1.SubcontractApplyServiceInfo{Date: "tomorrow"},
+				},
+			},
+		})
+
+		assert.ErrorContains(t, err, "can't convert additions of item 'uniq': can't parse apply service date")
+	})
+}
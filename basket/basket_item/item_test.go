@@ -5,11 +5,14 @@ import (
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.citilink.cloud/catalog_types"
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
 	userv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/profile/user/v1"
 	"go.citilink.cloud/store_types"
+	"gopkg.in/vmihailenco/msgpack.v2"
 	"testing"
+	"time"
 )
 
 func generateItem(id ItemId, iType Type) *Item {
@@ -284,7 +287,7 @@ func TestItem_Fingerprint(t *testing.T) {
 		store_types.SpaceId("test_space_id"),
 		catalog_types.PriceColumnClub,
 	)
-	assert.Equal(t, "1149408419158322160", item.Fingerprint())
+	assert.Equal(t, "2:8b72949cf497d8060ccdbdf9325d4698007e9c213eba9c6410f8b9b6ee703e2f", item.Fingerprint())
 }
 
 func TestItem_Type(t *testing.T) {
@@ -1882,3 +1885,93 @@ func TestItem_CalculateMaxCount(t *testing.T) {
 		})
 	}
 }
+
+func TestItem_InfoHistory(t *testing.T) {
+	newPriceChanged := func(from int, to int, createdAt time.Time) *Info {
+		info := NewInfo(InfoIdPriceChanged, "price changed")
+		info.Additionals().PriceChanged = PriceChangedInfoAddition{From: from, To: to}
+		info.createdAt = createdAt
+
+		return info
+	}
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	t.Run("history keeps every info", func(t *testing.T) {
+		item := generateItem("1", TypeProduct)
+		first := newPriceChanged(1000, 1200, createdAt)
+		second := newPriceChanged(1200, 1100, createdAt.Add(time.Hour))
+		split := NewInfo(InfoIdPositionSplit, "split")
+
+		item.AddInfo(first, split)
+		item.AddInfo(second)
+
+		assert.Equal(t, []*Info{first, split, second}, item.InfoHistory())
+		assert.Equal(t, map[InfoId]*Info{InfoIdPriceChanged: second, InfoIdPositionSplit: split}, item.Infos())
+	})
+
+	t.Run("commit infos", func(t *testing.T) {
+		item := generateItem("1", TypeProduct)
+		split := NewInfo(InfoIdPositionSplit, "split")
+		removed := NewInfo(InfoIdPositionRemoved, "removed")
+		item.AddInfo(newPriceChanged(1000, 1200, createdAt), split, newPriceChanged(1200, 1100, createdAt), removed)
+
+		item.CommitInfos(InfoIdPriceChanged, InfoIdPositionRemoved)
+
+		assert.Equal(t, []*Info{split}, item.InfoHistory())
+		assert.Equal(t, map[InfoId]*Info{InfoIdPositionSplit: split}, item.Infos())
+	})
+
+	t.Run("expire infos", func(t *testing.T) {
+		item := generateItem("1", TypeProduct)
+		expired := newPriceChanged(1000, 1200, createdAt)
+		actual := newPriceChanged(1200, 1100, createdAt.Add(2*time.Hour))
+		withoutTime := NewInfo(InfoIdPositionSplit, "split")
+		withoutTime.createdAt = time.Time{}
+		withoutTtl := NewInfo(InfoIdCountMoreThanAvail, "count")
+		withoutTtl.createdAt = createdAt
+		item.AddInfo(expired, withoutTime, actual, withoutTtl)
+
+		item.ExpireInfos(InfoExpiryPolicy{
+			InfoIdPriceChanged:  2 * time.Hour,
+			InfoIdPositionSplit: time.Hour,
+		}, createdAt.Add(3*time.Hour))
+
+		assert.Equal(t, []*Info{withoutTime, actual, withoutTtl}, item.InfoHistory())
+		assert.Equal(t, map[InfoId]*Info{
+			InfoIdPriceChanged:       actual,
+			InfoIdPositionSplit:      withoutTime,
+			InfoIdCountMoreThanAvail: withoutTtl,
+		}, item.Infos())
+	})
+
+	t.Run("stored item without history", func(t *testing.T) {
+		item := generateItem("1", TypeProduct)
+		split := NewInfo(InfoIdPositionSplit, "split")
+		priceChanged := newPriceChanged(1000, 1200, createdAt)
+		item.infos = map[InfoId]*Info{InfoIdPositionSplit: split, InfoIdPriceChanged: priceChanged}
+
+		encoded, err := msgpack.Marshal(item)
+		require.NoError(t, err)
+		decoded := &Item{}
+		require.NoError(t, msgpack.Unmarshal(encoded, decoded))
+
+		require.Len(t, decoded.InfoHistory(), 2)
+		assert.Equal(t, InfoIdPriceChanged, decoded.InfoHistory()[0].Id())
+		assert.Equal(t, InfoIdPositionSplit, decoded.InfoHistory()[1].Id())
+		assert.True(t, decoded.InfoHistory()[0] == decoded.Infos()[InfoIdPriceChanged])
+	})
+
+	t.Run("stored item with history", func(t *testing.T) {
+		item := generateItem("1", TypeProduct)
+		item.AddInfo(newPriceChanged(1000, 1200, createdAt), newPriceChanged(1200, 1100, createdAt))
+
+		encoded, err := msgpack.Marshal(item)
+		require.NoError(t, err)
+		decoded := &Item{}
+		require.NoError(t, msgpack.Unmarshal(encoded, decoded))
+
+		require.Len(t, decoded.InfoHistory(), 2)
+		assert.Equal(t, 1100, decoded.InfoHistory()[1].Additionals().PriceChanged.To)
+		assert.True(t, decoded.InfoHistory()[1] == decoded.Infos()[InfoIdPriceChanged])
+	})
+}
@@ -0,0 +1,146 @@
+package basket_item
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Language язык, на котором показываются сообщения проблем и информации о позициях
+type Language string
+
+const (
+	LanguageRu Language = "ru"
+	LanguageKk Language = "kk"
+	LanguageEn Language = "en"
+)
+
+// MessageKey ключ сообщения проблемы или информации о позиции в каталоге переводов. Ключ сохраняется вместе с
+// проблемой или информацией, а текст на нужном языке формируется при чтении
+type MessageKey string
+
+// MessageParamType тип параметра сообщения
+type MessageParamType string
+
+const (
+	MessageParamTypeString MessageParamType = "string"
+	MessageParamTypeInt    MessageParamType = "int"
+	// MessageParamTypeMessage значение параметра - ключ сообщения, которое подставляется на том же языке, что и
+	// основное сообщение
+	MessageParamTypeMessage MessageParamType = "message"
+)
+
+func NewStringMessageParam(name string, value string) *MessageParam {
+	return &MessageParam{name: name, paramType: MessageParamTypeString, stringValue: value}
+}
+
+func NewIntMessageParam(name string, value int) *MessageParam {
+	return &MessageParam{name: name, paramType: MessageParamTypeInt, intValue: value}
+}
+
+func NewKeyMessageParam(name string, key MessageKey) *MessageParam {
+	return &MessageParam{name: name, paramType: MessageParamTypeMessage, stringValue: string(key)}
+}
+
+//go:generate go run ../../tools/msgpackgen -output=message_msgpack.go -types=MessageParam
+
+// MessageParam именованный параметр сообщения, в тексте перевода подставляется вместо {name}
+type MessageParam struct {
+	name        string           `msgpackidx:"1,string"`
+	paramType   MessageParamType `msgpackidx:"2,string"`
+	stringValue string           `msgpackidx:"3,string"`
+	intValue    int              `msgpackidx:"4,int"`
+}
+
+func (p *MessageParam) Name() string {
+	return p.name
+}
+
+func (p *MessageParam) Type() MessageParamType {
+	return p.paramType
+}
+
+func (p *MessageParam) StringValue() string {
+	return p.stringValue
+}
+
+func (p *MessageParam) IntValue() int {
+	return p.intValue
+}
+
+// String значение параметра в том виде, в котором оно подставляется в текст сообщения
+func (p *MessageParam) String() string {
+	if p.paramType == MessageParamTypeInt {
+		return strconv.Itoa(p.intValue)
+	}
+
+	return p.stringValue
+}
+
+// MessageCatalog каталог переводов сообщений проблем и информации о позициях
+type MessageCatalog struct {
+	bundles map[Language]map[MessageKey]string
+}
+
+func NewMessageCatalog(bundles map[Language]map[MessageKey]string) *MessageCatalog {
+	return &MessageCatalog{bundles: bundles}
+}
+
+var defaultMessageCatalog = NewMessageCatalog(map[Language]map[MessageKey]string{
+	LanguageRu: messagesRu,
+	LanguageKk: messagesKk,
+	LanguageEn: messagesEn,
+})
+
+// DefaultMessageCatalog каталог с переводами сообщений сервиса на русский, казахский и английский языки
+func DefaultMessageCatalog() *MessageCatalog {
+	return defaultMessageCatalog
+}
+
+// Render формирует текст сообщения на указанном языке. Возвращает false, если перевода нет
+func (c *MessageCatalog) Render(language Language, key MessageKey, params []*MessageParam) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+
+	text, ok := c.bundles[language][key]
+	if !ok {
+		return "", false
+	}
+
+	if len(params) == 0 {
+		return text, true
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for _, param := range params {
+		replacements = append(replacements, "{"+param.name+"}", c.renderParam(language, param))
+	}
+
+	return strings.NewReplacer(replacements...).Replace(text), true
+}
+
+// renderParam формирует значение параметра. Параметр-сообщение подставляется на языке основного сообщения, а если
+// перевода нет, то подставляется его ключ
+func (c *MessageCatalog) renderParam(language Language, param *MessageParam) string {
+	if param.paramType != MessageParamTypeMessage {
+		return param.String()
+	}
+
+	text, ok := c.bundles[language][MessageKey(param.stringValue)]
+	if !ok {
+		return param.stringValue
+	}
+
+	return text
+}
+
+// renderStored формирует текст сообщения для сохранения вместе с ключом. Сохраненный текст показывается, если
+// перевода нет, и читается версиями сервиса, которые еще не знают о ключах сообщений
+func renderStored(key MessageKey, params []*MessageParam) string {
+	message, ok := defaultMessageCatalog.Render(LanguageRu, key, params)
+	if !ok {
+		return string(key)
+	}
+
+	return message
+}
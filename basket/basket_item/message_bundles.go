@@ -0,0 +1,225 @@
+package basket_item
+
+// Ключи сообщений проблем позиций
+const (
+	MessageProductNotAvailable                  MessageKey = "product_not_available"
+	MessageProductNotPurchasable                MessageKey = "product_not_purchasable"
+	MessageServiceNotPurchasable                MessageKey = "service_not_purchasable"
+	MessageProductWithServiceNotAvailable       MessageKey = "product_with_service_not_available"
+	MessageDigitalServiceNotProvided            MessageKey = "digital_service_not_provided"
+	MessageDigitalServiceNotAvailable           MessageKey = "digital_service_not_available"
+	MessagePropertyInsuranceNotProvided         MessageKey = "property_insurance_not_provided"
+	MessagePropertyInsuranceNotAvailable        MessageKey = "property_insurance_not_available"
+	MessageProductInsuranceNotProvided          MessageKey = "product_insurance_not_provided"
+	MessageProductInsuranceNotAvailable         MessageKey = "product_insurance_not_available"
+	MessageSubcontractServiceNotProvided        MessageKey = "subcontract_service_not_provided"
+	MessageSubcontractServiceNotAvailable       MessageKey = "subcontract_service_not_available"
+	MessageSubcontractServiceNotAvailableInCity MessageKey = "subcontract_service_not_available_in_city"
+	MessageMarkingDocumentFlowMissing           MessageKey = "marking_document_flow_missing"
+	MessageConfigurationPartNotAvailable        MessageKey = "configuration_part_not_available"
+	MessageCountExceedsParent                   MessageKey = "count_exceeds_parent"
+	MessagePositionNotAvailable                 MessageKey = "position_not_available"
+	MessagePositionNotOrderable                 MessageKey = "position_not_orderable"
+	MessageConfigurationComponentMinCount       MessageKey = "configuration_component_min_count"
+	MessageConfigurationComponentMaxCount       MessageKey = "configuration_component_max_count"
+	MessageConfigurationSocketMismatch          MessageKey = "configuration_socket_mismatch"
+	MessageCommodityGroupNotAvailableForResale  MessageKey = "commodity_group_not_available_for_resale"
+	// MessageUnknownCommodityGroupNotAvailableForResale используется, если название товарной группы неизвестно
+	MessageUnknownCommodityGroupNotAvailableForResale MessageKey = "unknown_commodity_group_not_available_for_resale"
+)
+
+// Ключи названий комплектующих, которые подставляются в сообщения о несовместимости конфигурации
+const (
+	MessageComponentPCCase      MessageKey = "component_pc_case"
+	MessageComponentMotherboard MessageKey = "component_motherboard"
+)
+
+// Ключи сообщений информации о позициях
+const (
+	MessageProductPriceChanged                 MessageKey = "product_price_changed"
+	MessageServicePriceChanged                 MessageKey = "service_price_changed"
+	MessageConfigurationPartPriceChanged       MessageKey = "configuration_part_price_changed"
+	MessageTemplatePartPriceChanged            MessageKey = "template_part_price_changed"
+	MessageAssemblyServicePriceChanged         MessageKey = "assembly_service_price_changed"
+	MessageConfigurationServicePriceChanged    MessageKey = "configuration_service_price_changed"
+	MessageConfigurationPriceChanged           MessageKey = "configuration_price_changed"
+	MessageCountMoreThanAvail                  MessageKey = "count_more_than_avail"
+	MessagePositionRemovedInCity               MessageKey = "position_removed_in_city"
+	MessageConfigurationPartRemovedInCity      MessageKey = "configuration_part_removed_in_city"
+	MessageSubcontractServiceReplaced          MessageKey = "subcontract_service_replaced"
+	MessageSubcontractServiceRemoved           MessageKey = "subcontract_service_removed"
+	MessagePositionSplit                       MessageKey = "position_split"
+	MessageServiceRemovedOnDisassemble         MessageKey = "service_removed_on_disassemble"
+	MessageAssemblyServiceRemovedOnDisassemble MessageKey = "assembly_service_removed_on_disassemble"
+	MessageTemplatePartNotAvailableInCity      MessageKey = "template_part_not_available_in_city"
+)
+
+var messagesRu = map[MessageKey]string{
+	MessageProductNotAvailable:                  "товара нет в наличии",
+	MessageProductNotPurchasable:                "покупка товара недоступна",
+	MessageServiceNotPurchasable:                "услугу купить невозможно",
+	MessageProductWithServiceNotAvailable:       "товара с услугой нет в наличии",
+	MessageDigitalServiceNotProvided:            "цифровая услуга не предоставляется",
+	MessageDigitalServiceNotAvailable:           "цифровая услуга недоступна",
+	MessagePropertyInsuranceNotProvided:         "услуга страхования имущества не предоставляется",
+	MessagePropertyInsuranceNotAvailable:        "услуга страхования имущества недоступна",
+	MessageProductInsuranceNotProvided:          "услуга страхования товара не предоставляется",
+	MessageProductInsuranceNotAvailable:         "услуга страхования товара недоступна",
+	MessageSubcontractServiceNotProvided:        "услуга субподряда не предоставляется",
+	MessageSubcontractServiceNotAvailable:       "услуга субподряда недоступна",
+	MessageSubcontractServiceNotAvailableInCity: "услуга субподряда недоступна в выбранном городе",
+	MessageMarkingDocumentFlowMissing:           "отсутствует ЭДО или ГИС",
+	MessageConfigurationPartNotAvailable:        "одна из позиций в конфигурации недоступна",
+	MessageCountExceedsParent:                   "Кол-во позиции не должно превышать родительскую",
+	MessagePositionNotAvailable:                 "Позиция не в наличии",
+	MessagePositionNotOrderable:                 "Позицию невозможно заказать",
+	MessageConfigurationComponentMinCount: "в конфигурации должно быть не " +
+		"меньше {count} шт. комплектующей «{component}»",
+	MessageConfigurationComponentMaxCount: "в конфигурации должно быть не " +
+		"больше {count} шт. комплектующей «{component}»",
+	MessageConfigurationSocketMismatch: "сокет процессора не совпадает с сокетом материнской платы",
+	MessageCommodityGroupNotAvailableForResale: "Товарная группа {commodityGroup} не подключена. Товар не может " +
+		"быть куплен для цели покупки «Перепродажа». Добавьте товарную группу в системе «Честный знак»",
+	MessageUnknownCommodityGroupNotAvailableForResale: "Товарная группа не подключена. Товар не может быть куплен " +
+		"для цели покупки «Перепродажа». Добавьте товарную группу в системе «Честный знак»",
+
+	MessageProductPriceChanged:              "цена на товар изменилась",
+	MessageServicePriceChanged:              "цена на услугу изменилась",
+	MessageConfigurationPartPriceChanged:    "цена на комплектующую конфигурации изменилась",
+	MessageTemplatePartPriceChanged:         "цена на комплектующую конфигурации изменилась с момента сохранения шаблона",
+	MessageAssemblyServicePriceChanged:      "цена на услугу сборки конфигурации изменилась",
+	MessageConfigurationServicePriceChanged: "цена на услугу в конфигурации изменилась",
+	MessageConfigurationPriceChanged:        "цена на конфигурацию изменилась",
+	MessageCountMoreThanAvail:               "Запрашиваемое кол-во больше, чем товара в наличии.",
+	MessagePositionRemovedInCity:            "Позиция удалена в связи с отсутствием в выбранном городе",
+	MessageConfigurationPartRemovedInCity: "Позиция удалена в связи с отсутствием в выбранном городе. " +
+		"Конфигурация, в которой присутствовала данная позиция, разобрана.",
+	MessageSubcontractServiceReplaced: "Услуга субподряда была заменена на аналогичную, доступную для текущего " +
+		"типа пользователя",
+	MessageSubcontractServiceRemoved: "Услуга субподряда удалена, в связи с недоступностью для текущего типа " +
+		"пользователя",
+	MessagePositionSplit: "В конфигурацию перенесено {moved} шт., остальные {remaining} шт. остались в корзине " +
+		"отдельной позицией, так как в конфигурации может быть не больше {max} шт. одного товара.",
+	MessageServiceRemovedOnDisassemble: "Услуга «{service}» к товару «{product}» доступна только в составе " +
+		"конфигурации и удалена при ее разборе.",
+	MessageAssemblyServiceRemovedOnDisassemble: "Услуга «{service}» удалена, так как конфигурация разобрана на " +
+		"отдельные товары.",
+	MessageTemplatePartNotAvailableInCity: "Комплектующая «{component}» отсутствует в выбранном городе и не " +
+		"добавлена в конфигурацию.",
+
+	MessageComponentPCCase:      "корпус",
+	MessageComponentMotherboard: "материнская плата",
+}
+
+var messagesKk = map[MessageKey]string{
+	MessageProductNotAvailable:                  "тауар қолда жоқ",
+	MessageProductNotPurchasable:                "тауарды сатып алу мүмкін емес",
+	MessageServiceNotPurchasable:                "қызметті сатып алу мүмкін емес",
+	MessageProductWithServiceNotAvailable:       "қызметі бар тауар қолда жоқ",
+	MessageDigitalServiceNotProvided:            "цифрлық қызмет көрсетілмейді",
+	MessageDigitalServiceNotAvailable:           "цифрлық қызмет қолжетімсіз",
+	MessagePropertyInsuranceNotProvided:         "мүлікті сақтандыру қызметі көрсетілмейді",
+	MessagePropertyInsuranceNotAvailable:        "мүлікті сақтандыру қызметі қолжетімсіз",
+	MessageProductInsuranceNotProvided:          "тауарды сақтандыру қызметі көрсетілмейді",
+	MessageProductInsuranceNotAvailable:         "тауарды сақтандыру қызметі қолжетімсіз",
+	MessageSubcontractServiceNotProvided:        "мердігерлік қызмет көрсетілмейді",
+	MessageSubcontractServiceNotAvailable:       "мердігерлік қызмет қолжетімсіз",
+	MessageSubcontractServiceNotAvailableInCity: "мердігерлік қызмет таңдалған қалада қолжетімсіз",
+	MessageMarkingDocumentFlowMissing:           "электрондық құжат айналымы немесе МАЖ жоқ",
+	MessageConfigurationPartNotAvailable:        "конфигурациядағы позициялардың бірі қолжетімсіз",
+	MessageCountExceedsParent:                   "Позиция саны негізгі позиция санынан аспауы керек",
+	MessagePositionNotAvailable:                 "Позиция қолда жоқ",
+	MessagePositionNotOrderable:                 "Позицияға тапсырыс беру мүмкін емес",
+	MessageConfigurationComponentMinCount: "конфигурацияда «{component}» құрамдас бөлігінің " +
+		"кемінде {count} данасы болуы керек",
+	MessageConfigurationComponentMaxCount: "конфигурацияда «{component}» құрамдас бөлігінің " +
+		"{count} данасынан артық болмауы керек",
+	MessageConfigurationSocketMismatch: "процессор сокеті аналық платаның сокетіне сәйкес келмейді",
+	MessageCommodityGroupNotAvailableForResale: "{commodityGroup} тауар тобы қосылмаған. Тауарды «Қайта сату» " +
+		"сатып алу мақсатымен сатып алу мүмкін емес. Тауар тобын «Честный знак» жүйесіне қосыңыз",
+	MessageUnknownCommodityGroupNotAvailableForResale: "Тауар тобы қосылмаған. Тауарды «Қайта сату» сатып алу " +
+		"мақсатымен сатып алу мүмкін емес. Тауар тобын «Честный знак» жүйесіне қосыңыз",
+
+	MessageProductPriceChanged:              "тауардың бағасы өзгерді",
+	MessageServicePriceChanged:              "қызметтің бағасы өзгерді",
+	MessageConfigurationPartPriceChanged:    "конфигурация құрамдас бөлігінің бағасы өзгерді",
+	MessageTemplatePartPriceChanged:         "үлгі сақталғаннан бері конфигурация құрамдас бөлігінің бағасы өзгерді",
+	MessageAssemblyServicePriceChanged:      "конфигурацияны құрастыру қызметінің бағасы өзгерді",
+	MessageConfigurationServicePriceChanged: "конфигурациядағы қызметтің бағасы өзгерді",
+	MessageConfigurationPriceChanged:        "конфигурацияның бағасы өзгерді",
+	MessageCountMoreThanAvail:               "Сұралған саны қолда бар тауардан көп.",
+	MessagePositionRemovedInCity:            "Позиция таңдалған қалада болмағандықтан жойылды",
+	MessageConfigurationPartRemovedInCity: "Позиция таңдалған қалада болмағандықтан жойылды. " +
+		"Осы позиция болған конфигурация бөлшектелді.",
+	MessageSubcontractServiceReplaced: "Мердігерлік қызмет ағымдағы пайдаланушы түріне қолжетімді ұқсас " +
+		"қызметпен ауыстырылды",
+	MessageSubcontractServiceRemoved: "Мердігерлік қызмет ағымдағы пайдаланушы түріне қолжетімсіз " +
+		"болғандықтан жойылды",
+	MessagePositionSplit: "Конфигурацияға {moved} дана ауыстырылды, қалған {remaining} дана себетте жеке " +
+		"позиция ретінде қалды, себебі конфигурацияда бір тауардың {max} данасынан артық болмауы керек.",
+	MessageServiceRemovedOnDisassemble: "«{product}» тауарына арналған «{service}» қызметі тек конфигурация " +
+		"құрамында қолжетімді, сондықтан ол бөлшектелгенде жойылды.",
+	MessageAssemblyServiceRemovedOnDisassemble: "Конфигурация жеке тауарларға бөлшектелгендіктен «{service}» " +
+		"қызметі жойылды.",
+	MessageTemplatePartNotAvailableInCity: "«{component}» құрамдас бөлігі таңдалған қалада жоқ, сондықтан " +
+		"конфигурацияға қосылмады.",
+
+	MessageComponentPCCase:      "корпус",
+	MessageComponentMotherboard: "аналық плата",
+}
+
+var messagesEn = map[MessageKey]string{
+	MessageProductNotAvailable:                  "product is out of stock",
+	MessageProductNotPurchasable:                "product can't be purchased",
+	MessageServiceNotPurchasable:                "service can't be purchased",
+	MessageProductWithServiceNotAvailable:       "product with the service is out of stock",
+	MessageDigitalServiceNotProvided:            "digital service is not provided",
+	MessageDigitalServiceNotAvailable:           "digital service is not available",
+	MessagePropertyInsuranceNotProvided:         "property insurance is not provided",
+	MessagePropertyInsuranceNotAvailable:        "property insurance is not available",
+	MessageProductInsuranceNotProvided:          "product insurance is not provided",
+	MessageProductInsuranceNotAvailable:         "product insurance is not available",
+	MessageSubcontractServiceNotProvided:        "subcontract service is not provided",
+	MessageSubcontractServiceNotAvailable:       "subcontract service is not available",
+	MessageSubcontractServiceNotAvailableInCity: "subcontract service is not available in the selected city",
+	MessageMarkingDocumentFlowMissing:           "electronic document flow or GIS is missing",
+	MessageConfigurationPartNotAvailable:        "one of the configuration items is not available",
+	MessageCountExceedsParent:                   "Item count must not exceed the count of the parent item",
+	MessagePositionNotAvailable:                 "Item is out of stock",
+	MessagePositionNotOrderable:                 "Item can't be ordered",
+	MessageConfigurationComponentMinCount: "configuration must contain at least " +
+		"{count} pcs. of component «{component}»",
+	MessageConfigurationComponentMaxCount: "configuration must contain at most " +
+		"{count} pcs. of component «{component}»",
+	MessageConfigurationSocketMismatch: "processor socket doesn't match the motherboard socket",
+	MessageCommodityGroupNotAvailableForResale: "Commodity group {commodityGroup} is not connected. The product " +
+		"can't be purchased for the «Resale» purpose. Add the commodity group in the «Chestny Znak» system",
+	MessageUnknownCommodityGroupNotAvailableForResale: "Commodity group is not connected. The product can't be " +
+		"purchased for the «Resale» purpose. Add the commodity group in the «Chestny Znak» system",
+
+	MessageProductPriceChanged:              "product price has changed",
+	MessageServicePriceChanged:              "service price has changed",
+	MessageConfigurationPartPriceChanged:    "configuration component price has changed",
+	MessageTemplatePartPriceChanged:         "configuration component price has changed since the template was saved",
+	MessageAssemblyServicePriceChanged:      "configuration assembly service price has changed",
+	MessageConfigurationServicePriceChanged: "configuration service price has changed",
+	MessageConfigurationPriceChanged:        "configuration price has changed",
+	MessageCountMoreThanAvail:               "Requested count is more than is in stock.",
+	MessagePositionRemovedInCity:            "Item is removed as it is not available in the selected city",
+	MessageConfigurationPartRemovedInCity: "Item is removed as it is not available in the selected city. " +
+		"The configuration containing this item is disassembled.",
+	MessageSubcontractServiceReplaced: "Subcontract service is replaced with a similar one available for the " +
+		"current user type",
+	MessageSubcontractServiceRemoved: "Subcontract service is removed as it is not available for the current user type",
+	MessagePositionSplit: "{moved} pcs. are moved to the configuration, the remaining {remaining} pcs. stay in the " +
+		"basket as a separate item, as a configuration can't contain more than {max} pcs. of one product.",
+	MessageServiceRemovedOnDisassemble: "Service «{service}» for product «{product}» is available only as part of " +
+		"the configuration and is removed on its disassembly.",
+	MessageAssemblyServiceRemovedOnDisassemble: "Service «{service}» is removed as the configuration is " +
+		"disassembled into separate products.",
+	MessageTemplatePartNotAvailableInCity: "Component «{component}» is not available in the selected city and is " +
+		"not added to the configuration.",
+
+	MessageComponentPCCase:      "case",
+	MessageComponentMotherboard: "motherboard",
+}
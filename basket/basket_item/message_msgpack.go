@@ -0,0 +1,67 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
+package basket_item
+
+import (
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+func (p *MessageParam) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(4); err != nil {
+		return err
+	}
+
+	if err := e.EncodeString(p.name); err != nil { // 1
+		return err
+	}
+	if err := e.EncodeString(string(p.paramType)); err != nil { // 2
+		return err
+	}
+	if err := e.EncodeString(p.stringValue); err != nil { // 3
+		return err
+	}
+	if err := e.EncodeInt(p.intValue); err != nil { // 4
+		return err
+	}
+
+	return nil
+}
+
+func (p *MessageParam) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 0, "MessageParam array len")
+	}
+
+	if length != 4 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("(basket_item.MessageParam) incorrect len: %d", length), 0, "(basket_item.MessageParam) incorrect len")
+	}
+
+	name, err := d.DecodeString() // 1
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 1, "MessageParam name")
+	}
+	p.name = name
+
+	paramType, err := d.DecodeString() // 2
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 2, "MessageParam paramType")
+	}
+	p.paramType = MessageParamType(paramType)
+
+	stringValue, err := d.DecodeString() // 3
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 3, "MessageParam stringValue")
+	}
+	p.stringValue = stringValue
+
+	intValue, err := d.DecodeInt() // 4
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 4, "MessageParam intValue")
+	}
+	p.intValue = intValue
+
+	return nil
+}
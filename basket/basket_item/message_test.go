@@ -0,0 +1,152 @@
+package basket_item
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageCatalog_Render(t *testing.T) {
+	catalog := NewMessageCatalog(map[Language]map[MessageKey]string{
+		LanguageRu: {"split": "перенесено {moved} шт. товара «{name}»", "ssd": "накопитель"},
+		LanguageEn: {"split": "{moved} pcs. of «{name}» are moved", "ssd": "drive"},
+	})
+	params := []*MessageParam{NewIntMessageParam("moved", 2), NewStringMessageParam("name", "SSD")}
+
+	tests := []struct {
+		name     string
+		language Language
+		key      MessageKey
+		params   []*MessageParam
+		want     string
+		wantOk   bool
+	}{
+		{
+			name:     "ru",
+			language: LanguageRu,
+			key:      "split",
+			params:   params,
+			want:     "перенесено 2 шт. товара «SSD»",
+			wantOk:   true,
+		},
+		{
+			name:     "en",
+			language: LanguageEn,
+			key:      "split",
+			params:   params,
+			want:     "2 pcs. of «SSD» are moved",
+			wantOk:   true,
+		},
+		{
+			name:     "message param",
+			language: LanguageEn,
+			key:      "split",
+			params:   []*MessageParam{NewIntMessageParam("moved", 2), NewKeyMessageParam("name", "ssd")},
+			want:     "2 pcs. of «drive» are moved",
+			wantOk:   true,
+		},
+		{
+			name:     "message param without translation",
+			language: LanguageRu,
+			key:      "split",
+			params:   []*MessageParam{NewIntMessageParam("moved", 2), NewKeyMessageParam("name", "hdd")},
+			want:     "перенесено 2 шт. товара «hdd»",
+			wantOk:   true,
+		},
+		{
+			name:     "without params",
+			language: LanguageEn,
+			key:      "split",
+			want:     "{moved} pcs. of «{name}» are moved",
+			wantOk:   true,
+		},
+		{
+			name:     "no bundle",
+			language: LanguageKk,
+			key:      "split",
+			params:   params,
+		},
+		{
+			name:     "unknown key",
+			language: LanguageRu,
+			key:      "unknown",
+		},
+		{
+			name:     "empty key",
+			language: LanguageRu,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := catalog.Render(tt.language, tt.key, tt.params)
+
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDefaultMessageCatalog(t *testing.T) {
+	for key := range messagesRu {
+		for _, language := range []Language{LanguageKk, LanguageEn} {
+			_, ok := DefaultMessageCatalog().Render(language, key, nil)
+			assert.True(t, ok, "message '%s' has no '%s' translation", key, language)
+		}
+	}
+
+	assert.Len(t, messagesKk, len(messagesRu))
+	assert.Len(t, messagesEn, len(messagesRu))
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	catalog := DefaultMessageCatalog()
+
+	t.Run("problem", func(t *testing.T) {
+		problem := NewLocalizedProblem(ProblemNotAvailable, MessageProductNotAvailable)
+
+		assert.Equal(t, "товара нет в наличии", problem.Message())
+		assert.Equal(t, "товара нет в наличии", problem.LocalizedMessage(catalog, LanguageRu))
+		assert.Equal(t, "тауар қолда жоқ", problem.LocalizedMessage(catalog, LanguageKk))
+		assert.Equal(t, "product is out of stock", problem.LocalizedMessage(catalog, LanguageEn))
+		assert.Equal(t, "товара нет в наличии", problem.LocalizedMessage(catalog, "de"))
+	})
+
+	t.Run("problem without key", func(t *testing.T) {
+		problem := NewProblem(ProblemNotAvailable, "сохраненный текст")
+
+		assert.Equal(t, "сохраненный текст", problem.LocalizedMessage(catalog, LanguageEn))
+	})
+
+	t.Run("allow resale problem", func(t *testing.T) {
+		problem := NewAllowResale(false, "Шины").Problem()
+
+		assert.Equal(t, ProblemPurchaseReasonNotAvailableForUser, problem.Id())
+		assert.Equal(t, "Товарная группа Шины не подключена. Товар не может быть куплен для цели покупки "+
+			"«Перепродажа». Добавьте товарную группу в системе «Честный знак»", problem.Message())
+		assert.Equal(t, "Commodity group Шины is not connected. The product can't be purchased for the «Resale» "+
+			"purpose. Add the commodity group in the «Chestny Znak» system", problem.LocalizedMessage(catalog, LanguageEn))
+		assert.Equal(t, NewAllowResale(false, "").Message(), NewAllowResale(false, "").Problem().Message())
+	})
+
+	t.Run("info with params", func(t *testing.T) {
+		info := NewLocalizedInfo(
+			InfoIdPositionSplit,
+			MessagePositionSplit,
+			NewIntMessageParam("moved", 3),
+			NewIntMessageParam("remaining", 1),
+			NewIntMessageParam("max", 3),
+		)
+
+		assert.Equal(t, "В конфигурацию перенесено 3 шт., остальные 1 шт. остались в корзине отдельной позицией, "+
+			"так как в конфигурации может быть не больше 3 шт. одного товара.", info.Message())
+		assert.Equal(t, "3 pcs. are moved to the configuration, the remaining 1 pcs. stay in the basket as a "+
+			"separate item, as a configuration can't contain more than 3 pcs. of one product.",
+			info.LocalizedMessage(catalog, LanguageEn))
+	})
+
+	t.Run("info without key", func(t *testing.T) {
+		info := NewInfo(InfoIdPriceChanged, "сохраненный текст")
+
+		assert.Equal(t, "сохраненный текст", info.LocalizedMessage(catalog, LanguageKk))
+	})
+}
@@ -0,0 +1,289 @@
+package basket_item
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"gopkg.in/vmihailenco/msgpack.v2/codes"
+)
+
+// Эталонные байты записаны кодеками, написанными вручную до перехода на msgpackgen, из позиции goldenItem. Новые
+// кодеки должны читать их без потерь, а поля, которые были у структур до перехода, записывать байт в байт
+const (
+	goldenItemHex = "dc001ed92433623565386139632d316632642d346336652d396137622d306431653266336134623563a431303031a77072" +
+		"6f64756374d92437633864396530662d316132622d346333642d386534662d356136623763386439653066a432303032b4d092d0b8d0b4d0" +
+		"b5d0bed0bad0b0d180d182d0b0a9696d6167652e6a706702cd88b8cd015e0190919403b8d0bdd0b5d18220d0b220d0bdd0b0d0bbd0b8d187" +
+		"d0b8d0b8919191a431303031c381019301bdd186d0b5d0bdd0b020d0b8d0b7d0bcd0b5d0bdd0b8d0bbd0b0d181d18c9392cd84d0cd88b891" +
+		"0195a435303035a4756e697103acd09ad0b0d0b1d0b5d0bbd18ccd01f4910594dc0014c3142aa000c307c391a6637265646974a0a0c3c302" +
+		"c3b4d092d0b8d0b4d0b5d0bed0bad0b0d180d182d18ba56272616e64a470617468a573686f7274c392a4636f6e66019194b4323032332d30" +
+		"352d30365430373a30383a30395aa6737472656574ad37373030303030303030303030acd09cd0bed181d0bad0b2d0b092c3c3919403b8d0" +
+		"bdd0b5d18220d0b220d0bdd0b0d0bbd0b8d187d0b8d0b8919190c20000a66d736b5f636c02ab66696e6765727072696e74c3c3c392c3af63" +
+		"6f6d6d6f646974792067726f757094010203a570726f6d6fc3c3c3"
+	goldenProductHex = "dc0014c3142aa000c307c391a6637265646974a0a0c3c302c3b4d092d0b8d0b4d0b5d0bed0bad0b0d180d182d18ba562" +
+		"72616e64a470617468a573686f7274c3"
+	goldenItemAdditionsHex = "94dc0014c3142aa000c307c391a6637265646974a0a0c3c302c3b4d092d0b8d0b4d0b5d0bed0bad0b0d180d182" +
+		"d18ba56272616e64a470617468a573686f7274c392a4636f6e66019194b4323032332d30352d30365430373a30383a30395aa67374726565" +
+		"74ad37373030303030303030303030acd09cd0bed181d0bad0b2d0b092c3c3"
+	goldenInfoHex = "9301bdd186d0b5d0bdd0b020d0b8d0b7d0bcd0b5d0bdd0b8d0bbd0b0d181d18c9392cd84d0cd88b8910195a435303035a4" +
+		"756e697103acd09ad0b0d0b1d0b5d0bbd18ccd01f4"
+	goldenProblemHex     = "9403b8d0bdd0b5d18220d0b220d0bdd0b0d0bbd0b8d187d0b8d0b8919191a431303031c3"
+	goldenRulesHex       = "9105"
+	goldenAllowResaleHex = "92c3af636f6d6d6f646974792067726f7570"
+)
+
+// goldenItem позиция, в которой заполнены все поля, сохранявшиеся кодеками до перехода на msgpackgen
+func goldenItem() *Item {
+	return &Item{
+		uniqId:            "3b5e8a9c-1f2d-4c6e-9a7b-0d1e2f3a4b5c",
+		itemId:            "1001",
+		itemType:          TypeProduct,
+		parentUniqId:      "7c8d9e0f-1a2b-4c3d-8e4f-5a6b7c8d9e0f",
+		parentItemId:      "2002",
+		name:              "Видеокарта",
+		image:             "image.jpg",
+		count:             2,
+		price:             35000,
+		bonus:             350,
+		countMultiplicity: 1,
+		problems: []*Problem{{
+			id:      ProblemProductItemInConfigurationNotAvailable,
+			message: "нет в наличии",
+			additions: ProblemAdditions{ConfigurationProblemAdditions: ConfigurationProblemAdditions{
+				NotAvailableProductItemIds: []ItemId{"1001"},
+			}},
+			isHidden: true,
+		}},
+		infos: map[InfoId]*Info{
+			InfoIdPriceChanged: {
+				id:      InfoIdPriceChanged,
+				message: "цена изменилась",
+				additions: &InfoAdditions{
+					PriceChanged:       PriceChangedInfoAddition{From: 34000, To: 35000},
+					CountMoreThenAvail: CountMoreThenAvailInfoAdditions{AvailCount: 1},
+					ChangedItem: ChangedItemInfoAdditions{
+						ItemId: "5005", UniqId: "uniq", Count: 3, Name: "Кабель", Price: 500,
+					},
+				},
+			},
+		},
+		rules: Rules{maxCount: 5},
+		additions: ItemAdditions{
+			Product: &ProductItemAdditions{
+				isAvailInStore:              true,
+				vat:                         20,
+				categoryId:                  42,
+				isOEM:                       true,
+				availTotal:                  7,
+				isCountMoreThenAvailChecked: true,
+				creditPrograms:              []catalog_types.CreditProgram{"credit"},
+				isAvailForDPD:               true,
+				isMarked:                    true,
+				markedPurchaseReason:        MarkedPurchaseReasonForResale,
+				isDiscounted:                true,
+				categoryName:                "Видеокарты",
+				brandName:                   "brand",
+				categoryPath:                "path",
+				shortName:                   "short",
+				isFnsTracked:                true,
+			},
+			Configuration: &ConfiguratorItemAdditions{ConfId: "conf", ConfType: ConfTypeUser},
+			SubcontractServiceForProduct: &SubcontractItemAdditions{ApplyServiceInfo: &SubcontractApplyServiceInfo{
+				Date:        time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC),
+				Address:     "street",
+				CityKladrId: "7700000000000",
+				CityName:    "Москва",
+			}},
+			Service: &Service{IsCreditAvail: true, IsAvailableForInstallments: true},
+		},
+		permanentProblems: []*Problem{{
+			id:      ProblemProductItemInConfigurationNotAvailable,
+			message: "нет в наличии",
+			// пустой список при чтении восстанавливается пустым срезом, а не nil
+			additions: ProblemAdditions{ConfigurationProblemAdditions: ConfigurationProblemAdditions{
+				NotAvailableProductItemIds: []ItemId{},
+			}},
+		}},
+		spaceId:                  "msk_cl",
+		priceColumn:              catalog_types.PriceColumnClub,
+		commitFingerprint:        "fingerprint",
+		isPrepaymentMandatory:    true,
+		hasFairPrice:             true,
+		ignoreFairPrice:          true,
+		allowResale:              NewAllowResale(true, "commodity group"),
+		discount:                 ItemDiscount{Coupon: 1, Action: 2, Total: 3, AppliedPromotions: "promo"},
+		movableToConfiguration:   true,
+		movableFromConfiguration: true,
+		isSelected:               true,
+	}
+}
+
+// goldenItemWithNewFields позиция goldenItem, в которой заполнены и поля, добавленные после перехода на msgpackgen
+func goldenItemWithNewFields() *Item {
+	item := goldenItem()
+	item.problems[0].additions.ConfigurationProblemAdditions.IncompatibleProductItemIds = []ItemId{"3003", "4004"}
+	item.permanentProblems[0].additions.ConfigurationProblemAdditions.IncompatibleProductItemIds = []ItemId{}
+	item.infos[InfoIdPriceChanged].createdAt = time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	item.presentChoiceGroup = NewPresentChoiceGroup([]ItemId{"6006", "7007"}, "7007")
+	item.position = 3
+	item.addedAt = time.Date(2023, 5, 6, 7, 8, 9, 10, time.UTC)
+
+	return item
+}
+
+func TestMsgpack_Golden(t *testing.T) {
+	item := goldenItem()
+	// при чтении позиция обновляет отпечаток устаревшей версии и восстанавливает историю уведомлений
+	decodedItem := goldenItem()
+	decodedItem.afterDecode()
+
+	tests := []struct {
+		name string
+		hex  string
+		want interface{}
+		got  interface{}
+		// encode кодируется для сравнения с эталоном, по умолчанию совпадает с want
+		encode interface{}
+	}{
+		{name: "item", hex: goldenItemHex, want: decodedItem, got: &Item{}, encode: item},
+		{name: "product additions", hex: goldenProductHex, want: item.additions.Product, got: &ProductItemAdditions{}},
+		{name: "item additions", hex: goldenItemAdditionsHex, want: &item.additions, got: &ItemAdditions{}},
+		{name: "info", hex: goldenInfoHex, want: item.infos[InfoIdPriceChanged], got: &Info{}},
+		{name: "problem", hex: goldenProblemHex, want: item.problems[0], got: &Problem{}},
+		{name: "rules", hex: goldenRulesHex, want: &item.rules, got: &Rules{}},
+		{name: "allow resale", hex: goldenAllowResaleHex, want: item.allowResale, got: &AllowResale{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			golden, err := hex.DecodeString(tt.hex)
+			assert.NoError(t, err)
+
+			assert.NoError(t, msgpack.Unmarshal(golden, tt.got))
+			assert.Equal(t, tt.want, tt.got)
+
+			if tt.encode == nil {
+				tt.encode = tt.want
+			}
+			encoded, err := msgpack.Marshal(tt.encode)
+			assert.NoError(t, err)
+			assertGoldenLayout(t, golden, encoded)
+		})
+	}
+}
+
+func TestMsgpack_GoldenNewFields(t *testing.T) {
+	item := goldenItemWithNewFields()
+	decodedItem := goldenItemWithNewFields()
+	decodedItem.afterDecode()
+
+	tests := []struct {
+		name string
+		hex  string
+		want interface{}
+		got  interface{}
+		// encode кодируется для сравнения с эталоном, по умолчанию совпадает с want
+		encode interface{}
+	}{
+		{name: "item", hex: goldenItemHex, want: decodedItem, got: &Item{}, encode: item},
+		{name: "info", hex: goldenInfoHex, want: item.infos[InfoIdPriceChanged], got: &Info{}},
+		{name: "problem", hex: goldenProblemHex, want: item.problems[0], got: &Problem{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			golden, err := hex.DecodeString(tt.hex)
+			assert.NoError(t, err)
+
+			if tt.encode == nil {
+				tt.encode = tt.want
+			}
+			encoded, err := msgpack.Marshal(tt.encode)
+			assert.NoError(t, err)
+			// новые поля дописываются в конец, не меняя байты полей, которые были до перехода
+			assertGoldenLayout(t, golden, encoded)
+
+			assert.NoError(t, msgpack.Unmarshal(encoded, tt.got))
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+// assertGoldenLayout проверяет, что encoded совпадает с эталонными байтами golden байт в байт, кроме массивов
+// структур, в конец которых могут быть дописаны новые поля. Такие массивы длиннее эталонных, а их первые элементы
+// совпадают с эталонными
+func assertGoldenLayout(t *testing.T, golden []byte, encoded []byte) {
+	goldenReader := newGoldenReader(golden)
+	encodedReader := newGoldenReader(encoded)
+
+	assertGoldenValue(t, "value", goldenReader, encodedReader)
+	assert.Equal(t, 0, goldenReader.reader.Len(), "golden bytes are not read to the end")
+	assert.Equal(t, 0, encodedReader.reader.Len(), "encoded bytes are not read to the end")
+}
+
+func assertGoldenValue(t *testing.T, path string, golden *goldenReader, encoded *goldenReader) {
+	code, err := golden.decoder.PeekCode()
+	if !assert.NoError(t, err, path) {
+		return
+	}
+
+	switch {
+	case codes.IsFixedArray(code) || code == codes.Array16 || code == codes.Array32:
+		goldenLen, err := golden.decoder.DecodeArrayLen()
+		assert.NoError(t, err, path)
+		encodedLen, err := encoded.decoder.DecodeArrayLen()
+		if !assert.NoError(t, err, path) || !assert.True(t, encodedLen >= goldenLen, path) {
+			return
+		}
+
+		for i := 0; i < goldenLen; i++ {
+			assertGoldenValue(t, fmt.Sprintf("%s[%d]", path, i), golden, encoded)
+		}
+		for i := goldenLen; i < encodedLen; i++ {
+			assert.NoError(t, encoded.decoder.Skip(), path)
+		}
+	case codes.IsFixedMap(code) || code == codes.Map16 || code == codes.Map32:
+		goldenLen, err := golden.decoder.DecodeMapLen()
+		assert.NoError(t, err, path)
+		encodedLen, err := encoded.decoder.DecodeMapLen()
+		if !assert.NoError(t, err, path) || !assert.Equal(t, goldenLen, encodedLen, path) {
+			return
+		}
+
+		for i := 0; i < goldenLen*2; i++ {
+			assertGoldenValue(t, fmt.Sprintf("%s{%d}", path, i), golden, encoded)
+		}
+	default:
+		goldenRaw, err := golden.skip()
+		assert.NoError(t, err, path)
+		encodedRaw, err := encoded.skip()
+		assert.NoError(t, err, path)
+		assert.Equal(t, hex.EncodeToString(goldenRaw), hex.EncodeToString(encodedRaw), path)
+	}
+}
+
+// goldenReader читает msgpack значения и позволяет получить их байты
+type goldenReader struct {
+	data    []byte
+	reader  *bytes.Reader
+	decoder *msgpack.Decoder
+}
+
+func newGoldenReader(data []byte) *goldenReader {
+	reader := bytes.NewReader(data)
+
+	return &goldenReader{data: data, reader: reader, decoder: msgpack.NewDecoder(reader)}
+}
+
+// skip пропускает значение и возвращает его байты
+func (r *goldenReader) skip() ([]byte, error) {
+	start := len(r.data) - r.reader.Len()
+	err := r.decoder.Skip()
+
+	return r.data[start : len(r.data)-r.reader.Len()], err
+}
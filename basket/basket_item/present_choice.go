@@ -0,0 +1,75 @@
+package basket_item
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PresentChoiceGroup группа подарков на выбор.
+//
+// Некоторые акции предлагают пользователю выбрать один подарок из нескольких (например наушники или внешний
+// аккумулятор). Группа хранится у родительской позиции, к которой прикрепляется подарок, и содержит список возможных
+// подарков и выбранный пользователем подарок. В корзине у родительской позиции может находиться только один подарок
+// из группы.
+type PresentChoiceGroup struct {
+	candidateItemIds []ItemId     `msgpackidx:"1,slice,elem=string,get=CandidateItemIds"`
+	chosenItemId     ItemId       `msgpackidx:"2,string,get=ChosenItemId"`
+	mx               sync.RWMutex `msgpack:"-"`
+}
+
+func NewPresentChoiceGroup(candidateItemIds []ItemId, chosenItemId ItemId) *PresentChoiceGroup {
+	return &PresentChoiceGroup{candidateItemIds: candidateItemIds, chosenItemId: chosenItemId}
+}
+
+// CandidateItemIds возвращает идентификаторы подарков, из которых пользователь может выбрать один
+func (g *PresentChoiceGroup) CandidateItemIds() []ItemId {
+	g.mx.RLock()
+	defer g.mx.RUnlock()
+
+	return g.candidateItemIds
+}
+
+// SetCandidateItemIds задает список подарков на выбор. Выбранный подарок при этом не сбрасывается, его актуальность
+// проверяется при обновлении корзины
+func (g *PresentChoiceGroup) SetCandidateItemIds(v []ItemId) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.candidateItemIds = v
+}
+
+// ChosenItemId возвращает идентификатор выбранного подарка. Пустое значение означает, что подарок еще не выбран
+func (g *PresentChoiceGroup) ChosenItemId() ItemId {
+	g.mx.RLock()
+	defer g.mx.RUnlock()
+
+	return g.chosenItemId
+}
+
+// HasCandidate проверяет, входит ли подарок в список подарков на выбор
+func (g *PresentChoiceGroup) HasCandidate(itemId ItemId) bool {
+	g.mx.RLock()
+	defer g.mx.RUnlock()
+
+	for _, candidateItemId := range g.candidateItemIds {
+		if candidateItemId == itemId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Choose выбирает подарок из группы. Выбрать можно только подарок из списка подарков на выбор
+func (g *PresentChoiceGroup) Choose(itemId ItemId) error {
+	if !g.HasCandidate(itemId) {
+		return fmt.Errorf("present '%s' is not in choice group", itemId)
+	}
+
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	g.chosenItemId = itemId
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package basket_item
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresentChoiceGroup_HasCandidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		group  *PresentChoiceGroup
+		itemId ItemId
+		want   bool
+	}{
+		{
+			name:   "candidate",
+			group:  NewPresentChoiceGroup([]ItemId{"1", "2"}, ""),
+			itemId: "2",
+			want:   true,
+		},
+		{
+			name:   "not candidate",
+			group:  NewPresentChoiceGroup([]ItemId{"1", "2"}, ""),
+			itemId: "3",
+			want:   false,
+		},
+		{
+			name:   "empty group",
+			group:  NewPresentChoiceGroup(nil, ""),
+			itemId: "",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.group.HasCandidate(tt.itemId))
+		})
+	}
+}
+
+func TestPresentChoiceGroup_Choose(t *testing.T) {
+	tests := []struct {
+		name       string
+		group      *PresentChoiceGroup
+		itemId     ItemId
+		wantChosen ItemId
+		wantErr    error
+	}{
+		{
+			name:       "choose candidate",
+			group:      NewPresentChoiceGroup([]ItemId{"1", "2"}, "1"),
+			itemId:     "2",
+			wantChosen: "2",
+		},
+		{
+			name:       "choose not candidate",
+			group:      NewPresentChoiceGroup([]ItemId{"1", "2"}, "1"),
+			itemId:     "3",
+			wantChosen: "1",
+			wantErr:    fmt.Errorf("present '3' is not in choice group"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.group.Choose(tt.itemId)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantChosen, tt.group.ChosenItemId())
+		})
+	}
+}
+
+func TestPresentChoiceGroup_SetCandidateItemIds(t *testing.T) {
+	group := NewPresentChoiceGroup([]ItemId{"1", "2"}, "1")
+	group.SetCandidateItemIds([]ItemId{"3", "4"})
+
+	assert.Equal(t, []ItemId{"3", "4"}, group.CandidateItemIds())
+	assert.Equal(t, ItemId("1"), group.ChosenItemId())
+	assert.False(t, group.HasCandidate("1"))
+}
@@ -19,19 +19,136 @@ const (
 	ProblemPurchaseReasonNotAvailableForUser ProblemId = 6
 	// ProblemFnsTrackedItemNotAvailableForUser отслеживаемый товар недоступен для пользователя
 	ProblemFnsTrackedItemNotAvailableForUser ProblemId = 7
+	// ProblemConfigurationIncompatible комплектующие конфигурации несовместимы между собой
+	ProblemConfigurationIncompatible ProblemId = 8
 )
 
+// ProblemSeverity насколько проблема позиции мешает оформлению заказа
+type ProblemSeverity string
+
+const (
+	// ProblemSeverityBlocking заказ нельзя оформить, пока проблема не решена
+	ProblemSeverityBlocking ProblemSeverity = "blocking"
+	// ProblemSeverityWarning заказ оформить можно, но пользователя нужно предупредить о проблеме
+	ProblemSeverityWarning ProblemSeverity = "warning"
+	// ProblemSeverityInformational проблема не влияет на оформление заказа
+	ProblemSeverityInformational ProblemSeverity = "informational"
+)
+
+// ProblemSubsystem подсистема, в которой возникла проблема и через которую она решается
+type ProblemSubsystem string
+
+const (
+	// ProblemSubsystemAvailability наличие и доступность позиции в регионе
+	ProblemSubsystemAvailability ProblemSubsystem = "availability"
+	// ProblemSubsystemCount ограничения на кол-во позиции
+	ProblemSubsystemCount ProblemSubsystem = "count"
+	// ProblemSubsystemMarking маркированные и отслеживаемые товары
+	ProblemSubsystemMarking ProblemSubsystem = "marking"
+	// ProblemSubsystemConfiguration конфигурации
+	ProblemSubsystemConfiguration ProblemSubsystem = "configuration"
+	// ProblemSubsystemOther проблемы, подсистема которых неизвестна
+	ProblemSubsystemOther ProblemSubsystem = "other"
+)
+
+type problemSpec struct {
+	severity  ProblemSeverity
+	subsystem ProblemSubsystem
+}
+
+// problemSpecs важность и подсистема каждой проблемы
+var problemSpecs = map[ProblemId]problemSpec{
+	ProblemUnknown: {
+		severity:  ProblemSeverityInformational,
+		subsystem: ProblemSubsystemOther,
+	},
+	ProblemNotAvailable: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemAvailability,
+	},
+	ProblemMaxCountExcess: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemCount,
+	},
+	ProblemProductItemInConfigurationNotAvailable: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemConfiguration,
+	},
+	// услугу субподряда не выполнят по выбранному адресу, но товар, к которому она относится, оформить можно
+	ProblemNotAvailableInSelectedCity: {
+		severity:  ProblemSeverityWarning,
+		subsystem: ProblemSubsystemAvailability,
+	},
+	// пользователя нужно попросить выбрать цель приобретения, но ее отсутствие не мешает оформлению заказа
+	ProblemPurchaseReasonIsNotSelected: {
+		severity:  ProblemSeverityWarning,
+		subsystem: ProblemSubsystemMarking,
+	},
+	ProblemPurchaseReasonNotAvailableForUser: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemMarking,
+	},
+	ProblemFnsTrackedItemNotAvailableForUser: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemMarking,
+	},
+	ProblemConfigurationIncompatible: {
+		severity:  ProblemSeverityBlocking,
+		subsystem: ProblemSubsystemConfiguration,
+	},
+}
+
+// Severity важность проблемы. Проблемы, которых нет в problemSpecs (например, сохраненные более новой версией
+// сервиса), считаются блокирующими, чтобы не допустить оформление заказа с нерешенной проблемой
+func (id ProblemId) Severity() ProblemSeverity {
+	spec, ok := problemSpecs[id]
+	if !ok {
+		return ProblemSeverityBlocking
+	}
+
+	return spec.severity
+}
+
+// Subsystem подсистема, в которой возникла проблема
+func (id ProblemId) Subsystem() ProblemSubsystem {
+	spec, ok := problemSpecs[id]
+	if !ok {
+		return ProblemSubsystemOther
+	}
+
+	return spec.subsystem
+}
+
 func NewProblem(id ProblemId, message string) *Problem {
 	return &Problem{id: id, message: message, isHidden: false}
 }
 
+// NewLocalizedProblem создает проблему с сообщением из каталога переводов. Вместе с ключом сохраняется текст
+// сообщения на русском языке, который показывается, если перевода на нужный язык нет
+func NewLocalizedProblem(id ProblemId, key MessageKey, params ...*MessageParam) *Problem {
+	return &Problem{
+		id:            id,
+		message:       renderStored(key, params),
+		messageKey:    key,
+		messageParams: params,
+	}
+}
+
+//go:generate go run ../../tools/msgpackgen -output=problem_msgpack.go -types=Problem,ProblemAdditions,ConfigurationProblemAdditions
+
+//msgpack:min 3
+//msgpack:len-err (basket_item.Problem) len doesn't match
 type Problem struct {
-	id      ProblemId // 1
-	message string    // 2
+	id      ProblemId `msgpackidx:"1,int"`
+	message string    `msgpackidx:"2,string"`
 	// Дополнительные данные по проблеме
-	additions ProblemAdditions // 3
+	additions ProblemAdditions `msgpackidx:"3"`
 	// Является ли данная позиция скрытой
-	isHidden bool // 4
+	isHidden bool `msgpackidx:"4,bool"`
+	// Ключ сообщения в каталоге переводов, пустой у проблем, созданных до появления ключей, и у проблем с текстом
+	// из внешних источников
+	messageKey    MessageKey      `msgpackidx:"5,string"`
+	messageParams []*MessageParam `msgpackidx:"6,slice,nilempty"`
 }
 
 func (p *Problem) Id() ProblemId {
@@ -46,21 +163,54 @@ func (p *Problem) IsHidden() bool {
 	return p.isHidden
 }
 
+// Severity важность проблемы, определяется идентификатором проблемы
+func (p *Problem) Severity() ProblemSeverity {
+	return p.id.Severity()
+}
+
+// Subsystem подсистема, в которой возникла проблема, определяется идентификатором проблемы
+func (p *Problem) Subsystem() ProblemSubsystem {
+	return p.id.Subsystem()
+}
+
 func (p *Problem) Message() string {
 	return p.message
 }
 
+func (p *Problem) MessageKey() MessageKey {
+	return p.messageKey
+}
+
+func (p *Problem) MessageParams() []*MessageParam {
+	return p.messageParams
+}
+
+// LocalizedMessage текст сообщения на указанном языке, либо сохраненный текст, если перевода нет
+func (p *Problem) LocalizedMessage(catalog *MessageCatalog, language Language) string {
+	message, ok := catalog.Render(language, p.messageKey, p.messageParams)
+	if !ok {
+		return p.message
+	}
+
+	return message
+}
+
 // Additions возвращает дополнительные данные по проблеме
 func (p *Problem) Additions() *ProblemAdditions {
 	return &p.additions
 }
 
 // ProblemAdditions дополнительные данные по проблеме
+//
+//msgpack:len-err (basket_item.ProblemAdditions) len doesn't match
 type ProblemAdditions struct {
-	ConfigurationProblemAdditions ConfigurationProblemAdditions // 1
+	ConfigurationProblemAdditions ConfigurationProblemAdditions `msgpackidx:"1"`
 }
 
 // ConfigurationProblemAdditions дополнительные данные по проблемам с конфигурацией
+//
+//msgpack:min 1
+//msgpack:elem-err 1 2 ConfigurationProblemAdditions NotAvailableProductItemIds
 type ConfigurationProblemAdditions struct {
 	// Идентификаторы позиций, которые не в наличии или невозможно купить.
 	//
@@ -69,5 +219,10 @@ type ConfigurationProblemAdditions struct {
 	// Проблему решить возможно только удалением/разборкой самой конфигурации
 	//
 	// Эти данные проставляются вместе с проблемой ProblemProductItemInConfigurationNotAvailable
-	NotAvailableProductItemIds []ItemId // 1
+	NotAvailableProductItemIds []ItemId `msgpackidx:"1,slice,elem=string"`
+	// Идентификаторы комплектующих, которые несовместимы между собой по одному из правил совместимости.
+	//
+	// Эти данные проставляются вместе с проблемой ProblemConfigurationIncompatible, на каждое нарушенное правило
+	// добавляется отдельная проблема
+	IncompatibleProductItemIds []ItemId `msgpackidx:"2,slice,elem=string"`
 }
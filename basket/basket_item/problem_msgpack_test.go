@@ -67,6 +67,32 @@ func (s *ProblemMsgpackSuite) TestProblem_DecodeMsgpack() {
 				},
 			},
 		},
+		{
+			name: "message params is not slice negative",
+			args: []interface{}{1, "message", []interface{}{[]interface{}{[]interface{}{}}}, false, "key", 0},
+			err:  "can't decode msgpack field `Problem messageParams`[6]: msgpack: invalid code 0 decoding array length",
+		},
+		{
+			name: "positive with message key",
+			args: []interface{}{1, "message", []interface{}{[]interface{}{[]interface{}{}}}, false, "key",
+				[]interface{}{[]interface{}{"count", "int", "", 2}}},
+			expected: &Problem{
+				id:      1,
+				message: "message",
+				additions: ProblemAdditions{
+					ConfigurationProblemAdditions: ConfigurationProblemAdditions{
+						NotAvailableProductItemIds: []ItemId{},
+					},
+				},
+				messageKey:    "key",
+				messageParams: []*MessageParam{NewIntMessageParam("count", 2)},
+			},
+		},
+		{
+			name: "too long",
+			args: []interface{}{1, "message", []interface{}{[]interface{}{[]interface{}{}}}, false, "key", nil, nil},
+			err:  "can't decode msgpack field `(basket_item.Problem) len doesn't match`[0]: (basket_item.Problem) len doesn't match: 7",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -164,6 +190,26 @@ func (s *ProblemMsgpackSuite) TestConfigurationProblemAdditions_DecodeMsgpack()
 			args:     []interface{}{[]interface{}{}},
 			expected: &ConfigurationProblemAdditions{NotAvailableProductItemIds: []ItemId{}},
 		},
+		{
+			name: "too long",
+			args: []interface{}{[]interface{}{}, []interface{}{}, []interface{}{}},
+			err: "can't decode msgpack field `(basket_item.ConfigurationProblemAdditions) incorrect len`[0]: " +
+				"(basket_item.ConfigurationProblemAdditions) incorrect len: 3",
+		},
+		{
+			name: "IncompatibleProductItemIds is not slice negative",
+			args: []interface{}{[]interface{}{}, 0},
+			err: "can't decode msgpack field `ConfigurationProblemAdditions IncompatibleProductItemIds`[2]: " +
+				"msgpack: invalid code 0 decoding array length",
+		},
+		{
+			name: "positive with incompatible items",
+			args: []interface{}{[]interface{}{"1"}, []interface{}{"2", "3"}},
+			expected: &ConfigurationProblemAdditions{
+				NotAvailableProductItemIds: []ItemId{"1"},
+				IncompatibleProductItemIds: []ItemId{"2", "3"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -175,3 +175,65 @@ func (s *ProblemSuite) TestProblem_Message() {
 		})
 	}
 }
+
+func (s *ProblemSuite) TestProblem_Severity() {
+	tests := []struct {
+		name          string
+		id            ProblemId
+		wantSeverity  ProblemSeverity
+		wantSubsystem ProblemSubsystem
+	}{
+		{
+			name:          "not available",
+			id:            ProblemNotAvailable,
+			wantSeverity:  ProblemSeverityBlocking,
+			wantSubsystem: ProblemSubsystemAvailability,
+		},
+		{
+			name:          "configuration incompatible",
+			id:            ProblemConfigurationIncompatible,
+			wantSeverity:  ProblemSeverityBlocking,
+			wantSubsystem: ProblemSubsystemConfiguration,
+		},
+		{
+			name:          "not available in selected city",
+			id:            ProblemNotAvailableInSelectedCity,
+			wantSeverity:  ProblemSeverityWarning,
+			wantSubsystem: ProblemSubsystemAvailability,
+		},
+		{
+			name:          "purchase reason is not selected",
+			id:            ProblemPurchaseReasonIsNotSelected,
+			wantSeverity:  ProblemSeverityWarning,
+			wantSubsystem: ProblemSubsystemMarking,
+		},
+		{
+			name:          "unknown",
+			id:            ProblemUnknown,
+			wantSeverity:  ProblemSeverityInformational,
+			wantSubsystem: ProblemSubsystemOther,
+		},
+		{
+			name:          "not registered",
+			id:            ProblemId(1000),
+			wantSeverity:  ProblemSeverityBlocking,
+			wantSubsystem: ProblemSubsystemOther,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		s.Run(tt.name, func() {
+			problem := NewProblem(tt.id, "message")
+
+			s.Equal(tt.wantSeverity, problem.Severity())
+			s.Equal(tt.wantSubsystem, problem.Subsystem())
+		})
+	}
+}
+
+func (s *ProblemSuite) TestProblemSpecs() {
+	for id := ProblemUnknown; id <= ProblemConfigurationIncompatible; id++ {
+		_, ok := problemSpecs[id]
+		s.True(ok, "problem %d has no spec", id)
+	}
+}
@@ -5,34 +5,57 @@ import (
 	"sync"
 )
 
+// ProductItemAdditions данные о товаре
+//
+//msgpack:deleted 4 string
+//msgpack:deleted 5 int
+//msgpack:deleted 10 string
+//msgpack:deleted 11 string
+//msgpack:min 5
+//msgpack:open
+//msgpack:unchecked
+//msgpack:err 1 ProductItemAdditions IsAvailInStore
+//msgpack:err 2 ProductItemAdditions Vat
+//msgpack:err 3 ProductItemAdditions CategoryId
+//msgpack:err 4 ProductItemAdditions DecodeString
+//msgpack:err 5 ProductItemAdditions DecodeInt
+//msgpack:err 6 ProductItemAdditions IsOEM
+//msgpack:err 7 ProductItemAdditions AvailTotal
+//msgpack:err 8 ProductItemAdditions IsCountMoreThenAvailChecked
+//msgpack:err 9 ProductItemAdditions DecodeArrayLen
+//msgpack:elem-err 9 9 ProductItemAdditions DecodeString
+//msgpack:err 10 ProductItemAdditions DecodeString
+//msgpack:err 11 ProductItemAdditions DecodeString
+//msgpack:err 12 ProductItemAdditions DecodeBool
+//msgpack:decode-err 13 14 15 16 17 18 19 20
 type ProductItemAdditions struct {
-	isAvailInStore bool                     // 1
-	vat            int                      // 2
-	categoryId     catalog_types.CategoryId // 3
+	isAvailInStore bool                     `msgpackidx:"1,bool,get=IsAvailInStore,set=SetIsAvailInStore"`
+	vat            int                      `msgpackidx:"2,int,get=Vat,set=SetVat"`
+	categoryId     catalog_types.CategoryId `msgpackidx:"3,int,get=CategoryId,set=SetCategoryId"`
 	// Является ли товар OEM софтом
-	isOEM bool // 6
+	isOEM bool `msgpackidx:"6,bool,get=IsOEM,set=SetIsOEM"`
 	// Товаров в наличии
-	availTotal int // 7
+	availTotal int `msgpackidx:"7,int,get=AvailTotal,set=SetAvailTotal"`
 	// Проверялось ли наличие товара. Данный признак проставляется при сравнении запрашиваемого кол-ва и наличия товара
 	// на складах. Данный признак обнуляется при изменении запрашиваемого кол-ва позици.
-	isCountMoreThenAvailChecked bool                          // 8
-	creditPrograms              []catalog_types.CreditProgram // 9
+	isCountMoreThenAvailChecked bool                          `msgpackidx:"8,bool,get=IsCountMoreThenAvailChecked,set=SetIsCountMoreThenAvailChecked"`
+	creditPrograms              []catalog_types.CreditProgram `msgpackidx:"9,slice,elem=string,get=CreditPrograms,set=SetCreditPrograms"`
 	// Возможно ли этот товар доставлять в аутсорсовые точки выдачи DPD
-	isAvailForDPD bool // 12
+	isAvailForDPD bool `msgpackidx:"12,bool,get=IsAvailForDPD,set=SetIsAvailForDPD"`
 	// Является ли товар маркированным
-	isMarked bool // 13
+	isMarked bool `msgpackidx:"13,bool,get=IsMarked,set=SetIsMarked"`
 	// Цель покупки маркированного товара
-	markedPurchaseReason MarkedPurchaseReason // 14
+	markedPurchaseReason MarkedPurchaseReason `msgpackidx:"14,int,get=MarkedPurchaseReason,set=SetMarkedPurchaseReason"`
 	// Уценённый товар
-	isDiscounted bool // 15
+	isDiscounted bool `msgpackidx:"15,bool,get=IsDiscounted,set=SetIsDiscounted"`
 	// Название категории
-	categoryName string // 16
+	categoryName string `msgpackidx:"16,string,get=CategoryName,set=SetCategoryName"`
 	// Бренд
-	brandName    string // 17
-	categoryPath string // 18
-	shortName    string // 19
+	brandName    string `msgpackidx:"17,string,get=BrandName,set=SetBrandName"`
+	categoryPath string `msgpackidx:"18,string,get=CategoryPath,set=SetCategoryPath"`
+	shortName    string `msgpackidx:"19,string,get=ShortName,set=SetShortName"`
 	// Является ли товар прослеживаемым
-	isFnsTracked bool         // 20
+	isFnsTracked bool         `msgpackidx:"20,bool,get=IsFnsTracked,set=SetIsFnsTracked"`
 	mx           sync.RWMutex `msgpack:"-"`
 }
 
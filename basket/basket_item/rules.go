@@ -2,8 +2,9 @@ package basket_item
 
 import "sync"
 
+//msgpack:len-err len doesn't match
 type Rules struct {
-	maxCount int          // 1
+	maxCount int          `msgpackidx:"1,int,get=MaxCount,set=SetMaxCount"`
 	mx       sync.RWMutex `msgpack:"-"`
 }
 
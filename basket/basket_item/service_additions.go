@@ -4,9 +4,11 @@ import (
 	"sync"
 )
 
+//msgpack:min 1
+//msgpack:len-err (basket_item.Service) not enough fields
 type Service struct {
-	IsCreditAvail              bool         // 1
-	IsAvailableForInstallments bool         // 2
+	IsCreditAvail              bool         `msgpackidx:"1,bool,get=GetIsCreditAvail,set=SetIsCreditAvail"`
+	IsAvailableForInstallments bool         `msgpackidx:"2,bool,get=GetIsAvailableForInstallments,set=SetIsAvailableForInstallments"`
 	mx                         sync.RWMutex `msgpack:"-"`
 }
 
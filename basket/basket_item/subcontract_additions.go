@@ -7,11 +7,12 @@ import (
 	"time"
 )
 
+//msgpack:len-err (basket_item.SubcontractItemAdditions) len doesn't match
 type SubcontractItemAdditions struct {
 	// Доп. данные для оказания услуги
 	//
 	// задается самим пользователем, так что всегда необходимо проверять на nil
-	ApplyServiceInfo *SubcontractApplyServiceInfo // 1
+	ApplyServiceInfo *SubcontractApplyServiceInfo `msgpackidx:"1,get=GetApplyServiceInfo"`
 	mx               sync.RWMutex                 `msgpack:"-"`
 }
 
@@ -31,11 +32,13 @@ func (s *SubcontractItemAdditions) GetApplyServiceInfo() *SubcontractApplyServic
 	return s.ApplyServiceInfo
 }
 
+//msgpack:len-err (basket_item.SubcontractApplyServiceInfo) len doesn't match
+//msgpack:parse-err 1 SubcontractApplyServiceInfo time.Parse
 type SubcontractApplyServiceInfo struct {
-	Date        time.Time           // 1
-	Address     string              // 2
-	CityKladrId store_types.KladrId // 3
-	CityName    string              // 4
+	Date        time.Time           `msgpackidx:"1,rfc3339"`
+	Address     string              `msgpackidx:"2,string"`
+	CityKladrId store_types.KladrId `msgpackidx:"3,string"`
+	CityName    string              `msgpackidx:"4,string"`
 	mx          sync.RWMutex        `msgpack:"-"`
 }
 
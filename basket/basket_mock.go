@@ -72,6 +72,18 @@ func (mr *MockRefresherBasketMockRecorder) All() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "All", reflect.TypeOf((*MockRefresherBasket)(nil).All))
 }
 
+// ChangePrice mocks base method.
+func (m *MockRefresherBasket) ChangePrice(item *basket_item.Item, price int) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ChangePrice", item, price)
+}
+
+// ChangePrice indicates an expected call of ChangePrice.
+func (mr *MockRefresherBasketMockRecorder) ChangePrice(item, price any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePrice", reflect.TypeOf((*MockRefresherBasket)(nil).ChangePrice), item, price)
+}
+
 // Configuration mocks base method.
 func (m *MockRefresherBasket) Configuration() *Configuration {
 	m.ctrl.T.Helper()
@@ -128,18 +140,18 @@ func (mr *MockRefresherBasketMockRecorder) HasPossibleConfiguration() *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPossibleConfiguration", reflect.TypeOf((*MockRefresherBasket)(nil).HasPossibleConfiguration))
 }
 
-// Remove mocks base method.
-func (m *MockRefresherBasket) Remove(item *basket_item.Item, force bool) error {
+// RemoveBySystem mocks base method.
+func (m *MockRefresherBasket) RemoveBySystem(item *basket_item.Item, reason AuditReason) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Remove", item, force)
+	ret := m.ctrl.Call(m, "RemoveBySystem", item, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Remove indicates an expected call of Remove.
-func (mr *MockRefresherBasketMockRecorder) Remove(item, force any) *gomock.Call {
+// RemoveBySystem indicates an expected call of RemoveBySystem.
+func (mr *MockRefresherBasketMockRecorder) RemoveBySystem(item, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockRefresherBasket)(nil).Remove), item, force)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBySystem", reflect.TypeOf((*MockRefresherBasket)(nil).RemoveBySystem), item, reason)
 }
 
 // SelectedItems mocks base method.
@@ -0,0 +1,143 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"gopkg.in/vmihailenco/msgpack.v2"
+	"sync"
+)
+
+//go:generate mockgen -source=basket_repository.go -destination=basket_repository_mock.go -package=basket
+
+// BasketId идентификатор корзины в хранилище
+type BasketId string
+
+// BasketRepository хранилище данных корзин с оптимистичной блокировкой. Одну и ту же корзину могут одновременно
+// изменять несколько вкладок браузера или вкладка и мобильное приложение, поэтому каждое сохранение увеличивает
+// версию данных корзины, а сохранение поверх чужих изменений отклоняется
+type BasketRepository interface {
+	// Load возвращает данные корзины, если корзина не найдена, то возвращается internal.NotFoundError
+	Load(ctx context.Context, id BasketId) (*BasketData, error)
+	// Save сохраняет данные корзины, если версия корзины в хранилище равна expectedVersion (0 - корзина еще не
+	// сохранялась), и увеличивает версию данных. Если в хранилище другая версия, то возвращается VersionConflictError
+	Save(ctx context.Context, id BasketId, data *BasketData, expectedVersion int64) error
+}
+
+// VersionConflictError ошибка сохранения корзины, измененной с момента загрузки. Чтобы разрешить конфликт, нужно
+// загрузить корзину заново и повторить над ней операцию
+type VersionConflictError struct {
+	id              BasketId
+	expectedVersion int64
+	actualVersion   int64
+}
+
+func NewVersionConflictError(id BasketId, expectedVersion int64, actualVersion int64) *VersionConflictError {
+	return &VersionConflictError{id: id, expectedVersion: expectedVersion, actualVersion: actualVersion}
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf(
+		"basket '%s' version conflict: expected version %d, actual version %d",
+		e.id, e.expectedVersion, e.actualVersion,
+	)
+}
+
+func (e *VersionConflictError) Id() BasketId {
+	return e.id
+}
+
+// ExpectedVersion версия, с которой корзину пытались сохранить
+func (e *VersionConflictError) ExpectedVersion() int64 {
+	return e.expectedVersion
+}
+
+// ActualVersion версия корзины в хранилище
+func (e *VersionConflictError) ActualVersion() int64 {
+	return e.actualVersion
+}
+
+// marshalBasketDataVersion кодирует данные корзины так, как они будут сохранены с новой версией. Сами данные получают
+// новую версию только после успешного сохранения
+func marshalBasketDataVersion(data *BasketData, version int64) ([]byte, error) {
+	previousVersion := data.version
+	data.version = version
+	encoded, err := msgpack.Marshal(data)
+	data.version = previousVersion
+	if err != nil {
+		return nil, fmt.Errorf("can't encode basket data: %w", err)
+	}
+
+	return encoded, nil
+}
+
+func unmarshalStoredBasketData(id BasketId, encoded []byte) (*BasketData, error) {
+	data := &BasketData{}
+	err := msgpack.Unmarshal(encoded, data)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode basket '%s': %w", id, err)
+	}
+
+	return data, nil
+}
+
+// InMemoryBasketRepository хранилище корзин в памяти. Данные хранятся в закодированном виде, поэтому загруженные
+// корзины не разделяют состояние между собой и с сохраненными данными
+type InMemoryBasketRepository struct {
+	mx      sync.Mutex
+	baskets map[BasketId][]byte
+}
+
+func NewInMemoryBasketRepository() *InMemoryBasketRepository {
+	return &InMemoryBasketRepository{baskets: make(map[BasketId][]byte)}
+}
+
+func (r *InMemoryBasketRepository) Load(_ context.Context, id BasketId) (*BasketData, error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	return r.load(id)
+}
+
+func (r *InMemoryBasketRepository) Save(
+	_ context.Context,
+	id BasketId,
+	data *BasketData,
+	expectedVersion int64,
+) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	var actualVersion int64
+	if _, ok := r.baskets[id]; ok {
+		stored, err := r.load(id)
+		if err != nil {
+			return err
+		}
+
+		actualVersion = stored.Version()
+	}
+
+	if actualVersion != expectedVersion {
+		return NewVersionConflictError(id, expectedVersion, actualVersion)
+	}
+
+	encoded, err := marshalBasketDataVersion(data, expectedVersion+1)
+	if err != nil {
+		return err
+	}
+
+	r.baskets[id] = encoded
+	data.version = expectedVersion + 1
+
+	return nil
+}
+
+func (r *InMemoryBasketRepository) load(id BasketId) (*BasketData, error) {
+	encoded, ok := r.baskets[id]
+	if !ok {
+		return nil, internal.NewNotFoundError(fmt.Errorf("basket '%s' not found", id))
+	}
+
+	return unmarshalStoredBasketData(id, encoded)
+}
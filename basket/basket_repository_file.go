@@ -0,0 +1,128 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileBasketRepository хранилище корзин в файлах: данные каждой корзины хранятся в msgpack в отдельном файле
+// каталога. Файл заменяется целиком через переименование временного файла, поэтому при сбое записи в хранилище
+// остается предыдущая версия корзины. Проверка версии защищена блокировкой внутри процесса, поэтому каталог должен
+// использоваться только одним экземпляром хранилища
+type FileBasketRepository struct {
+	mx  sync.Mutex
+	dir string
+}
+
+func NewFileBasketRepository(dir string) *FileBasketRepository {
+	return &FileBasketRepository{dir: dir}
+}
+
+func (r *FileBasketRepository) Load(_ context.Context, id BasketId) (*BasketData, error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	path, err := r.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.load(id, path)
+}
+
+func (r *FileBasketRepository) Save(
+	_ context.Context,
+	id BasketId,
+	data *BasketData,
+	expectedVersion int64,
+) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	path, err := r.path(id)
+	if err != nil {
+		return err
+	}
+
+	var actualVersion int64
+	_, err = os.Stat(path)
+	if err == nil {
+		stored, err := r.load(id, path)
+		if err != nil {
+			return err
+		}
+
+		actualVersion = stored.Version()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("can't stat basket '%s' file: %w", id, err)
+	}
+
+	if actualVersion != expectedVersion {
+		return NewVersionConflictError(id, expectedVersion, actualVersion)
+	}
+
+	encoded, err := marshalBasketDataVersion(data, expectedVersion+1)
+	if err != nil {
+		return err
+	}
+
+	err = r.write(path, encoded)
+	if err != nil {
+		return fmt.Errorf("can't write basket '%s' file: %w", id, err)
+	}
+
+	data.version = expectedVersion + 1
+
+	return nil
+}
+
+func (r *FileBasketRepository) load(id BasketId, path string) (*BasketData, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, internal.NewNotFoundError(fmt.Errorf("basket '%s' not found", id))
+		}
+
+		return nil, fmt.Errorf("can't read basket '%s' file: %w", id, err)
+	}
+
+	return unmarshalStoredBasketData(id, encoded)
+}
+
+// write записывает данные во временный файл и переименовывает его в файл корзины
+func (r *FileBasketRepository) write(path string, encoded []byte) error {
+	file, err := os.CreateTemp(r.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(encoded)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// path путь к файлу корзины. Идентификатор корзины становится именем файла, поэтому он не может указывать за
+// пределы каталога хранилища
+func (r *FileBasketRepository) path(id BasketId) (string, error) {
+	if id == "" || strings.ContainsAny(string(id), `/\`) {
+		return "", internal.NewValidationError(fmt.Errorf("invalid basket id '%s'", id))
+	}
+
+	return filepath.Join(r.dir, string(id)+".msgpack"), nil
+}
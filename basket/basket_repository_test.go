@@ -0,0 +1,173 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBasketRepository(t *testing.T) {
+	repositories := []struct {
+		name       string
+		repository func(t *testing.T) BasketRepository
+	}{
+		{
+			name: "in memory",
+			repository: func(t *testing.T) BasketRepository {
+				return NewInMemoryBasketRepository()
+			},
+		},
+		{
+			name: "file",
+			repository: func(t *testing.T) BasketRepository {
+				return NewFileBasketRepository(t.TempDir())
+			},
+		},
+	}
+
+	newItem := func(itemId basket_item.ItemId) *basket_item.Item {
+		return basket_item.NewItem(itemId, basket_item.TypeProduct, "name", "", 1, 100, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+	}
+
+	for _, rr := range repositories {
+		rr := rr
+		t.Run(rr.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("load not existing basket", func(t *testing.T) {
+				_, err := rr.repository(t).Load(ctx, "not-existing")
+
+				assert.EqualError(t, err, "basket 'not-existing' not found")
+			})
+
+			t.Run("save and load", func(t *testing.T) {
+				repository := rr.repository(t)
+				data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+				_, err := data.Add(newItem("1"))
+				require.NoError(t, err)
+
+				require.NoError(t, repository.Save(ctx, "basket", data, 0))
+				assert.Equal(t, int64(1), data.Version())
+
+				loaded, err := repository.Load(ctx, "basket")
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), loaded.Version())
+				assert.Equal(t, data.Fingerprint(), loaded.Fingerprint())
+
+				_, err = loaded.Add(newItem("2"))
+				require.NoError(t, err)
+				require.NoError(t, repository.Save(ctx, "basket", loaded, loaded.Version()))
+				assert.Equal(t, int64(2), loaded.Version())
+
+				reloaded, err := repository.Load(ctx, "basket")
+				require.NoError(t, err)
+				assert.Equal(t, int64(2), reloaded.Version())
+				assert.Len(t, reloaded.All(), 2)
+			})
+
+			t.Run("version conflict", func(t *testing.T) {
+				repository := rr.repository(t)
+				require.NoError(t, repository.Save(ctx, "basket",
+					NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0))
+
+				// корзину загрузили в двух вкладках, первая вкладка сохранила изменения раньше второй
+				first, err := repository.Load(ctx, "basket")
+				require.NoError(t, err)
+				second, err := repository.Load(ctx, "basket")
+				require.NoError(t, err)
+
+				_, err = first.Add(newItem("1"))
+				require.NoError(t, err)
+				require.NoError(t, repository.Save(ctx, "basket", first, first.Version()))
+
+				_, err = second.Add(newItem("2"))
+				require.NoError(t, err)
+				err = repository.Save(ctx, "basket", second, second.Version())
+				assert.EqualError(t, err, "basket 'basket' version conflict: expected version 1, actual version 2")
+				var conflictErr *VersionConflictError
+				require.True(t, errors.As(err, &conflictErr))
+				assert.Equal(t, BasketId("basket"), conflictErr.Id())
+				assert.Equal(t, int64(1), conflictErr.ExpectedVersion())
+				assert.Equal(t, int64(2), conflictErr.ActualVersion())
+				assert.Equal(t, int64(1), second.Version())
+
+				// конфликт разрешается повтором операции над заново загруженной корзиной
+				second, err = repository.Load(ctx, "basket")
+				require.NoError(t, err)
+				_, err = second.Add(newItem("2"))
+				require.NoError(t, err)
+				require.NoError(t, repository.Save(ctx, "basket", second, second.Version()))
+
+				loaded, err := repository.Load(ctx, "basket")
+				require.NoError(t, err)
+				assert.Equal(t, int64(3), loaded.Version())
+				assert.ElementsMatch(t, []basket_item.ItemId{"1", "2"}, loaded.All().ItemIds())
+			})
+
+			t.Run("save new basket over existing", func(t *testing.T) {
+				repository := rr.repository(t)
+				require.NoError(t, repository.Save(ctx, "basket",
+					NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0))
+
+				err := repository.Save(ctx, "basket", NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0)
+
+				assert.EqualError(t, err, "basket 'basket' version conflict: expected version 0, actual version 1")
+			})
+
+			t.Run("save not existing basket with version", func(t *testing.T) {
+				data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+
+				err := rr.repository(t).Save(ctx, "basket", data, 1)
+
+				assert.EqualError(t, err, "basket 'basket' version conflict: expected version 1, actual version 0")
+				assert.Equal(t, int64(0), data.Version())
+			})
+		})
+	}
+}
+
+func TestFileBasketRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid basket id", func(t *testing.T) {
+		repository := NewFileBasketRepository(t.TempDir())
+
+		_, err := repository.Load(ctx, "../basket")
+		assert.EqualError(t, err, "invalid basket id '../basket'")
+
+		err = repository.Save(ctx, "", NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0)
+		assert.EqualError(t, err, "invalid basket id ''")
+	})
+
+	t.Run("corrupted file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "basket.msgpack"), []byte("corrupted"), 0o600))
+		repository := NewFileBasketRepository(dir)
+
+		_, err := repository.Load(ctx, "basket")
+		assert.ErrorContains(t, err, "can't decode basket 'basket'")
+
+		err = repository.Save(ctx, "basket", NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0)
+		assert.ErrorContains(t, err, "can't decode basket 'basket'")
+	})
+
+	t.Run("no temporary files left", func(t *testing.T) {
+		dir := t.TempDir()
+		repository := NewFileBasketRepository(dir)
+
+		require.NoError(t, repository.Save(ctx, "basket",
+			NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk"), 0))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "basket.msgpack", entries[0].Name())
+	})
+}
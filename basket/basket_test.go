@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,7 @@ func TestNewBasket(t *testing.T) {
 		basketData *BasketData
 		mrkOpts    *markingOptions
 		subCtrOpts *subcontractServiceChangeOptions
+		confOpts   *ConfigurationOptions
 	}{
 		user: &userv1.User{
 			SpaceId:     "test_space_id",
@@ -41,6 +43,7 @@ func TestNewBasket(t *testing.T) {
 		subCtrOpts: NewSubcontractServiceChangeOptions(
 			false,
 		),
+		confOpts: NewConfigurationOptions(NewConfigurationValidator(nil), false),
 	}
 	want := &Basket{
 		data:                            args.basketData,
@@ -48,7 +51,7 @@ func TestNewBasket(t *testing.T) {
 		markingOptions:                  args.mrkOpts,
 		subcontractServiceChangeOptions: args.subCtrOpts,
 	}
-	want.configuration = NewConfiguration(want, nil, nil)
+	want.configuration = NewConfiguration(want, nil, nil, args.confOpts)
 	got := NewBasket(
 		args.basketData,
 		nil,
@@ -60,6 +63,7 @@ func TestNewBasket(t *testing.T) {
 		args.mrkOpts,
 		args.subCtrOpts,
 		nil,
+		args.confOpts,
 	)
 	assert.Equal(t, want, got)
 }
@@ -278,13 +282,58 @@ func TestBasket_Add(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Run(tt.name, func(t *testing.T) {
 			b := tt.init(ctrl)
-			got, err := b.Add(tt.args.ctx, tt.args.itemId, tt.args.itemType, tt.args.parentUniqId, tt.args.count, tt.args.ignoreFairPrice)
+			got, err := b.Add(tt.args.ctx, tt.args.itemId, tt.args.itemType, tt.args.parentUniqId, tt.args.count, tt.args.ignoreFairPrice, "")
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
+func TestBasket_AddIdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	item := basket_item.NewItem("test_itemId", basket_item.TypeProduct, "", "", 1, 100, 0, "spb",
+		catalog_types.PriceColumnClub)
+	item.Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	mockItemFactory := basket_item.NewMockItemFactory(ctrl)
+	mockItemFactory.EXPECT().Create(
+		context.Background(),
+		basket_item.ItemId("test_itemId"),
+		store_types.SpaceId("spb"),
+		basket_item.TypeProduct,
+		1,
+		nil,
+		catalog_types.PriceColumnClub,
+		nil,
+		false,
+	).Return(item, nil).Times(1)
+	b := &Basket{
+		data:        NewBasketData("spb", catalog_types.PriceColumnClub, ""),
+		itemFactory: mockItemFactory,
+	}
+
+	got, err := b.Add(context.Background(), "test_itemId", basket_item.TypeProduct, "", 1, false, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, item, got)
+
+	// повтор запроса не создает позицию заново и не увеличивает кол-во
+	got, err = b.Add(context.Background(), "test_itemId", basket_item.TypeProduct, "", 1, false, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, item, got)
+	assert.Equal(t, 1, item.Count())
+	assert.Equal(t, 1, b.Count())
+
+	err = b.Remove(item, false, "key")
+	assert.Equal(t, internal.NewValidationError(errors.New("idempotency key 'key' is already used for operation 'add'")),
+		err)
+
+	// повтор после удаления позиции не добавляет ее заново: операция уже выполнена, а позиции в корзине нет
+	assert.NoError(t, b.Remove(item, false, ""))
+	got, err = b.Add(context.Background(), "test_itemId", basket_item.TypeProduct, "", 1, false, "key")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	assert.Equal(t, 0, b.Count())
+}
+
 func TestBasket_AddItem(t *testing.T) {
 	type args struct {
 		item *basket_item.Item
@@ -564,6 +613,12 @@ func TestBasket_Clear(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			b := tt.basket
 			b.Clear()
+			assert.False(t, b.data.ModifiedAt().IsZero())
+			tt.want.data.createdAt, tt.want.data.modifiedAt = b.data.CreatedAt(), b.data.ModifiedAt()
+			if assert.Len(t, b.data.AuditTail(), 1) {
+				assert.Equal(t, AuditOperationRemove, b.data.AuditTail()[0].Operation())
+			}
+			tt.want.data.auditTail, tt.want.data.pendingAuditEntries = b.data.AuditTail(), b.data.PendingAuditEntries()
 			assert.Equal(t, tt.want, b)
 		})
 	}
@@ -614,7 +669,7 @@ func TestBasket_CommitChanges(t *testing.T) {
 	tests := []struct {
 		name   string
 		fields fields
-		want   func(fingerpring string) *Basket
+		want   func(fingerpring string, modifiedAt time.Time) *Basket
 	}{
 		{
 			name: "successful test",
@@ -625,13 +680,15 @@ func TestBasket_CommitChanges(t *testing.T) {
 					items:       map[basket_item.UniqId]*basket_item.Item{},
 				},
 			},
-			want: func(fingerpring string) *Basket {
+			want: func(fingerpring string, modifiedAt time.Time) *Basket {
 				return &Basket{
 					data: &BasketData{
 						spaceId:           "msk",
 						priceColumn:       catalog_types.PriceColumnClub,
 						items:             map[basket_item.UniqId]*basket_item.Item{},
 						commitFingerprint: fingerpring,
+						createdAt:         modifiedAt,
+						modifiedAt:        modifiedAt,
 					},
 				}
 			},
@@ -644,7 +701,8 @@ func TestBasket_CommitChanges(t *testing.T) {
 				data: tt.fields.data,
 			}
 			b.CommitChanges()
-			assert.Equal(t, tt.want(b.Fingerprint()), b)
+			assert.False(t, b.data.ModifiedAt().IsZero())
+			assert.Equal(t, tt.want(b.Fingerprint(), b.data.ModifiedAt()), b)
 		})
 	}
 }
@@ -1237,6 +1295,7 @@ func TestBasket_Refresh(t *testing.T) {
 		args    args
 		init    func(ctrl *gomock.Controller, args *args) *Basket
 		wantErr func(args *args) string
+		check   func(t *testing.T, b *Basket)
 	}{
 		{
 			name: "basket space does not equal to subcontract change service",
@@ -1811,6 +1870,101 @@ func TestBasket_Refresh(t *testing.T) {
 				return ""
 			},
 		},
+		{
+			name: "system changes keep modification time",
+			args: args{
+				ctx:    context.Background(),
+				logger: zap.NewNop(),
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				mockItemRefresher := NewMockitemRefresher(ctrl)
+				mockActualizerItems := NewMockActualizerItems(ctrl)
+				configItem := basket_item.NewItem(
+					"test_configuration", basket_item.TypeConfiguration, "", "", 1, 15, 0, "test_space_id", 0,
+				)
+				mockItemRefresher.EXPECT().Refresh(args.ctx, gomock.Any(), args.logger).Return(nil).Times(1)
+				mockActualizerItems.EXPECT().FindByItem(configItem).Return(nil).Times(1)
+				mockActualizerItems.EXPECT().FindByType(basket_item.TypePresent).Return(
+					[]ActualizerItem{},
+				).Times(1)
+				mockActualizerItems.EXPECT().FindByType(basket_item.TypeConfigurationProductService).Return(
+					[]ActualizerItem{},
+				).Times(1)
+				args.actualizerItems = mockActualizerItems
+
+				data := &BasketData{
+					items: map[basket_item.UniqId]*basket_item.Item{
+						configItem.UniqId(): configItem,
+					},
+				}
+				data.CommitChanges()
+				data.createdAt = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+				data.modifiedAt = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+				return &Basket{
+					data:          data,
+					itemRefresher: mockItemRefresher,
+					subcontractServiceChangeOptions: &subcontractServiceChangeOptions{
+						subcontractServicesChangeEnabled: false,
+					},
+				}
+			},
+			wantErr: func(args *args) string {
+				return ""
+			},
+			check: func(t *testing.T, b *Basket) {
+				// цену конфигурации пересчитала система
+				assert.Equal(t, 0, b.data.FindByType(basket_item.TypeConfiguration)[0].Price())
+				assert.Equal(t, time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), b.data.ModifiedAt())
+				assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), b.data.CreatedAt())
+			},
+		},
+		{
+			name: "present out of stock fault scenario",
+			args: args{
+				ctx:    context.Background(),
+				logger: zap.NewNop(),
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				mockItemRefresher := NewMockitemRefresher(ctrl)
+				mockActualizerItems := NewMockActualizerItems(ctrl)
+				presentAItem := NewMockActualizerItem(ctrl)
+				presentAItem.EXPECT().GetItemId().Return(basket_item.ItemId("test_present")).AnyTimes()
+				configItem := basket_item.NewItem(
+					"test_configuration", basket_item.TypeConfiguration, "", "", 1, 15, 0, "test_space_id", 0,
+				)
+				mockItemRefresher.EXPECT().Refresh(gomock.Any(), gomock.Any(), args.logger).Return(nil).Times(1)
+				mockActualizerItems.EXPECT().FindByItem(configItem).Return(nil).Times(1)
+				mockActualizerItems.EXPECT().FindByType(basket_item.TypePresent).Return(
+					[]ActualizerItem{presentAItem},
+				).Times(1)
+				mockActualizerItems.EXPECT().FindByType(basket_item.TypeConfigurationProductService).Return(
+					[]ActualizerItem{},
+				).Times(1)
+				args.actualizerItems = mockActualizerItems
+
+				return &Basket{
+					data: &BasketData{
+						items: map[basket_item.UniqId]*basket_item.Item{
+							configItem.UniqId(): configItem,
+						},
+						faultScenarios: []*FaultScenario{NewFaultScenario(FaultScenarioPresentOutOfStock)},
+					},
+					itemRefresher: mockItemRefresher,
+					subcontractServiceChangeOptions: &subcontractServiceChangeOptions{
+						subcontractServicesChangeEnabled: false,
+					},
+					faultScenarios: NewFaultScenarioRegistry(true),
+				}
+			},
+			wantErr: func(args *args) string {
+				return ""
+			},
+			check: func(t *testing.T, b *Basket) {
+				// закончившийся подарок актуализатор не вернул, поэтому в корзину он не добавлен
+				assert.Empty(t, b.data.FindByType(basket_item.TypePresent))
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -1823,6 +1977,9 @@ func TestBasket_Refresh(t *testing.T) {
 			} else {
 				assert.EqualError(t, err, tt.wantErr(&tt.args))
 			}
+			if tt.check != nil {
+				tt.check(t, b)
+			}
 		})
 	}
 }
@@ -1892,12 +2049,143 @@ func TestBasket_Remove(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			b := tt.init(&tt.args)
-			err := b.Remove(tt.args.item, tt.args.force)
+			err := b.Remove(tt.args.item, tt.args.force, "")
 			assert.Equal(t, tt.wantErr(&tt.args), err)
 		})
 	}
 }
 
+func TestBasket_RemoveIdempotencyKey(t *testing.T) {
+	item := basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 100, 0, "spb", catalog_types.PriceColumnClub)
+	b := &Basket{data: NewBasketData("spb", catalog_types.PriceColumnClub, "")}
+	_, err := b.data.Add(item)
+	assert.NoError(t, err)
+
+	assert.NoError(t, b.Remove(item, false, "key"))
+	assert.Equal(t, 0, b.Count())
+
+	// при повторе запроса позиция уже удалена и не найдена вызывающим кодом
+	assert.NoError(t, b.Remove(nil, false, "key"))
+	assert.Equal(t, []*AppliedOperation{
+		NewAppliedOperation("key", OperationTypeRemove, []basket_item.UniqId{item.UniqId()}),
+	}, b.data.AppliedOperations())
+}
+
+func TestBasket_Move(t *testing.T) {
+	newMoveBasket := func(t *testing.T) (*Basket, map[string]*basket_item.Item) {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		items := map[string]*basket_item.Item{
+			"first": basket_item.NewItem(
+				"1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+			),
+			"second": basket_item.NewItem(
+				"2", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+			),
+			"service": basket_item.NewItem(
+				"3", basket_item.TypeSubcontractServiceForProduct, "", "", 1, 10, 0, "msk_cl", catalog_types.PriceColumnRetail,
+			),
+			"third": basket_item.NewItem(
+				"4", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail,
+			),
+		}
+		assert.NoError(t, items["second"].AddChild(items["service"]))
+		for _, name := range []string{"first", "second", "service", "third"} {
+			_, err := data.Add(items[name])
+			assert.NoError(t, err)
+		}
+
+		return &Basket{data: data}, items
+	}
+
+	tests := []struct {
+		name      string
+		item      string
+		before    string
+		wantErr   func(items map[string]*basket_item.Item) error
+		wantOrder []string
+	}{
+		{
+			name:      "move before another item",
+			item:      "third",
+			before:    "first",
+			wantOrder: []string{"third", "first", "second", "service"},
+		},
+		{
+			name:      "move with children",
+			item:      "second",
+			before:    "first",
+			wantOrder: []string{"second", "service", "first", "third"},
+		},
+		{
+			name:      "move to the end",
+			item:      "first",
+			wantOrder: []string{"second", "service", "third", "first"},
+		},
+		{
+			name:   "move before itself",
+			item:   "first",
+			before: "first",
+			wantErr: func(items map[string]*basket_item.Item) error {
+				return internal.NewValidationError(
+					fmt.Errorf("item '%s' can't be moved before itself", items["first"].UniqId()),
+				)
+			},
+		},
+		{
+			name: "move child",
+			item: "service",
+			wantErr: func(items map[string]*basket_item.Item) error {
+				return internal.NewLogicError(
+					fmt.Errorf("item '%s' is child and can't be moved", items["service"].UniqId()),
+				)
+			},
+		},
+		{
+			name:   "move before child",
+			item:   "first",
+			before: "service",
+			wantErr: func(items map[string]*basket_item.Item) error {
+				return internal.NewLogicError(
+					fmt.Errorf("item '%s' is child, items can't be moved before it", items["service"].UniqId()),
+				)
+			},
+		},
+		{
+			name: "item not found",
+			item: "unknown",
+			wantErr: func(items map[string]*basket_item.Item) error {
+				return internal.NewNotFoundError(fmt.Errorf("item '%s' not found in basket", "unknown"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b, items := newMoveBasket(t)
+			uniqId, beforeUniqId := basket_item.UniqId(tt.item), basket_item.UniqId(tt.before)
+			if item, ok := items[tt.item]; ok {
+				uniqId = item.UniqId()
+			}
+			if item, ok := items[tt.before]; ok {
+				beforeUniqId = item.UniqId()
+			}
+
+			err := b.Move(uniqId, beforeUniqId)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr(items), err)
+				return
+			}
+
+			assert.NoError(t, err)
+			var want basket_item.Items
+			for _, name := range tt.wantOrder {
+				want = append(want, items[name])
+			}
+			assert.Equal(t, want, b.All())
+		})
+	}
+}
+
 func TestBasket_SetSpaceId(t *testing.T) {
 	type fields struct {
 		data *BasketData
@@ -2389,3 +2677,325 @@ func TestBasket_IsMarkingAvailable(t *testing.T) {
 		})
 	}
 }
+
+func TestBasket_ChoosePresent(t *testing.T) {
+	type args struct {
+		ctx          context.Context
+		parentUniqId basket_item.UniqId
+		itemId       basket_item.ItemId
+	}
+	tests := []struct {
+		name         string
+		args         args
+		init         func(ctrl *gomock.Controller, args *args) *Basket
+		wantPresents []basket_item.ItemId
+		wantChosen   basket_item.ItemId
+		wantErr      func(args *args) error
+	}{
+		{
+			name: "parent not found",
+			args: args{
+				ctx:          context.Background(),
+				parentUniqId: "not_found",
+				itemId:       "present_2",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				return &Basket{
+					data: &BasketData{
+						items: map[basket_item.UniqId]*basket_item.Item{},
+					},
+				}
+			},
+			wantErr: func(args *args) error {
+				return internal.NewNotFoundError(errors.New("parent item 'not_found' not found in basket"))
+			},
+		},
+		{
+			name: "parent without present choice",
+			args: args{
+				ctx:    context.Background(),
+				itemId: "present_2",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 1, 0, 0, "msk_cl", 0)
+				args.parentUniqId = parentItem.UniqId()
+
+				return &Basket{
+					data: &BasketData{
+						items: map[basket_item.UniqId]*basket_item.Item{
+							parentItem.UniqId(): parentItem,
+						},
+					},
+				}
+			},
+			wantErr: func(args *args) error {
+				return internal.NewLogicError(fmt.Errorf("item '%s' has no present choice", args.parentUniqId))
+			},
+		},
+		{
+			name: "present is not candidate",
+			args: args{
+				ctx:    context.Background(),
+				itemId: "present_3",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 1, 0, 0, "msk_cl", 0)
+				parentItem.SetPresentChoiceGroup(basket_item.NewPresentChoiceGroup(
+					[]basket_item.ItemId{"present_1", "present_2"},
+					"present_1",
+				))
+				args.parentUniqId = parentItem.UniqId()
+
+				return &Basket{
+					data: &BasketData{
+						items: map[basket_item.UniqId]*basket_item.Item{
+							parentItem.UniqId(): parentItem,
+						},
+					},
+				}
+			},
+			wantErr: func(args *args) error {
+				return internal.NewValidationError(
+					fmt.Errorf("present 'present_3' can't be chosen for item '%s'", args.parentUniqId),
+				)
+			},
+		},
+		{
+			name: "catalog error",
+			args: args{
+				ctx:    context.Background(),
+				itemId: "present_2",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 1, 0, 0, "msk_cl", 0)
+				parentItem.SetPresentChoiceGroup(basket_item.NewPresentChoiceGroup(
+					[]basket_item.ItemId{"present_1", "present_2"},
+					"present_1",
+				))
+				args.parentUniqId = parentItem.UniqId()
+				mockProductAPIClient := productv1mock.NewMockProductAPIClient(ctrl)
+				mockProductAPIClient.EXPECT().FindFull(args.ctx, &productv1.FindFullRequest{
+					Ids:     []string{"present_2"},
+					SpaceId: "msk_cl",
+				}).Return(nil, errors.New("test error")).Times(1)
+
+				return &Basket{
+					data: &BasketData{
+						spaceId: "msk_cl",
+						items: map[basket_item.UniqId]*basket_item.Item{
+							parentItem.UniqId(): parentItem,
+						},
+					},
+					productClient: mockProductAPIClient,
+				}
+			},
+			wantPresents: []basket_item.ItemId{},
+			wantChosen:   "present_1",
+			wantErr: func(args *args) error {
+				return internal.NewCatalogError(
+					fmt.Errorf("can't get present 'present_2' from catalog: %w", errors.New("test error")),
+					"msk_cl",
+				)
+			},
+		},
+		{
+			name: "present already chosen",
+			args: args{
+				ctx:    context.Background(),
+				itemId: "present_1",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 2, 0, 0, "msk_cl", 0)
+				parentItem.SetPresentChoiceGroup(basket_item.NewPresentChoiceGroup(
+					[]basket_item.ItemId{"present_1", "present_2"},
+					"present_1",
+				))
+				presentItem := basket_item.NewItem("present_1", basket_item.TypePresent, "", "", 2, 0, 0, "msk_cl", 0)
+				_ = parentItem.AddChild(presentItem)
+				args.parentUniqId = parentItem.UniqId()
+
+				return &Basket{
+					data: &BasketData{
+						spaceId: "msk_cl",
+						items: map[basket_item.UniqId]*basket_item.Item{
+							parentItem.UniqId():  parentItem,
+							presentItem.UniqId(): presentItem,
+						},
+					},
+				}
+			},
+			wantPresents: []basket_item.ItemId{"present_1"},
+			wantChosen:   "present_1",
+		},
+		{
+			name: "swap present",
+			args: args{
+				ctx:    context.Background(),
+				itemId: "present_2",
+			},
+			init: func(ctrl *gomock.Controller, args *args) *Basket {
+				parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 2, 0, 0, "msk_cl", 0)
+				parentItem.SetPresentChoiceGroup(basket_item.NewPresentChoiceGroup(
+					[]basket_item.ItemId{"present_1", "present_2"},
+					"present_1",
+				))
+				presentItem := basket_item.NewItem("present_1", basket_item.TypePresent, "", "", 2, 0, 0, "msk_cl", 0)
+				_ = parentItem.AddChild(presentItem)
+				args.parentUniqId = parentItem.UniqId()
+				mockProductAPIClient := productv1mock.NewMockProductAPIClient(ctrl)
+				mockProductAPIClient.EXPECT().FindFull(args.ctx, &productv1.FindFullRequest{
+					Ids:     []string{"present_2"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{
+						{
+							Id: "present_2",
+							Regional: &productv1.FindFullResponse_FullInfo_Regional{
+								Name:      "Внешний аккумулятор",
+								ImageName: "image",
+							},
+						},
+					},
+				}, nil).Times(1)
+
+				return &Basket{
+					data: &BasketData{
+						spaceId: "msk_cl",
+						items: map[basket_item.UniqId]*basket_item.Item{
+							parentItem.UniqId():  parentItem,
+							presentItem.UniqId(): presentItem,
+						},
+					},
+					productClient: mockProductAPIClient,
+				}
+			},
+			wantPresents: []basket_item.ItemId{"present_2"},
+			wantChosen:   "present_2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		ctrl := gomock.NewController(t)
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.init(ctrl, &tt.args)
+			present, err := b.ChoosePresent(tt.args.ctx, tt.args.parentUniqId, tt.args.itemId)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr(&tt.args), err)
+			} else {
+				assert.Nil(t, err)
+			}
+			if tt.wantPresents == nil {
+				return
+			}
+
+			parentItem := b.data.FindOneById(tt.args.parentUniqId)
+			presentItemIds := make([]basket_item.ItemId, 0)
+			for _, item := range b.data.Find(Finders.ChildrenOf(parentItem)) {
+				presentItemIds = append(presentItemIds, item.ItemId())
+			}
+			assert.Equal(t, tt.wantPresents, presentItemIds)
+			assert.Equal(t, tt.wantChosen, parentItem.PresentChoiceGroup().ChosenItemId())
+			if err == nil {
+				assert.Equal(t, tt.args.itemId, present.ItemId())
+				assert.Equal(t, 2, present.Count())
+			}
+		})
+	}
+}
+
+// presentChoiceActualizerItems актуализатор, который сообщает о подарках на выбор
+type presentChoiceActualizerItems struct {
+	*MockActualizerItems
+	*MockPresentChoiceActualizerItems
+}
+
+func TestBasket_refreshPresentChoiceGroups(t *testing.T) {
+	tests := []struct {
+		name            string
+		choices         []basket_item.ItemId
+		group           *basket_item.PresentChoiceGroup
+		presents        []basket_item.ItemId
+		withChoices     bool
+		wantGroup       *basket_item.PresentChoiceGroup
+		wantPresentsLen int
+	}{
+		{
+			name:            "actualizer without present choices",
+			group:           basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_1"),
+			presents:        []basket_item.ItemId{"present_1"},
+			wantGroup:       basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_1"),
+			wantPresentsLen: 1,
+		},
+		{
+			name:            "group created with present from basket",
+			withChoices:     true,
+			choices:         []basket_item.ItemId{"present_1", "present_2"},
+			presents:        []basket_item.ItemId{"present_2"},
+			wantGroup:       basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_2"),
+			wantPresentsLen: 1,
+		},
+		{
+			name:            "only one present left after promotion change",
+			withChoices:     true,
+			choices:         []basket_item.ItemId{"present_1"},
+			group:           basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_2"),
+			presents:        []basket_item.ItemId{"present_2"},
+			wantGroup:       nil,
+			wantPresentsLen: 1,
+		},
+		{
+			name:            "chosen present is no longer candidate",
+			withChoices:     true,
+			choices:         []basket_item.ItemId{"present_1", "present_3"},
+			group:           basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_2"),
+			presents:        []basket_item.ItemId{"present_2"},
+			wantGroup:       basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_3"}, "present_1"),
+			wantPresentsLen: 0,
+		},
+		{
+			name:            "several presents of one group in basket",
+			withChoices:     true,
+			choices:         []basket_item.ItemId{"present_1", "present_2"},
+			group:           basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_2"),
+			presents:        []basket_item.ItemId{"present_1", "present_2"},
+			wantGroup:       basket_item.NewPresentChoiceGroup([]basket_item.ItemId{"present_1", "present_2"}, "present_2"),
+			wantPresentsLen: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		ctrl := gomock.NewController(t)
+		t.Run(tt.name, func(t *testing.T) {
+			parentItem := basket_item.NewItem("parent", basket_item.TypeProduct, "", "", 1, 0, 0, "msk_cl", 0)
+			parentItem.SetPresentChoiceGroup(tt.group)
+			items := map[basket_item.UniqId]*basket_item.Item{
+				parentItem.UniqId(): parentItem,
+			}
+			for _, presentItemId := range tt.presents {
+				presentItem := basket_item.NewItem(presentItemId, basket_item.TypePresent, "", "", 1, 0, 0, "msk_cl", 0)
+				_ = parentItem.AddChild(presentItem)
+				items[presentItem.UniqId()] = presentItem
+			}
+
+			var actualizerItems ActualizerItems = NewMockActualizerItems(ctrl)
+			if tt.withChoices {
+				mockPresentChoices := NewMockPresentChoiceActualizerItems(ctrl)
+				mockPresentChoices.EXPECT().FindPresentChoices(parentItem).Return(tt.choices).Times(1)
+				actualizerItems = &presentChoiceActualizerItems{
+					MockActualizerItems:              NewMockActualizerItems(ctrl),
+					MockPresentChoiceActualizerItems: mockPresentChoices,
+				}
+			}
+
+			b := &Basket{
+				data: &BasketData{
+					items: items,
+				},
+			}
+			err := b.refreshPresentChoiceGroups(actualizerItems)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantGroup, parentItem.PresentChoiceGroup())
+			assert.Len(t, b.data.Find(Finders.ChildrenOf(parentItem)), tt.wantPresentsLen)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package basket
+
+import (
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+)
+
+// CheckoutReadiness готовность корзины к оформлению заказа
+type CheckoutReadiness struct {
+	// Можно ли оформить заказ: в корзине есть выбранные позиции и ни у одной из них нет блокирующих проблем
+	CanCheckout bool
+	// Позиции с блокирующими проблемами в порядке позиций корзины
+	BlockingItems []*CheckoutBlockingItem
+}
+
+// CheckoutBlockingItem позиция, из-за которой нельзя оформить заказ
+type CheckoutBlockingItem struct {
+	Item *basket_item.Item
+	// Блокирующие проблемы позиции по подсистемам, в которых они возникли
+	Problems map[basket_item.ProblemSubsystem][]*basket_item.Problem
+	// Комплектующие, из-за которых нельзя оформить конфигурацию, если позиция - конфигурация
+	ConfigurationItemIds []basket_item.ItemId
+}
+
+// CheckoutReadiness проверяет, можно ли оформить заказ из выбранных позиций корзины.
+//
+// Невыбранные позиции в заказ не попадают, поэтому их проблемы (скрытые для клиентов) оформление не блокируют. У
+// выбранных позиций учитываются все блокирующие проблемы, даже если проблема была скрыта, пока позиция не была выбрана.
+//
+// Проблемы комплектующих конфигурации решаются только удалением или разборкой самой конфигурации, поэтому они
+// относятся к конфигурации, а сами комплектующие попадают в ConfigurationItemIds вместе с комплектующими из
+// ConfigurationProblemAdditions проблем конфигурации
+func (b *BasketData) CheckoutReadiness() *CheckoutReadiness {
+	selectedItems := b.SelectedItems()
+	readiness := &CheckoutReadiness{}
+	blockingItems := make(map[basket_item.UniqId]*CheckoutBlockingItem)
+	for _, item := range selectedItems {
+		for _, problem := range item.Problems() {
+			if problem.Severity() != basket_item.ProblemSeverityBlocking {
+				continue
+			}
+
+			owner := item
+			if item.Type().IsPartOfConfiguration() {
+				if configurationItem := b.configurationOf(item); configurationItem != nil {
+					owner = configurationItem
+				}
+			}
+
+			blockingItem, ok := blockingItems[owner.UniqId()]
+			if !ok {
+				blockingItem = &CheckoutBlockingItem{
+					Item:     owner,
+					Problems: make(map[basket_item.ProblemSubsystem][]*basket_item.Problem),
+				}
+				blockingItems[owner.UniqId()] = blockingItem
+				readiness.BlockingItems = append(readiness.BlockingItems, blockingItem)
+			}
+
+			subsystem := problem.Subsystem()
+			blockingItem.Problems[subsystem] = append(blockingItem.Problems[subsystem], problem)
+
+			additions := problem.Additions().ConfigurationProblemAdditions
+			blockingItem.addConfigurationItemIds(additions.NotAvailableProductItemIds...)
+			blockingItem.addConfigurationItemIds(additions.IncompatibleProductItemIds...)
+			if owner != item {
+				blockingItem.addConfigurationItemIds(item.ItemId())
+			}
+		}
+	}
+
+	readiness.CanCheckout = len(selectedItems) > 0 && len(readiness.BlockingItems) == 0
+
+	return readiness
+}
+
+// addConfigurationItemIds добавляет комплектующие без повторов
+func (i *CheckoutBlockingItem) addConfigurationItemIds(itemIds ...basket_item.ItemId) {
+	for _, itemId := range itemIds {
+		found := false
+		for _, existItemId := range i.ConfigurationItemIds {
+			if existItemId == itemId {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			i.ConfigurationItemIds = append(i.ConfigurationItemIds, itemId)
+		}
+	}
+}
+
+// configurationOf находит конфигурацию, в состав которой входит позиция. Если позиция не является частью
+// конфигурации, то вернется nil
+func (b *BasketData) configurationOf(item *basket_item.Item) *basket_item.Item {
+	for item != nil && item.Type() != basket_item.TypeConfiguration {
+		if !item.IsChild() {
+			return nil
+		}
+
+		item = b.FindOneById(item.ParentUniqId())
+	}
+
+	return item
+}
@@ -0,0 +1,104 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"testing"
+)
+
+func TestBasketData_CheckoutReadiness(t *testing.T) {
+	newItem := func(itemId basket_item.ItemId, itemType basket_item.Type) *basket_item.Item {
+		return basket_item.NewItem(itemId, itemType, "name", "", 1, 100, 0, "msk_cl",
+			catalog_types.PriceColumnRetail)
+	}
+	newData := func(t *testing.T, items ...*basket_item.Item) *BasketData {
+		data := NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "msk")
+		for _, item := range items {
+			_, err := data.Add(item)
+			require.NoError(t, err)
+		}
+
+		return data
+	}
+
+	t.Run("empty basket", func(t *testing.T) {
+		assert.Equal(t, &CheckoutReadiness{}, newData(t).CheckoutReadiness())
+	})
+
+	t.Run("not blocking problems", func(t *testing.T) {
+		product := newItem("1", basket_item.TypeProduct)
+		product.AddProblem(basket_item.NewProblem(basket_item.ProblemUnknown, "unknown"))
+
+		assert.Equal(t, &CheckoutReadiness{CanCheckout: true}, newData(t, product).CheckoutReadiness())
+	})
+
+	t.Run("only warnings", func(t *testing.T) {
+		product := newItem("1", basket_item.TypeProduct)
+		product.AddProblem(basket_item.NewProblem(basket_item.ProblemPurchaseReasonIsNotSelected, "reason"))
+		service := newItem("2", basket_item.TypeSubcontractServiceForProduct)
+		require.NoError(t, product.AddChild(service))
+		service.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailableInSelectedCity, "city"))
+
+		assert.Equal(t, &CheckoutReadiness{CanCheckout: true}, newData(t, product, service).CheckoutReadiness())
+	})
+
+	t.Run("unselected item", func(t *testing.T) {
+		product := newItem("1", basket_item.TypeProduct)
+		unselected := newItem("2", basket_item.TypeProduct)
+		unselected.SetIsSelected(false)
+		unselected.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "not available"))
+
+		assert.Equal(t, &CheckoutReadiness{CanCheckout: true}, newData(t, product, unselected).CheckoutReadiness())
+	})
+
+	t.Run("blocking problems by subsystem", func(t *testing.T) {
+		product := newItem("1", basket_item.TypeProduct)
+		notAvailable := basket_item.NewProblem(basket_item.ProblemNotAvailable, "not available")
+		countExcess := basket_item.NewProblem(basket_item.ProblemMaxCountExcess, "count excess")
+		product.AddProblem(notAvailable, countExcess)
+		another := newItem("2", basket_item.TypeProduct)
+
+		assert.Equal(t, &CheckoutReadiness{
+			BlockingItems: []*CheckoutBlockingItem{
+				{
+					Item: product,
+					Problems: map[basket_item.ProblemSubsystem][]*basket_item.Problem{
+						basket_item.ProblemSubsystemAvailability: {notAvailable},
+						basket_item.ProblemSubsystemCount:        {countExcess},
+					},
+				},
+			},
+		}, newData(t, product, another).CheckoutReadiness())
+	})
+
+	t.Run("configuration", func(t *testing.T) {
+		configuration := newItem("conf", basket_item.TypeConfiguration)
+		notAvailableInConf := basket_item.NewProblem(
+			basket_item.ProblemProductItemInConfigurationNotAvailable,
+			"not available in configuration",
+		)
+		notAvailableInConf.Additions().ConfigurationProblemAdditions = basket_item.ConfigurationProblemAdditions{
+			NotAvailableProductItemIds: []basket_item.ItemId{"p1"},
+		}
+		configuration.AddProblem(notAvailableInConf)
+		part := newItem("p2", basket_item.TypeConfigurationProduct)
+		require.NoError(t, configuration.AddChild(part))
+		notAvailable := basket_item.NewProblem(basket_item.ProblemNotAvailable, "not available")
+		part.AddProblem(notAvailable)
+
+		assert.Equal(t, &CheckoutReadiness{
+			BlockingItems: []*CheckoutBlockingItem{
+				{
+					Item: configuration,
+					Problems: map[basket_item.ProblemSubsystem][]*basket_item.Problem{
+						basket_item.ProblemSubsystemConfiguration: {notAvailableInConf},
+						basket_item.ProblemSubsystemAvailability:  {notAvailable},
+					},
+					ConfigurationItemIds: []basket_item.ItemId{"p1", "p2"},
+				},
+			},
+		}, newData(t, configuration, part).CheckoutReadiness())
+	})
+}
@@ -2,16 +2,15 @@ package basket
 
 import (
 	"context"
-	"database/sql"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"go.citilink.cloud/catalog_types"
-	database "go.citilink.cloud/libdatabase"
 	"go.citilink.cloud/order/internal"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
-	"time"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
 )
 
 const MaxCountOfProductItemsInConf = 10
@@ -19,45 +18,248 @@ const MaxCountOfProductItemsInConf = 10
 func NewConfiguration(
 	basket *Basket,
 	productClient productv1.ProductAPIClient,
-	db database.DB,
+	registry ConfigurationRegistry,
+	confOptions *ConfigurationOptions,
 ) *Configuration {
-	return &Configuration{basket: basket, productClient: productClient, db: db}
+	if confOptions == nil {
+		confOptions = NewConfigurationOptions(nil, true)
+	}
+
+	return &Configuration{
+		basket:        basket,
+		productClient: productClient,
+		registry:      registry,
+		confOptions:   confOptions,
+	}
 }
 
 type Configuration struct {
 	basket        *Basket
 	productClient productv1.ProductAPIClient
-	db            database.DB
+	registry      ConfigurationRegistry
+	confOptions   *ConfigurationOptions
+}
+
+// ConfigurationOptions настройки проверки совместимости комплектующих конфигурации
+type ConfigurationOptions struct {
+	validator                           *ConfigurationValidator // валидатор совместимости комплектующих, может быть nil
+	isRegistryCompatibilityCheckEnabled bool                    // учитывать ли проверку совместимости в реестре
+}
+
+// NewConfigurationOptions создает настройки конфигурации. Если валидатор не задан, то совместимость всегда проверяется
+// в реестре конфигураций, иначе проверка в реестре учитывается только при isRegistryCompatibilityCheckEnabled
+func NewConfigurationOptions(
+	validator *ConfigurationValidator,
+	isRegistryCompatibilityCheckEnabled bool,
+) *ConfigurationOptions {
+	return &ConfigurationOptions{
+		validator:                           validator,
+		isRegistryCompatibilityCheckEnabled: isRegistryCompatibilityCheckEnabled,
+	}
+}
+
+// NewValidatedConfigurationOptions создает настройки конфигурации со стандартными правилами совместимости для
+// указанных категорий, свойства комплектующих берутся из источника propertiesProvider
+func NewValidatedConfigurationOptions(
+	propertiesProvider ConfigurationPropertiesProvider,
+	categories ConfigurationCategories,
+	isRegistryCompatibilityCheckEnabled bool,
+) *ConfigurationOptions {
+	return NewConfigurationOptions(
+		NewConfigurationValidator(propertiesProvider, DefaultConfigurationRules(categories)...),
+		isRegistryCompatibilityCheckEnabled,
+	)
+}
+
+// All возвращает все конфигурации (сборки) в корзине
+func (c *Configuration) All() basket_item.Items {
+	return c.basket.data.FindByType(basket_item.TypeConfiguration)
+}
+
+// FindOne находит конфигурацию по идентификатору позиции
+func (c *Configuration) FindOne(uniqId basket_item.UniqId) (*basket_item.Item, error) {
+	configurationItem := c.basket.FindOneById(uniqId)
+	if configurationItem == nil || configurationItem.Type() != basket_item.TypeConfiguration {
+		return nil, internal.NewNotFoundError(fmt.Errorf("configuration '%s' not found in basket", uniqId))
+	}
+
+	return configurationItem, nil
 }
 
+// FindByConfId находит конфигурацию по идентификатору конфигурации в конфигураторе
+func (c *Configuration) FindByConfId(confId basket_item.ConfId) *basket_item.Item {
+	for _, conf := range c.All() {
+		if conf.Additions().GetConfiguration() == nil {
+			continue
+		}
+
+		if basket_item.ConfId(conf.Additions().GetConfiguration().GetConfId()) == confId {
+			return conf
+		}
+	}
+
+	return nil
+}
+
+// configurationOf находит конфигурацию, в состав которой входит позиция. Если позиция не является частью
+// конфигурации, то вернется nil
+func (c *Configuration) configurationOf(item *basket_item.Item) *basket_item.Item {
+	return c.basket.data.configurationOf(item)
+}
+
+// configurationContent состав конфигурации
+type configurationContent struct {
+	// комплектующие, отсортированные по идентификатору товара
+	products basket_item.Items
+	// услуги комплектующих, ключ - UniqId комплектующей
+	productServices map[basket_item.UniqId]basket_item.Items
+	// услуга сборки, может отсутствовать
+	assemblyService *basket_item.Item
+}
+
+// contentOf возвращает состав конфигурации
+func (c *Configuration) contentOf(configurationItem *basket_item.Item) *configurationContent {
+	content := &configurationContent{productServices: make(map[basket_item.UniqId]basket_item.Items)}
+	for _, child := range c.basket.data.ChildrenOf(configurationItem) {
+		switch child.Type() {
+		case basket_item.TypeConfigurationAssemblyService:
+			content.assemblyService = child
+		case basket_item.TypeConfigurationProduct:
+			content.products = append(content.products, child)
+			for _, service := range c.basket.data.ChildrenOf(child) {
+				if service.Type() == basket_item.TypeConfigurationProductService {
+					content.productServices[child.UniqId()] = append(content.productServices[child.UniqId()], service)
+				}
+			}
+		}
+	}
+
+	sort.Slice(content.products, func(i, j int) bool {
+		return content.products[i].ItemId() < content.products[j].ItemId()
+	})
+
+	return content
+}
+
+// availableProductIds возвращает товары, которые есть в каталоге в регионе корзины
+func (c *Configuration) availableProductIds(
+	ctx context.Context,
+	productIds []catalog_types.ProductId,
+) (map[catalog_types.ProductId]bool, error) {
+	ids := make([]string, 0, len(productIds))
+	for _, productId := range productIds {
+		ids = append(ids, string(productId))
+	}
+
+	// сценарии внедрения сбоев применяются и к комплектующим конфигурации
+	findFullCtx := withFaultScenarios(ctx, c.basket.data.faultScenarios)
+	findFullResponse, err := c.productClient.FindFull(findFullCtx, &productv1.FindFullRequest{
+		Ids:     ids,
+		SpaceId: string(c.basket.SpaceId()),
+	})
+	if err != nil {
+		return nil, internal.NewCatalogError(
+			fmt.Errorf("can't get products from catalog: %w", err),
+			c.basket.SpaceId(),
+		)
+	}
+
+	// Чтобы поиск был O(1)
+	available := make(map[catalog_types.ProductId]bool, len(findFullResponse.GetInfos()))
+	for _, info := range findFullResponse.GetInfos() {
+		available[catalog_types.ProductId(info.GetId())] = true
+	}
+
+	return available, nil
+}
+
+// Add добавляет конфигурацию в корзину. В корзине может находиться несколько конфигураций, но если в корзине уже
+// есть конфигурация с тем же идентификатором, то она будет заменена новой
 func (c *Configuration) Add(
 	ctx context.Context,
 	confId basket_item.ConfId,
 	confType basket_item.ConfType,
 	assemblyServiceItemId string,
 	confItems []*basket_item.ConfItem,
+	idempotencyKey IdempotencyKey,
 ) ([]*basket_item.Item, error) {
+	operation, err := c.basket.data.findAppliedOperation(idempotencyKey, OperationTypeConfigurationAdd)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation != nil {
+		return c.basket.data.appliedItems(operation)
+	}
+
 	items, err := c.assembleConfigurationItems(ctx, confId, confType, assemblyServiceItemId, confItems)
 	if err != nil {
 		return nil, fmt.Errorf("can't assemble item's configuration: %w", err)
 	}
 
-	configurations := c.basket.Find(Finders.ByType(basket_item.TypeConfiguration))
-	for _, conf := range configurations {
-		err := c.basket.Remove(conf, false)
+	// конфигурация из конфигуратора добавляется, даже если комплектующие несовместимы, а проблемы совместимости
+	// добавляются к самой конфигурации, как и при обновлении корзины. Проверка выполняется до изменения корзины, чтобы
+	// ошибка валидатора не оставила в корзине конфигурацию, добавление которой не записано как выполненная операция
+	if c.validator() != nil {
+		problems, err := c.validator().Validate(ctx, c.basket.SpaceId(), items)
 		if err != nil {
-			return nil, fmt.Errorf("can't remove item's configuration: %w", err)
+			return nil, fmt.Errorf("can't validate configuration: %w", err)
+		}
+
+		for _, item := range items {
+			if item.Type() == basket_item.TypeConfiguration {
+				item.AddProblem(problems...)
+			}
+		}
+	}
+
+	err = c.addAssembled(confId, items)
+	if err != nil {
+		return nil, err
+	}
+
+	c.basket.data.recordAppliedOperation(idempotencyKey, OperationTypeConfigurationAdd, items...)
+
+	return items, nil
+}
+
+// addAssembled добавляет собранные позиции конфигурации в корзину, заменяя конфигурацию с тем же идентификатором
+func (c *Configuration) addAssembled(confId basket_item.ConfId, items []*basket_item.Item) error {
+	existConfiguration := c.FindByConfId(confId)
+	if existConfiguration != nil {
+		err := c.basket.remove(existConfiguration, false)
+		if err != nil {
+			return fmt.Errorf("can't remove item's configuration: %w", err)
 		}
 	}
 
 	for _, item := range items {
 		_, err := c.basket.data.Add(item)
 		if err != nil {
-			return nil, fmt.Errorf("can't add item's configuration: %w", err)
+			return fmt.Errorf("can't add item's configuration: %w", err)
 		}
 	}
 
-	return items, nil
+	return nil
+}
+
+// checkCompatibility проверяет совместимость собранных позиций конфигурации правилами валидатора. Если комплектующие
+// несовместимы, то возвращается IncompatibleConfigurationError
+func (c *Configuration) checkCompatibility(ctx context.Context, items []*basket_item.Item) error {
+	if c.validator() == nil {
+		return nil
+	}
+
+	problems, err := c.validator().Validate(ctx, c.basket.SpaceId(), items)
+	if err != nil {
+		return fmt.Errorf("can't validate configuration: %w", err)
+	}
+
+	if len(problems) > 0 {
+		return NewIncompatibleConfigurationError(problems)
+	}
+
+	return nil
 }
 
 func (c *Configuration) assembleConfigurationItems(
@@ -87,7 +289,9 @@ func (c *Configuration) assembleConfigurationItems(
 		productIds = append(productIds, string(confItem.ProductId))
 	}
 
-	findFullResponse, err := c.productClient.FindFull(ctx, &productv1.FindFullRequest{
+	// сценарии внедрения сбоев применяются и к комплектующим конфигурации
+	findFullCtx := withFaultScenarios(ctx, c.basket.data.faultScenarios)
+	findFullResponse, err := c.productClient.FindFull(findFullCtx, &productv1.FindFullRequest{
 		Ids:     productIds,
 		SpaceId: string(c.basket.SpaceId()),
 	})
@@ -212,44 +416,31 @@ func (c *Configuration) assembleConfigurationItems(
 
 // MoveItemFrom перемещает позицию из конфигурации. Особенность данной операции заключается в том, что в
 // случае если передвигается позиция типа "товар", то аналог этой позиции добавляется в корзину. Услуги и прочие типы
-// позиций просто удаляются
+// позиций просто удаляются. Конфигурация, из которой перемещается позиция, определяется по самой позиции
 func (c *Configuration) MoveItemFrom(ctx context.Context, uniqId basket_item.UniqId) error {
-	configurations := c.basket.Find(Finders.ByType(basket_item.TypeConfiguration))
-	for _, conf := range configurations {
-		if !conf.Additions().GetConfiguration().IsMutable() {
-			return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item out"),
-				"Из шаблонной конфигурации нельзя удалять комплектующие.")
-		}
-	}
-
-	var configurationItem *basket_item.Item
-	for _, item := range c.basket.All() {
-		if item.Type() == basket_item.TypeConfiguration {
-			configurationItem = item
-		}
+	itemToMove := c.basket.FindOneById(uniqId)
+	if itemToMove == nil || !itemToMove.Type().IsPartOfConfiguration() {
+		return fmt.Errorf("item %s not found", uniqId)
 	}
 
+	configurationItem := c.configurationOf(itemToMove)
 	if configurationItem == nil {
 		return fmt.Errorf("no configuration in basket")
 	}
 
-	var itemToMove *basket_item.Item
-	for _, item := range c.basket.All() {
-		if item.Type().IsPartOfConfiguration() && item.UniqId() == uniqId {
-			itemToMove = item
-		}
-	}
-
-	if itemToMove == nil {
-		return fmt.Errorf("item %s not found", uniqId)
+	if !configurationItem.Additions().GetConfiguration().IsMutable() {
+		return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item out"),
+			"Из шаблонной конфигурации нельзя удалять комплектующие.")
 	}
 
 	if !itemToMove.IsMovableFromConfiguration() {
 		return fmt.Errorf("can't move not movable item from configuration")
 	}
 
+	defer c.basket.data.withAudit("", AuditReasonMoveFromConfiguration)()
+
 	if itemToMove.Type().IsProduct() {
-		_, err := c.basket.Add(ctx, itemToMove.ItemId(), basket_item.TypeProduct, "", itemToMove.Count(), false)
+		_, err := c.basket.Add(ctx, itemToMove.ItemId(), basket_item.TypeProduct, "", itemToMove.Count(), false, "")
 		if err != nil {
 			return fmt.Errorf("can't move product from configuration to basket: %w", err)
 		}
@@ -260,35 +451,22 @@ func (c *Configuration) MoveItemFrom(ctx context.Context, uniqId basket_item.Uni
 	return nil
 }
 
-// MoveItemIn перемещает позицию в конфигурацию. (на данный момент возможно перемещение только товаров)
-func (c *Configuration) MoveItemIn(uniqId basket_item.UniqId) error {
-	configurations := c.basket.Find(Finders.ByType(basket_item.TypeConfiguration))
-	for _, conf := range configurations {
-		if !conf.Additions().GetConfiguration().IsMutable() {
-			return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item in"),
-				"В шаблонную конфигурации нельзя добавлять комплектующие.")
-		}
-	}
-
-	var configurationItem *basket_item.Item
-	for _, item := range c.basket.All() {
-		if item.Type() == basket_item.TypeConfiguration {
-			configurationItem = item
-			break
-		}
-	}
-
-	if configurationItem == nil {
-		return fmt.Errorf("no configuration in basket")
+// MoveItemIn перемещает позицию в указанную конфигурацию. (на данный момент возможно перемещение только товаров)
+//
+// В конфигурации может быть не больше MaxCountOfProductItemsInConf шт. одного товара, поэтому переносится только
+// допустимое кол-во, а остаток остается в корзине отдельной позицией с информационным сообщением InfoIdPositionSplit
+func (c *Configuration) MoveItemIn(confUniqId basket_item.UniqId, uniqId basket_item.UniqId) error {
+	configurationItem, err := c.FindOne(confUniqId)
+	if err != nil {
+		return err
 	}
 
-	var itemToMove *basket_item.Item
-	for _, item := range c.basket.All() {
-		if item.Type() == basket_item.TypeProduct && item.UniqId() == uniqId {
-			itemToMove = item
-		}
+	if !configurationItem.Additions().GetConfiguration().IsMutable() {
+		return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item in"),
+			"В шаблонную конфигурации нельзя добавлять комплектующие.")
 	}
 
+	itemToMove := c.basket.FindOneById(uniqId)
 	if itemToMove == nil {
 		return fmt.Errorf("item %s not found", uniqId)
 	}
@@ -301,7 +479,7 @@ func (c *Configuration) MoveItemIn(uniqId basket_item.UniqId) error {
 		return fmt.Errorf("can't move not movable item to configuration")
 	}
 
-	children := c.basket.Find(Finders.ChildrenOf(configurationItem))
+	children := c.basket.data.ChildrenOf(configurationItem)
 	var foundedChild *basket_item.Item
 	for _, child := range children {
 		if child.Type() == basket_item.TypeConfigurationProduct && child.ItemId() == itemToMove.ItemId() {
@@ -310,14 +488,28 @@ func (c *Configuration) MoveItemIn(uniqId basket_item.UniqId) error {
 		}
 	}
 
-	var newItem *basket_item.Item
+	countInConf := 0
+	if foundedChild != nil {
+		countInConf = foundedChild.Count()
+	}
+
+	movedCount := c.movableInItemCount(countInConf, itemToMove.Count())
+	if movedCount == 0 {
+		return internal.NewLogicErrorWithMsg(
+			fmt.Errorf("configuration already has max count of item %s", itemToMove.ItemId()),
+			fmt.Sprintf("В конфигурации не может быть больше %d шт. одного товара.", MaxCountOfProductItemsInConf),
+		)
+	}
+
+	defer c.basket.data.withAudit("", AuditReasonMoveToConfiguration)()
+
 	if foundedChild == nil {
-		newItem = basket_item.NewItem(
+		newItem := basket_item.NewItem(
 			itemToMove.ItemId(),
 			basket_item.TypeConfigurationProduct,
 			itemToMove.Name(),
 			itemToMove.Image(),
-			c.fixMoveInItemCount(itemToMove.Count()),
+			movedCount,
 			itemToMove.Price(),
 			itemToMove.Bonus(),
 			c.basket.SpaceId(),
@@ -335,34 +527,66 @@ func (c *Configuration) MoveItemIn(uniqId basket_item.UniqId) error {
 			return err
 		}
 	} else {
-		foundedChild.FixCount(c.fixMoveInItemCount(foundedChild.Count() + itemToMove.Count()))
+		foundedChild.FixCount(countInConf + movedCount)
+		c.basket.data.auditCountChange(foundedChild, countInConf)
 	}
 
-	c.basket.data.Remove(itemToMove)
+	remainder := itemToMove.Count() - movedCount
+	if remainder == 0 {
+		c.basket.data.Remove(itemToMove)
+
+		return nil
+	}
+
+	// все, что не поместилось в конфигурацию, остается в корзине отдельной позицией
+	countBefore := itemToMove.Count()
+	itemToMove.FixCount(remainder)
+	c.basket.data.auditCountChange(itemToMove, countBefore)
+	itemToMove.AddInfo(basket_item.NewLocalizedInfo(
+		basket_item.InfoIdPositionSplit,
+		basket_item.MessagePositionSplit,
+		basket_item.NewIntMessageParam("moved", movedCount),
+		basket_item.NewIntMessageParam("remaining", remainder),
+		basket_item.NewIntMessageParam("max", MaxCountOfProductItemsInConf),
+	))
 
 	return nil
 }
 
-func (c *Configuration) Disassemble() error {
-	configurations := c.basket.Find(Finders.ByType(basket_item.TypeConfiguration))
-	var configurationItem *basket_item.Item
-	for _, conf := range configurations {
-		if !conf.Additions().GetConfiguration().IsMutable() {
-			return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't disassemble"),
-				"Шаблонную конфигурацию нельзя разобрать.")
-		}
-		configurationItem = conf
+// Disassemble разбирает указанную конфигурацию на отдельные товары. Остальные конфигурации корзины не затрагиваются.
+//
+// Услуги комплектующих переносятся к товарам как самостоятельные услуги, если каталог предлагает такую же услугу для
+// товара вне конфигурации. Услуги, у которых нет самостоятельного аналога, и услуга сборки удаляются, о каждой из них
+// пользователь получает информационное сообщение
+func (c *Configuration) Disassemble(ctx context.Context, confUniqId basket_item.UniqId) error {
+	configurationItem, err := c.FindOne(confUniqId)
+	if err != nil {
+		return err
 	}
 
-	if configurationItem == nil {
-		return fmt.Errorf("no configuration in basket")
+	if !configurationItem.Additions().GetConfiguration().IsMutable() {
+		return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't disassemble"),
+			"Шаблонную конфигурацию нельзя разобрать.")
 	}
 
-	for _, item := range c.basket.Find(Finders.ChildrenOfRecursive(configurationItem)) {
-		if !item.Type().IsProduct() {
+	content := c.contentOf(configurationItem)
+	defer c.basket.data.withAudit("", AuditReasonMoveFromConfiguration)()
+
+	// услуги каталога запрашиваются до изменения корзины, чтобы ошибка каталога не оставила конфигурацию разобранной
+	// наполовину
+	offers := make(map[basket_item.UniqId]map[basket_item.ItemId]*standaloneServiceOffer, len(content.productServices))
+	for _, item := range content.products {
+		if len(content.productServices[item.UniqId()]) == 0 {
 			continue
 		}
 
+		offers[item.UniqId()], err = c.standaloneServiceOffers(ctx, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range content.products {
 		newProductItem := basket_item.NewItem(
 			item.ItemId(),
 			basket_item.TypeProduct,
@@ -376,10 +600,38 @@ func (c *Configuration) Disassemble() error {
 		newProductItem.SetCountMultiplicity(item.CountMultiplicity())
 		newProductItem.SetPrepaymentMandatory(item.IsPrepaymentMandatory())
 		newProductItem.Additions().SetProduct(item.Additions().GetProduct())
-		_, err := c.basket.data.Add(newProductItem)
+		// если такой товар уже есть в корзине отдельной позицией, то кол-во добавляется к ней, и услуги переносятся к
+		// этой позиции
+		productItem, err := c.basket.data.Add(newProductItem)
 		if err != nil {
 			return err
 		}
+
+		for _, service := range content.productServices[item.UniqId()] {
+			offer, ok := offers[item.UniqId()][service.ItemId()]
+			if !ok || !productItem.Spec().CanHaveChild(offer.itemType) {
+				c.basket.AddInfo(NewInfo(service, removedItemInfo(
+					service,
+					basket_item.MessageServiceRemovedOnDisassemble,
+					basket_item.NewStringMessageParam("service", service.Name()),
+					basket_item.NewStringMessageParam("product", item.Name()),
+				)))
+				continue
+			}
+
+			err := c.addStandaloneService(productItem, service, offer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if content.assemblyService != nil {
+		c.basket.AddInfo(NewInfo(content.assemblyService, removedItemInfo(
+			content.assemblyService,
+			basket_item.MessageAssemblyServiceRemovedOnDisassemble,
+			basket_item.NewStringMessageParam("service", content.assemblyService.Name()),
+		)))
 	}
 
 	c.basket.data.Remove(configurationItem)
@@ -387,71 +639,177 @@ func (c *Configuration) Disassemble() error {
 	return nil
 }
 
-func (c *Configuration) Assemble(
+// standaloneServiceOffer самостоятельная услуга, которую каталог предлагает для товара вне конфигурации
+type standaloneServiceOffer struct {
+	itemType basket_item.Type
+	// цена услуги в колонке цен корзины, 0 - если каталог цену не вернул
+	price int
+}
+
+// standaloneServiceOffers возвращает самостоятельные услуги, которые каталог предлагает для товара
+func (c *Configuration) standaloneServiceOffers(
 	ctx context.Context,
-	assemblyServiceItemId string,
-	confItems []*basket_item.ConfItem,
-	count int,
-) error {
-	var configurationItem *basket_item.Item
-	for _, item := range c.basket.All() {
-		if item.Type() == basket_item.TypeConfiguration {
-			configurationItem = item
-			break
+	productItem *basket_item.Item,
+) (map[basket_item.ItemId]*standaloneServiceOffer, error) {
+	offers := make(map[basket_item.ItemId]*standaloneServiceOffer)
+	response, err := c.productClient.FindServices(ctx, &productv1.FindServicesRequest{
+		ProductId: string(productItem.ItemId()),
+		SpaceId:   string(c.basket.SpaceId()),
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.NotFound {
+			return offers, nil
 		}
+
+		return nil, internal.NewCatalogError(
+			fmt.Errorf("can't get services of product from catalog microservice: %w", err),
+			c.basket.SpaceId(),
+		)
 	}
 
-	if configurationItem != nil {
-		return fmt.Errorf("configuration already exists in basket")
+	priceColumn := int32(c.basket.PriceColumn())
+	for serviceId, service := range response.GetSubcontractServices() {
+		offers[basket_item.ItemId(serviceId)] = &standaloneServiceOffer{
+			itemType: basket_item.TypeSubcontractServiceForProduct,
+			price:    int(service.GetPrices()[priceColumn].GetPrice()),
+		}
 	}
 
-	itemListStr, err := xml.Marshal(c.basket.All().ToItemList())
-	if err != nil {
-		return fmt.Errorf("can't marshal itemList: %w", err)
+	for serviceId, service := range response.GetInsuranceServices() {
+		if avail, ok := service.GetAvailability()[priceColumn]; !ok || !avail {
+			continue
+		}
+
+		offers[basket_item.ItemId(serviceId)] = &standaloneServiceOffer{
+			itemType: basket_item.TypeInsuranceServiceForProduct,
+			price:    int(service.GetPrices()[priceColumn].GetPrice()),
+		}
+	}
+
+	return offers, nil
+}
+
+// addStandaloneService добавляет к товару разобранной конфигурации самостоятельную услугу вместо услуги комплектующей
+func (c *Configuration) addStandaloneService(
+	productItem *basket_item.Item,
+	service *basket_item.Item,
+	offer *standaloneServiceOffer,
+) error {
+	price := offer.price
+	if price == 0 {
+		price = service.Price()
 	}
 
-	dbCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-	row := c.db.QueryRowxContext(dbCtx, "Configurator.set_temporary_configuration",
-		sql.Named("conf_id", basket_item.DefaultConfId),
-		sql.Named("item_list", string(itemListStr)),
+	standaloneService := basket_item.NewItem(
+		service.ItemId(),
+		offer.itemType,
+		service.Name(),
+		service.Image(),
+		productItem.Count(),
+		price,
+		0,
+		c.basket.SpaceId(),
+		c.basket.PriceColumn(),
 	)
-	if row.Err() != nil {
-		return fmt.Errorf("error on query from db: %w", row.Err())
+	if offer.itemType == basket_item.TypeSubcontractServiceForProduct {
+		standaloneService.Additions().SetSubcontractServiceForProduct(&basket_item.SubcontractItemAdditions{})
+	}
+
+	err := productItem.AddChild(standaloneService)
+	if err != nil {
+		return fmt.Errorf("can't make item '%s' child of item '%s'", standaloneService.Type(), productItem.Type())
 	}
 
-	rawData := struct {
-		ConfId         sql.NullString `db:"conf_id"`
-		AssemblyTypeId sql.NullInt64  `db:"assembly_type_id"`
-		Compatible     sql.NullBool   `db:"compatible"`
-	}{}
-	err = row.StructScan(&rawData)
+	_, err = c.basket.data.Add(standaloneService)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// removedItemInfo информационное сообщение об удаленной позиции
+func removedItemInfo(
+	item *basket_item.Item,
+	key basket_item.MessageKey,
+	params ...*basket_item.MessageParam,
+) *basket_item.Info {
+	info := basket_item.NewLocalizedInfo(basket_item.InfoIdPositionRemoved, key, params...)
+	info.SetAdditions(&basket_item.InfoAdditions{
+		ChangedItem: basket_item.ChangedItemInfoAdditions{
+			ItemId: string(item.ItemId()),
+			UniqId: string(item.UniqId()),
+			Count:  item.Count(),
+			Name:   item.Name(),
+			Price:  item.Price(),
+		},
+	})
+
+	return info
+}
+
+// Assemble собирает новую конфигурацию из товаров корзины. Уже собранные конфигурации остаются в корзине как есть и
+// в конфигуратор не передаются. Возвращается собранная конфигурация, либо nil, если конфигуратор ее не собрал
+func (c *Configuration) Assemble(
+	ctx context.Context,
+	assemblyServiceItemId string,
+	confItems []*basket_item.ConfItem,
+	count int,
+	idempotencyKey IdempotencyKey,
+) (*basket_item.Item, error) {
+	operation, err := c.basket.data.findAppliedOperation(idempotencyKey, OperationTypeConfigurationAssemble)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil
+		return nil, err
+	}
+
+	if operation != nil {
+		return c.basket.data.appliedItem(operation)
+	}
+
+	itemsOutOfConfigurations := make(basket_item.Items, 0)
+	for _, item := range c.basket.All() {
+		if item.Type().IsConfiguration() || item.Type().IsPartOfConfiguration() {
+			continue
 		}
-		return fmt.Errorf("error on scanned row: %w", err)
+
+		itemsOutOfConfigurations = append(itemsOutOfConfigurations, item)
+	}
+
+	temporaryConfiguration, err := c.registry.RegisterTemporary(ctx, itemsOutOfConfigurations)
+	if err != nil {
+		return nil, fmt.Errorf("can't register temporary configuration: %w", err)
+	}
+
+	if temporaryConfiguration == nil {
+		return nil, nil
 	}
 
-	if !rawData.Compatible.Bool {
-		return fmt.Errorf("invalid configuration")
+	if !temporaryConfiguration.IsCompatible && c.isRegistryCompatibilityCheckRequired() {
+		return nil, fmt.Errorf("invalid configuration")
 	}
 
-	confId := basket_item.ConfId(rawData.ConfId.String)
+	confId := temporaryConfiguration.ConfId
 	items, err := c.assembleConfigurationItems(ctx, confId, basket_item.ConfTypeUser, assemblyServiceItemId, confItems)
 	if err != nil {
-		return fmt.Errorf("error on assembling configuration: %w", err)
+		return nil, fmt.Errorf("error on assembling configuration: %w", err)
 	}
 
+	var configurationItem *basket_item.Item
 	for _, item := range items {
 		if item.Type() == basket_item.TypeConfiguration {
+			configurationItem = item
 			err := item.SetCount(count)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
+	err = c.checkCompatibility(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
 	changeableItems := make([]*basket_item.Item, 0, len(confItems))
 	for _, confItem := range confItems {
 		for _, item := range c.basket.All() {
@@ -468,10 +826,12 @@ func (c *Configuration) Assemble(
 			}
 
 			if changeableItem.Count() > item.Count()*count {
+				countBefore := changeableItem.Count()
 				err := changeableItem.SetCount(changeableItem.Count() - item.Count()*count)
 				if err != nil {
-					return err
+					return nil, err
 				}
+				c.basket.data.auditCountChange(changeableItem, countBefore)
 			} else {
 				c.basket.data.Remove(changeableItem)
 			}
@@ -481,16 +841,98 @@ func (c *Configuration) Assemble(
 	for _, item := range items {
 		_, err := c.basket.data.Add(item)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	c.basket.data.recordAppliedOperation(idempotencyKey, OperationTypeConfigurationAssemble, configurationItem)
+
+	return configurationItem, nil
+}
+
+// register регистрирует новую сборку в реестре конфигураций и возвращает выданный реестром идентификатор
+// конфигурации. Каждая регистрация получает свой идентификатор, поэтому сборка не заменит другие конфигурации корзины
+func (c *Configuration) register(ctx context.Context, registeredItems basket_item.Items) (basket_item.ConfId, error) {
+	temporaryConfiguration, err := c.registry.RegisterTemporary(ctx, registeredItems)
+	if err != nil {
+		return "", fmt.Errorf("can't register temporary configuration: %w", err)
+	}
+
+	if temporaryConfiguration == nil {
+		return "", fmt.Errorf("configuration is not registered")
+	}
+
+	if !temporaryConfiguration.IsCompatible && c.isRegistryCompatibilityCheckRequired() {
+		return "", fmt.Errorf("invalid configuration")
+	}
+
+	return temporaryConfiguration.ConfId, nil
+}
+
+// registeredComponent позиция комплектующей для регистрации в реестре конфигураций. В реестре регистрируется сборка
+// целиком, поэтому кол-во комплектующей умножается на кол-во сборок
+func (c *Configuration) registeredComponent(
+	productId catalog_types.ProductId,
+	count int,
+	price int,
+	confCount int,
+) *basket_item.Item {
+	return basket_item.NewItem(
+		basket_item.ItemId(productId),
+		basket_item.TypeProduct,
+		"",
+		"",
+		count*confCount,
+		price,
+		0,
+		c.basket.SpaceId(),
+		c.basket.PriceColumn(),
+	)
+}
+
+// Validate проверяет совместимость комплектующих конфигурации правилами валидатора. Если валидатор не задан, то
+// проблем нет
+func (c *Configuration) Validate(ctx context.Context, confUniqId basket_item.UniqId) ([]*basket_item.Problem, error) {
+	configurationItem, err := c.FindOne(confUniqId)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.validator() == nil {
+		return nil, nil
+	}
+
+	return c.validator().Validate(
+		ctx,
+		c.basket.SpaceId(),
+		c.basket.data.ChildrenOfRecursive(configurationItem),
+	)
 }
 
-func (c *Configuration) fixMoveInItemCount(count int) int {
-	if count > MaxCountOfProductItemsInConf {
-		return MaxCountOfProductItemsInConf
+// isRegistryCompatibilityCheckRequired проверяет, нужно ли учитывать результат проверки совместимости в реестре
+// конфигураций. Без валидатора проверка в реестре единственная, поэтому учитывается всегда
+func (c *Configuration) isRegistryCompatibilityCheckRequired() bool {
+	return c.validator() == nil || c.confOptions.isRegistryCompatibilityCheckEnabled
+}
+
+func (c *Configuration) validator() *ConfigurationValidator {
+	if c.confOptions == nil {
+		return nil
+	}
+
+	return c.confOptions.validator
+}
+
+// movableInItemCount возвращает кол-во товара, которое можно перенести в конфигурацию, если в ней уже есть countInConf
+// шт. этого товара
+func (c *Configuration) movableInItemCount(countInConf int, count int) int {
+	allowed := MaxCountOfProductItemsInConf - countInConf
+	if allowed < 0 {
+		allowed = 0
+	}
+
+	if count > allowed {
+		return allowed
 	}
 
 	return count
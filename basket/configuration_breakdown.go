@@ -0,0 +1,118 @@
+package basket
+
+import (
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"sort"
+)
+
+// ConfigurationBreakdown разбивка стоимости и бонусов конфигурации по ее составу. Стоимость конфигурации считается
+// только по ее составу: комплектующим, услугам комплектующих и услуге сборки
+type ConfigurationBreakdown struct {
+	Configuration *basket_item.Item
+	// Комплектующие, отсортированные по идентификатору товара
+	Components      []*ConfigurationBreakdownComponent
+	AssemblyService *ConfigurationBreakdownLine
+	// Цена одной сборки
+	Price int
+	// Бонусы за одну сборку
+	Bonus int
+	// Кол-во сборок, множитель цены и бонусов одной сборки
+	Count int
+}
+
+// Cost стоимость всех сборок
+func (b *ConfigurationBreakdown) Cost() int {
+	return b.Price * b.Count
+}
+
+// TotalBonus бонусы за все сборки
+func (b *ConfigurationBreakdown) TotalBonus() int {
+	return b.Bonus * b.Count
+}
+
+// ConfigurationBreakdownComponent комплектующая конфигурации вместе со своими услугами
+type ConfigurationBreakdownComponent struct {
+	*ConfigurationBreakdownLine
+	Services []*ConfigurationBreakdownLine
+}
+
+// ConfigurationBreakdownLine позиция конфигурации. Цена, кол-во и бонусы указаны в расчете на одну сборку
+type ConfigurationBreakdownLine struct {
+	Item  *basket_item.Item
+	Price int
+	Count int
+	// Цена позиции, умноженная на кол-во
+	Cost int
+	// Бонусы за позицию, умноженные на кол-во
+	Bonus int
+}
+
+func newConfigurationBreakdownLine(item *basket_item.Item) *ConfigurationBreakdownLine {
+	return &ConfigurationBreakdownLine{
+		Item:  item,
+		Price: item.Price(),
+		Count: item.Count(),
+		Cost:  item.Cost(),
+		Bonus: item.Bonus() * item.Count(),
+	}
+}
+
+// calculateConfiguration считает разбивку конфигурации по ее дочерним позициям. В цену и бонусы конфигурации входят
+// только позиции, которые являются частью конфигурации
+func calculateConfiguration(
+	configurationItem *basket_item.Item,
+	children basket_item.Items,
+) *ConfigurationBreakdown {
+	breakdown := &ConfigurationBreakdown{
+		Configuration: configurationItem,
+		Count:         configurationItem.Count(),
+	}
+
+	components := make(map[basket_item.UniqId]*ConfigurationBreakdownComponent)
+	var services basket_item.Items
+	for _, item := range children {
+		if !item.Type().IsPartOfConfiguration() {
+			continue
+		}
+
+		line := newConfigurationBreakdownLine(item)
+		breakdown.Price += line.Cost
+		breakdown.Bonus += line.Bonus
+
+		switch item.Type() {
+		case basket_item.TypeConfigurationProduct:
+			component := &ConfigurationBreakdownComponent{ConfigurationBreakdownLine: line}
+			components[item.UniqId()] = component
+			breakdown.Components = append(breakdown.Components, component)
+		case basket_item.TypeConfigurationAssemblyService:
+			breakdown.AssemblyService = line
+		default:
+			services = append(services, item)
+		}
+	}
+
+	for _, service := range services {
+		component, ok := components[service.ParentUniqId()]
+		if !ok {
+			continue
+		}
+
+		component.Services = append(component.Services, newConfigurationBreakdownLine(service))
+	}
+
+	sort.Slice(breakdown.Components, func(i, j int) bool {
+		return breakdown.Components[i].Item.ItemId() < breakdown.Components[j].Item.ItemId()
+	})
+
+	return breakdown
+}
+
+// Breakdown возвращает разбивку стоимости и бонусов указанной конфигурации по ее составу
+func (c *Configuration) Breakdown(confUniqId basket_item.UniqId) (*ConfigurationBreakdown, error) {
+	configurationItem, err := c.FindOne(confUniqId)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateConfiguration(configurationItem, c.basket.data.ChildrenOfRecursive(configurationItem)), nil
+}
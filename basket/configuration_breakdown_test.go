@@ -0,0 +1,96 @@
+package basket
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"testing"
+)
+
+func TestConfiguration_Breakdown(t *testing.T) {
+	bsk, configurationItem := newTestConfigurationBasket(t)
+	for _, item := range bsk.All() {
+		switch item.ItemId() {
+		case "100":
+			item.SetBonus(10)
+		case "200":
+			item.FixCount(2)
+			item.SetBonus(5)
+		case "400":
+			item.SetPrice(1000)
+		}
+	}
+
+	breakdown, err := bsk.Configuration().Breakdown(configurationItem.UniqId())
+	assert.NoError(t, err)
+
+	assert.Equal(t, configurationItem, breakdown.Configuration)
+	assert.Equal(t, 2, breakdown.Count)
+	// 500 + 300*2 + 50 + 1000
+	assert.Equal(t, 2150, breakdown.Price)
+	assert.Equal(t, 4300, breakdown.Cost())
+	// 10 + 5*2
+	assert.Equal(t, 20, breakdown.Bonus)
+	assert.Equal(t, 40, breakdown.TotalBonus())
+
+	if assert.NotNil(t, breakdown.AssemblyService) {
+		assert.Equal(t, basket_item.ItemId("400"), breakdown.AssemblyService.Item.ItemId())
+		assert.Equal(t, 1000, breakdown.AssemblyService.Cost)
+	}
+
+	if assert.Len(t, breakdown.Components, 2) {
+		cpu := breakdown.Components[0]
+		assert.Equal(t, basket_item.ItemId("100"), cpu.Item.ItemId())
+		assert.Equal(t, 500, cpu.Cost)
+		assert.Equal(t, 10, cpu.Bonus)
+		if assert.Len(t, cpu.Services, 1) {
+			assert.Equal(t, basket_item.ItemId("300"), cpu.Services[0].Item.ItemId())
+			assert.Equal(t, 50, cpu.Services[0].Cost)
+		}
+
+		pcCase := breakdown.Components[1]
+		assert.Equal(t, basket_item.ItemId("200"), pcCase.Item.ItemId())
+		assert.Equal(t, 300, pcCase.Price)
+		assert.Equal(t, 2, pcCase.Count)
+		assert.Equal(t, 600, pcCase.Cost)
+		assert.Equal(t, 10, pcCase.Bonus)
+		assert.Empty(t, pcCase.Services)
+	}
+}
+
+func TestConfiguration_BreakdownNotFound(t *testing.T) {
+	bsk, _ := newTestConfigurationBasket(t)
+	product := basket_item.NewItem(
+		"500", basket_item.TypeProduct, "", "", 1, 100, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	bsk.data.items[product.UniqId()] = product
+
+	breakdown, err := bsk.Configuration().Breakdown(product.UniqId())
+	assert.EqualError(
+		t,
+		err,
+		internal.NewNotFoundError(fmt.Errorf("configuration '%s' not found in basket", product.UniqId())).Error(),
+	)
+	assert.Nil(t, breakdown)
+}
+
+func TestCalculateConfiguration_SkipsItemsOutOfConfiguration(t *testing.T) {
+	configurationItem := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	component := basket_item.NewItem(
+		"100", basket_item.TypeConfigurationProduct, "", "", 1, 500, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	assert.NoError(t, configurationItem.AddChild(component))
+	present := basket_item.NewItem(
+		"900", basket_item.TypePresent, "", "", 1, 100, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+
+	breakdown := calculateConfiguration(configurationItem, basket_item.Items{component, present})
+	assert.Equal(t, 500, breakdown.Price)
+	assert.Len(t, breakdown.Components, 1)
+	assert.Nil(t, breakdown.AssemblyService)
+}
@@ -0,0 +1,268 @@
+package basket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+)
+
+// ConfigurationBuildSpecVersion текущая версия формата спецификации сборки. Версию необходимо увеличивать при
+// несовместимом изменении формата
+const ConfigurationBuildSpecVersion = 1
+
+// ConfigurationBuildSpec спецификация сборки - переносимое описание конфигурации, которым можно поделиться (например,
+// поддержка отправляет его клиенту). Спецификация не привязана к корзине и региону, поэтому при импорте цены и наличие
+// комплектующих проверяются заново
+type ConfigurationBuildSpec struct {
+	Version               int                                `json:"version"`
+	ConfType              basket_item.ConfType               `json:"confType"`
+	AssemblyServiceItemId string                             `json:"assemblyServiceItemId,omitempty"`
+	Count                 int                                `json:"count"`
+	Components            []*ConfigurationBuildSpecComponent `json:"components"`
+}
+
+// ConfigurationBuildSpecComponent комплектующая в спецификации сборки
+type ConfigurationBuildSpecComponent struct {
+	ProductId catalog_types.ProductId `json:"productId"`
+	// Кол-во комплектующей в одной сборке
+	Count int `json:"count"`
+	// Цена комплектующей на момент экспорта
+	Price    int                              `json:"price,omitempty"`
+	Services []*ConfigurationBuildSpecService `json:"services,omitempty"`
+}
+
+// ConfigurationBuildSpecService услуга комплектующей в спецификации сборки, соответствует basket_item.ConfItemService
+type ConfigurationBuildSpecService struct {
+	ItemId string `json:"itemId"`
+	Name   string `json:"name,omitempty"`
+	Price  int    `json:"price,omitempty"`
+	Count  int    `json:"count"`
+}
+
+// MarshalConfigurationBuildSpec кодирует спецификацию сборки в JSON
+func MarshalConfigurationBuildSpec(spec *ConfigurationBuildSpec) ([]byte, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal build spec: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalConfigurationBuildSpec декодирует спецификацию сборки из JSON и проверяет ее
+func UnmarshalConfigurationBuildSpec(data []byte) (*ConfigurationBuildSpec, error) {
+	spec := &ConfigurationBuildSpec{}
+	err := json.Unmarshal(data, spec)
+	if err != nil {
+		return nil, internal.NewValidationError(fmt.Errorf("can't unmarshal build spec: %w", err))
+	}
+
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// Validate проверяет спецификацию сборки
+func (s *ConfigurationBuildSpec) Validate() error {
+	if s.Version < 1 || s.Version > ConfigurationBuildSpecVersion {
+		return internal.NewValidationError(fmt.Errorf("unsupported build spec version %d", s.Version))
+	}
+
+	switch s.ConfType {
+	case basket_item.ConfTypeUser, basket_item.ConfTypeTemplate, basket_item.ConfTypeVendor:
+	default:
+		return internal.NewValidationError(fmt.Errorf("unknown build spec conf type %d", s.ConfType))
+	}
+
+	if len(s.Components) == 0 {
+		return internal.NewValidationError(fmt.Errorf("build spec has no components"))
+	}
+
+	// конфигураций в корзине, как и одного товара в конфигурации, не может быть больше MaxCountOfProductItemsInConf
+	if s.Count < 1 || s.Count > MaxCountOfProductItemsInConf {
+		return internal.NewValidationError(fmt.Errorf(
+			"build spec count must be from 1 to %d, got %d", MaxCountOfProductItemsInConf, s.Count,
+		))
+	}
+
+	for _, component := range s.Components {
+		if component.ProductId == "" || component.Count < 1 || component.Count > MaxCountOfProductItemsInConf {
+			return internal.NewValidationError(
+				fmt.Errorf("invalid build spec component '%s' with count %d", component.ProductId, component.Count),
+			)
+		}
+	}
+
+	return nil
+}
+
+// ConfigurationImportReport результат импорта спецификации сборки
+type ConfigurationImportReport struct {
+	// Собранная конфигурация
+	Configuration *basket_item.Item
+	// Комплектующие, которых нет в регионе корзины. В конфигурацию они не попали
+	UnavailableProductIds []catalog_types.ProductId
+	// Комплектующие, цена которых отличается от цены в спецификации
+	RepricedComponents []*ConfigurationRepricedComponent
+}
+
+// ConfigurationRepricedComponent комплектующая, цена которой изменилась относительно спецификации сборки
+type ConfigurationRepricedComponent struct {
+	ProductId catalog_types.ProductId
+	From      int
+	To        int
+}
+
+// ExportBuildSpec экспортирует конфигурацию корзины в спецификацию сборки
+func (c *Configuration) ExportBuildSpec(confUniqId basket_item.UniqId) (*ConfigurationBuildSpec, error) {
+	configurationItem, err := c.FindOne(confUniqId)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &ConfigurationBuildSpec{
+		Version:  ConfigurationBuildSpecVersion,
+		ConfType: basket_item.ConfTypeUser,
+		Count:    configurationItem.Count(),
+	}
+	if configurationItem.Additions().GetConfiguration() != nil {
+		spec.ConfType = configurationItem.Additions().GetConfiguration().GetConfType()
+	}
+
+	content := c.contentOf(configurationItem)
+	if content.assemblyService != nil {
+		spec.AssemblyServiceItemId = string(content.assemblyService.ItemId())
+	}
+
+	for _, product := range content.products {
+		component := &ConfigurationBuildSpecComponent{
+			ProductId: catalog_types.ProductId(product.ItemId()),
+			Count:     product.Count(),
+			Price:     product.Price(),
+		}
+		for _, service := range content.productServices[product.UniqId()] {
+			component.Services = append(component.Services, &ConfigurationBuildSpecService{
+				ItemId: string(service.ItemId()),
+				Name:   service.Name(),
+				Price:  service.Price(),
+				Count:  service.Count(),
+			})
+		}
+
+		spec.Components = append(spec.Components, component)
+	}
+
+	if len(spec.Components) == 0 {
+		return nil, internal.NewLogicError(fmt.Errorf("configuration '%s' has no components", confUniqId))
+	}
+
+	return spec, nil
+}
+
+// ImportBuildSpec собирает конфигурацию по спецификации сборки в корзине. Идентификатор конфигурации выдает реестр
+// конфигураций, а сборка добавляется через Add так же, как конфигурация из конфигуратора: несовместимая сборка
+// добавляется, а проблемы совместимости прикрепляются к конфигурации. Спецификация приходит от пользователя, поэтому
+// конфигурация импортируется как пользовательская, каким бы ни был тип в спецификации. Комплектующие, которых нет
+// в регионе корзины, пропускаются, а комплектующие с изменившейся ценой добавляются по актуальной цене, и то и другое
+// попадает в отчет
+func (c *Configuration) ImportBuildSpec(
+	ctx context.Context,
+	spec *ConfigurationBuildSpec,
+) (*ConfigurationImportReport, error) {
+	err := spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	productIds := make([]catalog_types.ProductId, 0, len(spec.Components))
+	for _, component := range spec.Components {
+		productIds = append(productIds, component.ProductId)
+	}
+
+	availableProductIds, err := c.availableProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConfigurationImportReport{}
+	confItems := make([]*basket_item.ConfItem, 0, len(spec.Components))
+	registeredItems := make(basket_item.Items, 0, len(spec.Components))
+	for _, component := range spec.Components {
+		if !availableProductIds[component.ProductId] {
+			report.UnavailableProductIds = append(report.UnavailableProductIds, component.ProductId)
+			continue
+		}
+
+		confItem := &basket_item.ConfItem{ProductId: component.ProductId, Count: component.Count}
+		for _, service := range component.Services {
+			confItem.Services = append(confItem.Services, &basket_item.ConfItemService{
+				ItemId: service.ItemId,
+				Name:   service.Name,
+				Price:  service.Price,
+				Count:  service.Count,
+			})
+		}
+		confItems = append(confItems, confItem)
+
+		registeredItems = append(registeredItems, c.registeredComponent(
+			component.ProductId,
+			component.Count,
+			component.Price,
+			spec.Count,
+		))
+	}
+
+	if len(confItems) == 0 {
+		return nil, internal.NewNotFoundError(
+			fmt.Errorf("no component of build spec is available in region '%s'", c.basket.SpaceId()),
+		)
+	}
+
+	confId, err := c.register(ctx, registeredItems)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := c.Add(ctx, confId, basket_item.ConfTypeUser, spec.AssemblyServiceItemId, confItems, "")
+	if err != nil {
+		return nil, fmt.Errorf("can't add configuration from build spec: %w", err)
+	}
+
+	for _, item := range items {
+		if item.Type() == basket_item.TypeConfiguration {
+			report.Configuration = item
+			err := item.SetCount(spec.Count)
+			if err != nil {
+				return nil, fmt.Errorf("can't set configuration count: %w", err)
+			}
+		}
+	}
+
+	specPrices := make(map[catalog_types.ProductId]int, len(spec.Components))
+	for _, component := range spec.Components {
+		specPrices[component.ProductId] = component.Price
+	}
+
+	for _, item := range items {
+		if item.Type() != basket_item.TypeConfigurationProduct {
+			continue
+		}
+
+		specPrice := specPrices[catalog_types.ProductId(item.ItemId())]
+		if specPrice != 0 && specPrice != item.Price() {
+			report.RepricedComponents = append(report.RepricedComponents, &ConfigurationRepricedComponent{
+				ProductId: catalog_types.ProductId(item.ItemId()),
+				From:      specPrice,
+				To:        item.Price(),
+			})
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,327 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	overallv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/overall/v1"
+	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	productmockv1 "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
+	"testing"
+)
+
+func TestConfigurationBuildSpec_Validate(t *testing.T) {
+	validSpec := func() *ConfigurationBuildSpec {
+		return &ConfigurationBuildSpec{
+			Version:    ConfigurationBuildSpecVersion,
+			ConfType:   basket_item.ConfTypeUser,
+			Count:      1,
+			Components: []*ConfigurationBuildSpecComponent{{ProductId: "100", Count: 1}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		spec    func() *ConfigurationBuildSpec
+		wantErr error
+	}{
+		{
+			name: "valid",
+			spec: validSpec,
+		},
+		{
+			name: "unsupported version",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Version = ConfigurationBuildSpecVersion + 1
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("unsupported build spec version 2")),
+		},
+		{
+			name: "unknown conf type",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.ConfType = basket_item.ConfTypeUnknown
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("unknown build spec conf type 0")),
+		},
+		{
+			name: "no components",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Components = nil
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("build spec has no components")),
+		},
+		{
+			name: "zero count",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Count = 0
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("build spec count must be from 1 to 10, got 0")),
+		},
+		{
+			name: "count more than max",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Count = MaxCountOfProductItemsInConf + 1
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("build spec count must be from 1 to 10, got 11")),
+		},
+		{
+			name: "invalid component",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Components[0].Count = 0
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("invalid build spec component '100' with count 0")),
+		},
+		{
+			name: "component count more than max",
+			spec: func() *ConfigurationBuildSpec {
+				spec := validSpec()
+				spec.Components[0].Count = MaxCountOfProductItemsInConf + 1
+				return spec
+			},
+			wantErr: internal.NewValidationError(fmt.Errorf("invalid build spec component '100' with count 11")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.spec().Validate())
+		})
+	}
+}
+
+func TestUnmarshalConfigurationBuildSpec(t *testing.T) {
+	data := []byte(`{"version":1,"confType":1,"assemblyServiceItemId":"400","count":2,` +
+		`"components":[{"productId":"100","count":1,"price":500,` +
+		`"services":[{"itemId":"300","name":"thermal paste","price":50,"count":1}]}]}`)
+	want := &ConfigurationBuildSpec{
+		Version:               1,
+		ConfType:              basket_item.ConfTypeUser,
+		AssemblyServiceItemId: "400",
+		Count:                 2,
+		Components: []*ConfigurationBuildSpecComponent{
+			{
+				ProductId: "100",
+				Count:     1,
+				Price:     500,
+				Services: []*ConfigurationBuildSpecService{
+					{ItemId: "300", Name: "thermal paste", Price: 50, Count: 1},
+				},
+			},
+		},
+	}
+
+	got, err := UnmarshalConfigurationBuildSpec(data)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	encoded, err := MarshalConfigurationBuildSpec(got)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(encoded))
+
+	_, err = UnmarshalConfigurationBuildSpec([]byte(`{"version":`))
+	assert.Error(t, err)
+
+	_, err = UnmarshalConfigurationBuildSpec([]byte(`{"version":0}`))
+	assert.Equal(t, internal.NewValidationError(fmt.Errorf("unsupported build spec version 0")), err)
+}
+
+func TestConfiguration_ExportBuildSpec(t *testing.T) {
+	bsk, configurationItem := newTestConfigurationBasket(t)
+
+	got, err := bsk.Configuration().ExportBuildSpec(configurationItem.UniqId())
+	assert.NoError(t, err)
+	assert.Equal(t, &ConfigurationBuildSpec{
+		Version:               ConfigurationBuildSpecVersion,
+		ConfType:              basket_item.ConfTypeUser,
+		AssemblyServiceItemId: "400",
+		Count:                 2,
+		Components: []*ConfigurationBuildSpecComponent{
+			{
+				ProductId: "100",
+				Count:     1,
+				Price:     500,
+				Services: []*ConfigurationBuildSpecService{
+					{ItemId: "300", Name: "thermal paste", Price: 50, Count: 1},
+				},
+			},
+			{ProductId: "200", Count: 1, Price: 300},
+		},
+	}, got)
+
+	_, err = bsk.Configuration().ExportBuildSpec("unknown")
+	assert.Equal(t, internal.NewNotFoundError(fmt.Errorf("configuration 'unknown' not found in basket")), err)
+}
+
+func TestConfiguration_ImportBuildSpec(t *testing.T) {
+	spec := &ConfigurationBuildSpec{
+		Version: ConfigurationBuildSpecVersion,
+		// тип конфигурации из спецификации не переносится, импортированная конфигурация всегда пользовательская
+		ConfType:              basket_item.ConfTypeVendor,
+		AssemblyServiceItemId: "400",
+		Count:                 2,
+		Components: []*ConfigurationBuildSpecComponent{
+			{ProductId: "100", Count: 1, Price: 500},
+			{ProductId: "200", Count: 1, Price: 300},
+		},
+	}
+
+	productInfo := func(id string, price int32) *productv1.FindFullResponse_FullInfo {
+		return &productv1.FindFullResponse_FullInfo{
+			Id: id,
+			Price: &productv1.ProductPriceByRegion{
+				ProductId: id,
+				Prices: map[int32]*overallv1.Price{
+					1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: price},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		isCompatible bool
+		confOptions  *ConfigurationOptions
+		prepare      func(m *productmockv1.MockProductAPIClient)
+		check        func(t *testing.T, bsk *Basket, registry *InMemoryConfigurationRegistry, report *ConfigurationImportReport)
+		wantErr      error
+	}{
+		{
+			name:         "unavailable and re-priced components",
+			isCompatible: true,
+			prepare: func(m *productmockv1.MockProductAPIClient) {
+				m.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 550)},
+				}, nil)
+				m.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 550)},
+				}, nil)
+			},
+			check: func(
+				t *testing.T,
+				bsk *Basket,
+				registry *InMemoryConfigurationRegistry,
+				report *ConfigurationImportReport,
+			) {
+				assert.Equal(t, []catalog_types.ProductId{"200"}, report.UnavailableProductIds)
+				assert.Equal(t, []*ConfigurationRepricedComponent{{ProductId: "100", From: 500, To: 550}}, report.RepricedComponents)
+				assert.Equal(t, 2, report.Configuration.Count())
+				assert.Equal(t, "1", report.Configuration.Additions().GetConfiguration().GetConfId())
+				assert.Equal(t, basket_item.ConfTypeUser, report.Configuration.Additions().GetConfiguration().GetConfType())
+				assert.Empty(t, report.Configuration.Problems())
+				assert.Len(t, bsk.Find(Finders.ChildrenOfRecursive(report.Configuration)), 2)
+
+				registeredItems, ok := registry.Items("1")
+				if assert.True(t, ok) && assert.Len(t, registeredItems, 1) {
+					assert.Equal(t, 2, registeredItems[0].Count())
+				}
+			},
+		},
+		{
+			name:         "incompatible",
+			isCompatible: false,
+			prepare: func(m *productmockv1.MockProductAPIClient) {
+				m.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 500), productInfo("200", 300)},
+				}, nil)
+			},
+			wantErr: fmt.Errorf("invalid configuration"),
+		},
+		{
+			name:         "incompatible by validator",
+			isCompatible: true,
+			confOptions: NewConfigurationOptions(
+				NewConfigurationValidator(
+					nil,
+					NewCategoryCountRule(basket_item.PCCaseCategoryID, basket_item.MessageComponentPCCase, 1, 1),
+				),
+				false,
+			),
+			prepare: func(m *productmockv1.MockProductAPIClient) {
+				m.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 500), productInfo("200", 300)},
+				}, nil).Times(2)
+			},
+			check: func(
+				t *testing.T,
+				bsk *Basket,
+				registry *InMemoryConfigurationRegistry,
+				report *ConfigurationImportReport,
+			) {
+				// несовместимая сборка добавляется, а проблема совместимости прикрепляется к конфигурации
+				assert.Equal(t, 2, report.Configuration.Count())
+				assert.Len(t, bsk.Find(Finders.ChildrenOfRecursive(report.Configuration)), 2)
+				if assert.Len(t, report.Configuration.Problems(), 1) {
+					assert.Equal(
+						t,
+						"в конфигурации должно быть не меньше 1 шт. комплектующей «корпус»",
+						report.Configuration.Problems()[0].Message(),
+					)
+				}
+			},
+		},
+		{
+			name:         "nothing available",
+			isCompatible: true,
+			prepare: func(m *productmockv1.MockProductAPIClient) {
+				m.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{}, nil)
+			},
+			wantErr: internal.NewNotFoundError(fmt.Errorf("no component of build spec is available in region 'msk_cl'")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+			tt.prepare(productApiMock)
+
+			registry := NewInMemoryConfigurationRegistry(1, tt.isCompatible)
+			bsk := &Basket{
+				data: &BasketData{
+					spaceId:     "msk_cl",
+					priceColumn: catalog_types.PriceColumnRetail,
+					items:       make(map[basket_item.UniqId]*basket_item.Item),
+				},
+			}
+			bsk.configuration = NewConfiguration(bsk, productApiMock, registry, tt.confOptions)
+
+			report, err := bsk.Configuration().ImportBuildSpec(context.Background(), spec)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			tt.check(t, bsk, registry, report)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package basket
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	database "go.citilink.cloud/libdatabase"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/store_types"
+	"strings"
+	"time"
+)
+
+// MssqlConfigurationPropertiesProvider источник свойств комплектующих на основе процедуры конфигуратора в MSSQL.
+// Процедура и таблица свойств создаются миграцией migrations/mssql/configurator_get_product_properties.sql
+type MssqlConfigurationPropertiesProvider struct {
+	db database.DB
+}
+
+func NewMssqlConfigurationPropertiesProvider(db database.DB) *MssqlConfigurationPropertiesProvider {
+	return &MssqlConfigurationPropertiesProvider{db: db}
+}
+
+// Properties возвращает свойства комплектующих. Комплектующие, о которых конфигуратор ничего не знает, в результат
+// не попадают
+func (p *MssqlConfigurationPropertiesProvider) Properties(
+	ctx context.Context,
+	spaceId store_types.SpaceId,
+	itemIds []basket_item.ItemId,
+) (map[basket_item.ItemId]map[ConfigurationProperty]string, error) {
+	properties := make(map[basket_item.ItemId]map[ConfigurationProperty]string, len(itemIds))
+	if len(itemIds) == 0 {
+		return properties, nil
+	}
+
+	itemIdsStr := make([]string, 0, len(itemIds))
+	for _, itemId := range itemIds {
+		itemIdsStr = append(itemIdsStr, string(itemId))
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	rows, err := p.db.QueryxContext(dbCtx, "Configurator.get_product_properties",
+		sql.Named("item_ids", strings.Join(itemIdsStr, "|")),
+		sql.Named("space_id", string(spaceId)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't execute query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var raw struct {
+			ItemId   string `db:"item_id"`
+			Property string `db:"property"`
+			Value    string `db:"value"`
+		}
+
+		err := rows.StructScan(&raw)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan product property: %w", err)
+		}
+
+		itemId := basket_item.ItemId(raw.ItemId)
+		if properties[itemId] == nil {
+			properties[itemId] = make(map[ConfigurationProperty]string)
+		}
+		properties[itemId][ConfigurationProperty(raw.Property)] = raw.Value
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error on rows iteration: %w", rows.Err())
+	}
+
+	return properties, nil
+}
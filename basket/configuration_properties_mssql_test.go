@@ -0,0 +1,77 @@
+package basket
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"testing"
+)
+
+func TestMssqlConfigurationPropertiesProvider_Properties(t *testing.T) {
+	propertiesProcedure := "Configurator.get_product_properties"
+	propertiesRows := []string{"item_id", "property", "value"}
+
+	tests := []struct {
+		name       string
+		itemIds    []basket_item.ItemId
+		prepare    func(dbMock sqlmock.Sqlmock)
+		want       map[basket_item.ItemId]map[ConfigurationProperty]string
+		wantErrMsg string
+	}{
+		{
+			name:    "empty item ids",
+			itemIds: []basket_item.ItemId{},
+			prepare: func(dbMock sqlmock.Sqlmock) {},
+			want:    map[basket_item.ItemId]map[ConfigurationProperty]string{},
+		},
+		{
+			name:    "db error",
+			itemIds: []basket_item.ItemId{"1", "2"},
+			prepare: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectQuery(propertiesProcedure).WillReturnError(errors.New("some db error"))
+			},
+			wantErrMsg: "can't execute query: some db error",
+		},
+		{
+			name:    "ok",
+			itemIds: []basket_item.ItemId{"1", "2", "3"},
+			prepare: func(dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectQuery(propertiesProcedure).
+					WithArgs(sql.Named("item_ids", "1|2|3"), sql.Named("space_id", "msk_cl")).
+					WillReturnRows(sqlmock.NewRows(propertiesRows).
+						AddRow("1", "socket", "AM5").
+						AddRow("2", "socket", "LGA1700"))
+			},
+			want: map[basket_item.ItemId]map[ConfigurationProperty]string{
+				"1": {ConfigurationPropertySocket: "AM5"},
+				"2": {ConfigurationPropertySocket: "LGA1700"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbMock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			tt.prepare(dbMock)
+
+			provider := NewMssqlConfigurationPropertiesProvider(sqlx.NewDb(db, "sqlmock"))
+			got, err := provider.Properties(context.Background(), "msk_cl", tt.itemIds)
+
+			if tt.wantErrMsg != "" {
+				assert.EqualError(t, err, tt.wantErrMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
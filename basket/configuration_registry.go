@@ -0,0 +1,73 @@
+package basket
+
+import (
+	"context"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"strconv"
+	"sync"
+)
+
+//go:generate mockgen -source=configuration_registry.go -destination=configuration_registry_mock.go -package=basket
+
+// ConfigurationRegistry реестр конфигураций. Регистрирует временную конфигурацию из набора позиций и сообщает,
+// совместимы ли позиции между собой
+type ConfigurationRegistry interface {
+	// RegisterTemporary регистрирует временную конфигурацию. Если конфигурацию зарегистрировать не удалось, то
+	// возвращается nil без ошибки
+	RegisterTemporary(ctx context.Context, items basket_item.Items) (*TemporaryConfiguration, error)
+}
+
+// TemporaryConfiguration зарегистрированная временная конфигурация
+type TemporaryConfiguration struct {
+	ConfId         basket_item.ConfId
+	AssemblyTypeId int
+	// Совместимы ли позиции конфигурации по мнению реестра
+	IsCompatible bool
+}
+
+// InMemoryConfigurationRegistry реестр конфигураций в памяти. Все конфигурации считаются совместимыми, если не
+// задано обратное, поэтому его удобно использовать вместе с валидатором совместимости ConfigurationValidator, а так
+// же в тестах и окружениях без БД
+type InMemoryConfigurationRegistry struct {
+	mx             sync.Mutex
+	lastId         int
+	assemblyTypeId int
+	isCompatible   bool
+	configurations map[basket_item.ConfId]basket_item.Items
+}
+
+func NewInMemoryConfigurationRegistry(assemblyTypeId int, isCompatible bool) *InMemoryConfigurationRegistry {
+	return &InMemoryConfigurationRegistry{
+		assemblyTypeId: assemblyTypeId,
+		isCompatible:   isCompatible,
+		configurations: make(map[basket_item.ConfId]basket_item.Items),
+	}
+}
+
+func (r *InMemoryConfigurationRegistry) RegisterTemporary(
+	_ context.Context,
+	items basket_item.Items,
+) (*TemporaryConfiguration, error) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.lastId++
+	confId := basket_item.ConfId(strconv.Itoa(r.lastId))
+	r.configurations[confId] = items
+
+	return &TemporaryConfiguration{
+		ConfId:         confId,
+		AssemblyTypeId: r.assemblyTypeId,
+		IsCompatible:   r.isCompatible,
+	}, nil
+}
+
+// Items возвращает позиции, из которых была зарегистрирована конфигурация
+func (r *InMemoryConfigurationRegistry) Items(confId basket_item.ConfId) (basket_item.Items, bool) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	items, ok := r.configurations[confId]
+
+	return items, ok
+}
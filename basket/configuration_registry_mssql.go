@@ -0,0 +1,60 @@
+package basket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	database "go.citilink.cloud/libdatabase"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"time"
+)
+
+// MssqlConfigurationRegistry реестр конфигураций на основе процедуры конфигуратора в MSSQL
+type MssqlConfigurationRegistry struct {
+	db database.DB
+}
+
+func NewMssqlConfigurationRegistry(db database.DB) *MssqlConfigurationRegistry {
+	return &MssqlConfigurationRegistry{db: db}
+}
+
+func (r *MssqlConfigurationRegistry) RegisterTemporary(
+	ctx context.Context,
+	items basket_item.Items,
+) (*TemporaryConfiguration, error) {
+	itemListStr, err := xml.Marshal(items.ToItemList())
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal itemList: %w", err)
+	}
+
+	dbCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	row := r.db.QueryRowxContext(dbCtx, "Configurator.set_temporary_configuration",
+		sql.Named("conf_id", basket_item.DefaultConfId),
+		sql.Named("item_list", string(itemListStr)),
+	)
+	if row.Err() != nil {
+		return nil, fmt.Errorf("error on query from db: %w", row.Err())
+	}
+
+	rawData := struct {
+		ConfId         sql.NullString `db:"conf_id"`
+		AssemblyTypeId sql.NullInt64  `db:"assembly_type_id"`
+		Compatible     sql.NullBool   `db:"compatible"`
+	}{}
+	err = row.StructScan(&rawData)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error on scanned row: %w", err)
+	}
+
+	return &TemporaryConfiguration{
+		ConfId:         basket_item.ConfId(rawData.ConfId.String),
+		AssemblyTypeId: int(rawData.AssemblyTypeId.Int64),
+		IsCompatible:   rawData.Compatible.Bool,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	overallv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/overall/v1"
+	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	productmockv1 "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
+	"go.citilink.cloud/store_types"
+	"testing"
+)
+
+func TestInMemoryConfigurationRegistry_RegisterTemporary(t *testing.T) {
+	registry := NewInMemoryConfigurationRegistry(2, true)
+	items := basket_item.Items{
+		basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 100, 0, "msk_cl", catalog_types.PriceColumnRetail),
+	}
+
+	first, err := registry.RegisterTemporary(context.Background(), items)
+	assert.NoError(t, err)
+	assert.Equal(t, &TemporaryConfiguration{ConfId: "1", AssemblyTypeId: 2, IsCompatible: true}, first)
+
+	second, err := registry.RegisterTemporary(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, basket_item.ConfId("2"), second.ConfId)
+
+	registeredItems, ok := registry.Items(first.ConfId)
+	assert.True(t, ok)
+	assert.Equal(t, items, registeredItems)
+
+	_, ok = registry.Items("3")
+	assert.False(t, ok)
+}
+
+func TestConfiguration_AssembleWithInMemoryRegistry(t *testing.T) {
+	tests := []struct {
+		name         string
+		isCompatible bool
+		confOptions  *ConfigurationOptions
+		wantConfId   basket_item.ConfId
+		wantErr      string
+	}{
+		{
+			name:         "compatible",
+			isCompatible: true,
+			wantConfId:   "1",
+		},
+		{
+			name:         "incompatible by registry",
+			isCompatible: false,
+			wantErr:      "invalid configuration",
+		},
+		{
+			name:         "incompatible by rules",
+			isCompatible: true,
+			confOptions: NewConfigurationOptions(
+				NewConfigurationValidator(
+					nil,
+					NewCategoryCountRule(basket_item.PCCaseCategoryID, basket_item.MessageComponentPCCase, 1, 1),
+				),
+				true,
+			),
+			wantErr: "invalid configuration: в конфигурации должно быть не меньше 1 шт. комплектующей «корпус»",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+			productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+				Ids:     []string{"100"},
+				SpaceId: "msk_cl",
+			}).Return(&productv1.FindFullResponse{
+				Infos: []*productv1.FindFullResponse_FullInfo{
+					{
+						Id: "100",
+						Price: &productv1.ProductPriceByRegion{
+							ProductId: "100",
+							Prices: map[int32]*overallv1.Price{
+								1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: 500},
+							},
+						},
+					},
+				},
+			}, nil).AnyTimes()
+
+			bsk := &Basket{
+				data: &BasketData{
+					spaceId:     "msk_cl",
+					priceColumn: catalog_types.PriceColumnRetail,
+					items:       make(map[basket_item.UniqId]*basket_item.Item),
+				},
+			}
+			conf := NewConfiguration(bsk, productApiMock, NewInMemoryConfigurationRegistry(1, tt.isCompatible), tt.confOptions)
+
+			got, err := conf.Assemble(
+				context.Background(),
+				"11",
+				[]*basket_item.ConfItem{{ProductId: "100", Count: 1}},
+				1,
+				"",
+			)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, string(tt.wantConfId), got.Additions().GetConfiguration().GetConfId())
+		})
+	}
+}
+
+func TestConfiguration_AssembleIdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+	productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+		Ids:     []string{"100"},
+		SpaceId: "msk_cl",
+	}).Return(&productv1.FindFullResponse{
+		Infos: []*productv1.FindFullResponse_FullInfo{
+			{
+				Id: "100",
+				Price: &productv1.ProductPriceByRegion{
+					ProductId: "100",
+					Prices: map[int32]*overallv1.Price{
+						1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: 500},
+					},
+				},
+			},
+		},
+	}, nil).Times(1)
+
+	bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+	registry := NewInMemoryConfigurationRegistry(1, true)
+	conf := NewConfiguration(bsk, productApiMock, registry, nil)
+	confItems := []*basket_item.ConfItem{{ProductId: "100", Count: 1}}
+
+	first, err := conf.Assemble(context.Background(), "11", confItems, 1, "key")
+	assert.NoError(t, err)
+	count := bsk.Count()
+
+	// повтор запроса не регистрирует и не собирает конфигурацию заново
+	second, err := conf.Assemble(context.Background(), "11", confItems, 1, "key")
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, count, bsk.Count())
+	_, ok := registry.Items("2")
+	assert.False(t, ok)
+}
+
+func TestConfiguration_ValidatedConfigurationOptions(t *testing.T) {
+	newConfiguration := func(t *testing.T, propertiesErr error) (*Basket, *Configuration) {
+		ctrl := gomock.NewController(t)
+		productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+		productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+			Ids:     []string{"100"},
+			SpaceId: "msk_cl",
+		}).Return(&productv1.FindFullResponse{
+			Infos: []*productv1.FindFullResponse_FullInfo{
+				{
+					Id: "100",
+					Price: &productv1.ProductPriceByRegion{
+						ProductId: "100",
+						Prices: map[int32]*overallv1.Price{
+							1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: 500},
+						},
+					},
+				},
+			},
+		}, nil).AnyTimes()
+		propertiesProviderMock := NewMockConfigurationPropertiesProvider(ctrl)
+		propertiesProviderMock.EXPECT().
+			Properties(gomock.Any(), store_types.SpaceId("msk_cl"), []basket_item.ItemId{"100"}).
+			Return(map[basket_item.ItemId]map[ConfigurationProperty]string{}, propertiesErr).Times(1)
+
+		bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+		confOptions := NewValidatedConfigurationOptions(
+			propertiesProviderMock,
+			ConfigurationCategories{PCCase: basket_item.PCCaseCategoryID},
+			false,
+		)
+
+		return bsk, NewConfiguration(bsk, productApiMock, NewInMemoryConfigurationRegistry(1, true), confOptions)
+	}
+	confItems := []*basket_item.ConfItem{{ProductId: "100", Count: 1}}
+
+	t.Run("assemble", func(t *testing.T) {
+		bsk, conf := newConfiguration(t, nil)
+
+		got, err := conf.Assemble(context.Background(), "11", confItems, 1, "")
+
+		assert.EqualError(t, err,
+			"invalid configuration: в конфигурации должно быть не меньше 1 шт. комплектующей «корпус»")
+		assert.Nil(t, got)
+		assert.Equal(t, 0, bsk.Count())
+	})
+
+	t.Run("add", func(t *testing.T) {
+		_, conf := newConfiguration(t, nil)
+
+		items, err := conf.Add(context.Background(), "conf", basket_item.ConfTypeUser, "", confItems, "")
+
+		assert.NoError(t, err)
+		configurationItem := conf.FindByConfId("conf")
+		if assert.NotNil(t, configurationItem) {
+			assert.Contains(t, items, configurationItem)
+			if assert.Len(t, configurationItem.Problems(), 1) {
+				assert.Equal(t, basket_item.ProblemConfigurationIncompatible, configurationItem.Problems()[0].Id())
+			}
+		}
+	})
+
+	t.Run("add with properties provider error", func(t *testing.T) {
+		bsk, conf := newConfiguration(t, errors.New("some error"))
+
+		items, err := conf.Add(context.Background(), "conf", basket_item.ConfTypeUser, "", confItems, "key")
+
+		assert.EqualError(t, err,
+			"can't validate configuration: can't get properties of configuration components: some error")
+		assert.Nil(t, items)
+		assert.Equal(t, 0, bsk.Count())
+		assert.Nil(t, conf.FindByConfId("conf"))
+	})
+}
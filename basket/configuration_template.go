@@ -0,0 +1,353 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/user_types"
+	"sort"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=configuration_template.go -destination=configuration_template_mock.go -package=basket
+
+type ConfigurationTemplateId string
+
+// ConfigurationTemplate именованный шаблон конфигурации, сохраненный пользователем. Из шаблона можно собрать такую же
+// конфигурацию в любой корзине
+type ConfigurationTemplate struct {
+	Id     ConfigurationTemplateId
+	Name   string
+	UserId user_types.UserId
+	// Идентификатор конфигурации в конфигураторе, из которой был сохранен шаблон
+	ConfId                basket_item.ConfId
+	AssemblyServiceItemId string
+	// Кол-во сборок
+	Count      int
+	Components []*ConfigurationTemplateComponent
+	CreatedAt  time.Time
+}
+
+// ConfigurationTemplateComponent комплектующая шаблона конфигурации
+type ConfigurationTemplateComponent struct {
+	ProductId catalog_types.ProductId
+	Name      string
+	// Кол-во комплектующей в одной сборке
+	Count int
+	// Цена комплектующей на момент сохранения шаблона
+	Price    int
+	Services []*basket_item.ConfItemService
+}
+
+// ConfItem возвращает комплектующую в виде, в котором ее принимает Configuration.Add
+func (c *ConfigurationTemplateComponent) ConfItem() *basket_item.ConfItem {
+	return &basket_item.ConfItem{
+		ProductId: c.ProductId,
+		Count:     c.Count,
+		Services:  c.Services,
+	}
+}
+
+// ConfigurationTemplateRepository хранилище шаблонов конфигураций
+type ConfigurationTemplateRepository interface {
+	Save(ctx context.Context, template *ConfigurationTemplate) error
+	// Get возвращает шаблон по идентификатору, если шаблон не найден, то возвращается internal.NotFoundError
+	Get(ctx context.Context, id ConfigurationTemplateId) (*ConfigurationTemplate, error)
+	FindByUserId(ctx context.Context, userId user_types.UserId) ([]*ConfigurationTemplate, error)
+}
+
+// ConfigurationTemplates сохраняет конфигурации корзины в шаблоны и собирает конфигурации из шаблонов
+type ConfigurationTemplates struct {
+	repository ConfigurationTemplateRepository
+}
+
+func NewConfigurationTemplates(repository ConfigurationTemplateRepository) *ConfigurationTemplates {
+	return &ConfigurationTemplates{repository: repository}
+}
+
+// Save сохраняет конфигурацию корзины в именованный шаблон: комплектующие с их кол-вом и ценами, услуги комплектующих,
+// услугу сборки и кол-во сборок
+func (t *ConfigurationTemplates) Save(
+	ctx context.Context,
+	bsk *Basket,
+	confUniqId basket_item.UniqId,
+	name string,
+) (*ConfigurationTemplate, error) {
+	if name == "" {
+		return nil, internal.NewValidationError(fmt.Errorf("template name can't be empty"))
+	}
+
+	configurationItem, err := bsk.Configuration().FindOne(confUniqId)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &ConfigurationTemplate{
+		Id:        ConfigurationTemplateId(uuid.NewString()),
+		Name:      name,
+		UserId:    templateUserId(bsk),
+		Count:     configurationItem.Count(),
+		CreatedAt: time.Now().UTC(),
+	}
+	if configurationItem.Additions().GetConfiguration() != nil {
+		template.ConfId = basket_item.ConfId(configurationItem.Additions().GetConfiguration().GetConfId())
+	}
+
+	content := bsk.Configuration().contentOf(configurationItem)
+	if content.assemblyService != nil {
+		template.AssemblyServiceItemId = string(content.assemblyService.ItemId())
+	}
+
+	for _, product := range content.products {
+		component := &ConfigurationTemplateComponent{
+			ProductId: catalog_types.ProductId(product.ItemId()),
+			Name:      product.Name(),
+			Count:     product.Count(),
+			Price:     product.Price(),
+		}
+		for _, service := range content.productServices[product.UniqId()] {
+			component.Services = append(component.Services, &basket_item.ConfItemService{
+				ItemId: string(service.ItemId()),
+				Name:   service.Name(),
+				Price:  service.Price(),
+				Count:  service.Count(),
+			})
+		}
+
+		template.Components = append(template.Components, component)
+	}
+
+	if len(template.Components) == 0 {
+		return nil, internal.NewLogicError(fmt.Errorf("configuration '%s' has no components", confUniqId))
+	}
+
+	err = t.repository.Save(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("can't save configuration template: %w", err)
+	}
+
+	return template, nil
+}
+
+// Instantiate собирает конфигурацию из шаблона в корзине. Комплектующие, которых нет в регионе корзины, в конфигурацию
+// не попадают, о них сообщает проблема на конфигурации и информационное сообщение. Если цена комплектующей изменилась
+// с момента сохранения шаблона, то к ней добавляется информационное сообщение об изменении цены.
+//
+// Каждая сборка из шаблона регистрируется в реестре конфигураций и получает свой идентификатор конфигурации, поэтому
+// из одного шаблона в одной корзине можно собрать несколько конфигураций, а исходная конфигурация шаблона не заменяется
+//
+// Шаблоны не общие: собрать конфигурацию можно только из шаблона пользователя корзины, шаблон другого пользователя
+// считается ненайденным
+func (t *ConfigurationTemplates) Instantiate(
+	ctx context.Context,
+	bsk *Basket,
+	templateId ConfigurationTemplateId,
+) (*basket_item.Item, error) {
+	template, err := t.repository.Get(ctx, templateId)
+	if err != nil {
+		return nil, fmt.Errorf("can't get configuration template: %w", err)
+	}
+
+	if template.UserId != templateUserId(bsk) {
+		return nil, fmt.Errorf(
+			"can't get configuration template: %w",
+			internal.NewNotFoundError(fmt.Errorf("configuration template '%s' not found", templateId)),
+		)
+	}
+
+	productIds := make([]catalog_types.ProductId, 0, len(template.Components))
+	for _, component := range template.Components {
+		productIds = append(productIds, component.ProductId)
+	}
+
+	availableProductIds, err := bsk.Configuration().availableProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+
+	confCount := template.Count
+	if confCount <= 0 {
+		confCount = 1
+	}
+
+	confItems := make([]*basket_item.ConfItem, 0, len(template.Components))
+	registeredItems := make(basket_item.Items, 0, len(template.Components))
+	var unavailableComponents []*ConfigurationTemplateComponent
+	for _, component := range template.Components {
+		if !availableProductIds[component.ProductId] {
+			unavailableComponents = append(unavailableComponents, component)
+			continue
+		}
+
+		confItems = append(confItems, component.ConfItem())
+		registeredItems = append(registeredItems, bsk.Configuration().registeredComponent(
+			component.ProductId,
+			component.Count,
+			component.Price,
+			confCount,
+		))
+	}
+
+	if len(confItems) == 0 {
+		return nil, internal.NewNotFoundError(
+			fmt.Errorf("no component of template '%s' is available in region '%s'", templateId, bsk.SpaceId()),
+		)
+	}
+
+	confId, err := bsk.Configuration().register(ctx, registeredItems)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := bsk.Configuration().Add(
+		ctx,
+		confId,
+		basket_item.ConfTypeUser,
+		template.AssemblyServiceItemId,
+		confItems,
+		"",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't add configuration from template: %w", err)
+	}
+
+	var configurationItem *basket_item.Item
+	for _, item := range items {
+		if item.Type() == basket_item.TypeConfiguration {
+			configurationItem = item
+		}
+	}
+
+	if configurationItem == nil {
+		return nil, fmt.Errorf("configuration item of template '%s' not assembled", templateId)
+	}
+
+	if template.Count > 0 {
+		err = configurationItem.SetCount(template.Count)
+		if err != nil {
+			return nil, fmt.Errorf("can't set configuration count: %w", err)
+		}
+	}
+
+	templatePrices := make(map[catalog_types.ProductId]int, len(template.Components))
+	for _, component := range template.Components {
+		templatePrices[component.ProductId] = component.Price
+	}
+
+	for _, item := range items {
+		if item.Type() != basket_item.TypeConfigurationProduct {
+			continue
+		}
+
+		templatePrice, ok := templatePrices[catalog_types.ProductId(item.ItemId())]
+		if !ok || templatePrice == item.Price() {
+			continue
+		}
+
+		info := basket_item.NewLocalizedInfo(
+			basket_item.InfoIdPriceChanged,
+			basket_item.MessageTemplatePartPriceChanged,
+		)
+		info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
+			From: templatePrice,
+			To:   item.Price(),
+		}
+		item.AddInfo(info)
+	}
+
+	if len(unavailableComponents) > 0 {
+		notAvailableProductItemIds := make([]basket_item.ItemId, 0, len(unavailableComponents))
+		for _, component := range unavailableComponents {
+			notAvailableProductItemIds = append(notAvailableProductItemIds, basket_item.ItemId(component.ProductId))
+			bsk.AddInfo(NewInfo(
+				configurationItem,
+				basket_item.NewLocalizedInfo(
+					basket_item.InfoIdPositionRemoved,
+					basket_item.MessageTemplatePartNotAvailableInCity,
+					basket_item.NewStringMessageParam("component", component.Name),
+				),
+			))
+		}
+
+		problem := basket_item.NewLocalizedProblem(
+			basket_item.ProblemProductItemInConfigurationNotAvailable,
+			basket_item.MessageConfigurationPartNotAvailable,
+		)
+		problem.Additions().ConfigurationProblemAdditions = basket_item.ConfigurationProblemAdditions{
+			NotAvailableProductItemIds: notAvailableProductItemIds,
+		}
+		configurationItem.AddProblem(problem)
+	}
+
+	return configurationItem, nil
+}
+
+// templateUserId возвращает владельца шаблонов, сохраняемых из корзины. У анонимной корзины владельца нет
+func templateUserId(bsk *Basket) user_types.UserId {
+	if bsk.User() == nil {
+		return ""
+	}
+
+	return user_types.UserId(bsk.User().GetId())
+}
+
+// InMemoryConfigurationTemplateRepository хранилище шаблонов конфигураций в памяти
+type InMemoryConfigurationTemplateRepository struct {
+	mx        sync.RWMutex
+	templates map[ConfigurationTemplateId]*ConfigurationTemplate
+}
+
+func NewInMemoryConfigurationTemplateRepository() *InMemoryConfigurationTemplateRepository {
+	return &InMemoryConfigurationTemplateRepository{
+		templates: make(map[ConfigurationTemplateId]*ConfigurationTemplate),
+	}
+}
+
+func (r *InMemoryConfigurationTemplateRepository) Save(_ context.Context, template *ConfigurationTemplate) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.templates[template.Id] = template
+
+	return nil
+}
+
+func (r *InMemoryConfigurationTemplateRepository) Get(
+	_ context.Context,
+	id ConfigurationTemplateId,
+) (*ConfigurationTemplate, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	template, ok := r.templates[id]
+	if !ok {
+		return nil, internal.NewNotFoundError(fmt.Errorf("configuration template '%s' not found", id))
+	}
+
+	return template, nil
+}
+
+func (r *InMemoryConfigurationTemplateRepository) FindByUserId(
+	_ context.Context,
+	userId user_types.UserId,
+) ([]*ConfigurationTemplate, error) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	templates := make([]*ConfigurationTemplate, 0)
+	for _, template := range r.templates {
+		if template.UserId == userId {
+			templates = append(templates, template)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].CreatedAt.Before(templates[j].CreatedAt)
+	})
+
+	return templates, nil
+}
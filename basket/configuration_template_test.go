@@ -0,0 +1,372 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	overallv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/overall/v1"
+	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	userv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/profile/user/v1"
+	productmockv1 "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
+	"go.citilink.cloud/user_types"
+	"testing"
+	"time"
+)
+
+func newTestConfigurationBasket(t *testing.T) (*Basket, *basket_item.Item) {
+	configurationItem := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	configurationItem.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("conf", basket_item.ConfTypeUser))
+	assert.NoError(t, configurationItem.SetCount(2))
+
+	cpu := basket_item.NewItem(
+		"100", basket_item.TypeConfigurationProduct, "cpu", "", 1, 500, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	pcCase := basket_item.NewItem(
+		"200", basket_item.TypeConfigurationProduct, "case", "", 1, 300, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	featureService := basket_item.NewItem(
+		"300", basket_item.TypeConfigurationProductService, "thermal paste", "", 1, 50, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	assemblyService := basket_item.NewItem(
+		"400", basket_item.TypeConfigurationAssemblyService, "", "", 1, 0, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	assert.NoError(t, configurationItem.AddChild(cpu))
+	assert.NoError(t, configurationItem.AddChild(pcCase))
+	assert.NoError(t, configurationItem.AddChild(assemblyService))
+	assert.NoError(t, cpu.AddChild(featureService))
+
+	items := make(map[basket_item.UniqId]*basket_item.Item)
+	for _, item := range []*basket_item.Item{configurationItem, cpu, pcCase, featureService, assemblyService} {
+		items[item.UniqId()] = item
+	}
+
+	bsk := &Basket{
+		data: &BasketData{
+			spaceId:     "msk_cl",
+			priceColumn: catalog_types.PriceColumnRetail,
+			items:       items,
+		},
+		user: &userv1.User{Id: "user"},
+	}
+	bsk.configuration = NewConfiguration(bsk, nil, nil, nil)
+
+	return bsk, configurationItem
+}
+
+func TestConfigurationTemplates_Save(t *testing.T) {
+	bsk, configurationItem := newTestConfigurationBasket(t)
+	emptyConfiguration := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	bsk.data.items[emptyConfiguration.UniqId()] = emptyConfiguration
+
+	tests := []struct {
+		name       string
+		confUniqId basket_item.UniqId
+		tmplName   string
+		want       *ConfigurationTemplate
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			confUniqId: configurationItem.UniqId(),
+			tmplName:   "office",
+			want: &ConfigurationTemplate{
+				Name:                  "office",
+				UserId:                user_types.UserId("user"),
+				ConfId:                "conf",
+				AssemblyServiceItemId: "400",
+				Count:                 2,
+				Components: []*ConfigurationTemplateComponent{
+					{
+						ProductId: "100",
+						Name:      "cpu",
+						Count:     1,
+						Price:     500,
+						Services: []*basket_item.ConfItemService{
+							{ItemId: "300", Name: "thermal paste", Price: 50, Count: 1},
+						},
+					},
+					{ProductId: "200", Name: "case", Count: 1, Price: 300},
+				},
+			},
+		},
+		{
+			name:       "empty name",
+			confUniqId: configurationItem.UniqId(),
+			tmplName:   "",
+			wantErr:    internal.NewValidationError(fmt.Errorf("template name can't be empty")),
+		},
+		{
+			name:       "configuration not found",
+			confUniqId: "unknown",
+			tmplName:   "office",
+			wantErr:    internal.NewNotFoundError(fmt.Errorf("configuration 'unknown' not found in basket")),
+		},
+		{
+			name:       "no components",
+			confUniqId: emptyConfiguration.UniqId(),
+			tmplName:   "office",
+			wantErr: internal.NewLogicError(
+				fmt.Errorf("configuration '%s' has no components", emptyConfiguration.UniqId()),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := NewInMemoryConfigurationTemplateRepository()
+			templates := NewConfigurationTemplates(repository)
+
+			got, err := templates.Save(context.Background(), bsk, tt.confUniqId, tt.tmplName)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				return
+			}
+
+			assert.NotEmpty(t, got.Id)
+			assert.False(t, got.CreatedAt.IsZero())
+			assert.Equal(t, time.UTC, got.CreatedAt.Location())
+			// порядок дочерних позиций в корзине не гарантирован
+			assert.ElementsMatch(t, tt.want.Components, got.Components)
+			tt.want.Id, tt.want.CreatedAt, tt.want.Components = got.Id, got.CreatedAt, got.Components
+			assert.Equal(t, tt.want, got)
+
+			saved, err := repository.Get(context.Background(), got.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, got, saved)
+		})
+	}
+}
+
+func TestConfigurationTemplates_Instantiate(t *testing.T) {
+	type mocks struct {
+		productApiMock *productmockv1.MockProductAPIClient
+	}
+
+	template := &ConfigurationTemplate{
+		Id:                    "tmpl",
+		Name:                  "office",
+		ConfId:                "conf",
+		AssemblyServiceItemId: "400",
+		Count:                 3,
+		Components: []*ConfigurationTemplateComponent{
+			{ProductId: "100", Name: "cpu", Count: 1, Price: 500},
+			{ProductId: "200", Name: "case", Count: 1, Price: 300},
+		},
+	}
+
+	productInfo := func(id string, price int32) *productv1.FindFullResponse_FullInfo {
+		return &productv1.FindFullResponse_FullInfo{
+			Id: id,
+			Price: &productv1.ProductPriceByRegion{
+				ProductId: id,
+				Prices: map[int32]*overallv1.Price{
+					1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: price},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		templateId ConfigurationTemplateId
+		prepare    func(m *mocks)
+		check      func(t *testing.T, bsk *Basket, conf *basket_item.Item)
+		wantErr    error
+	}{
+		{
+			name:       "template not found",
+			templateId: "unknown",
+			prepare:    func(m *mocks) {},
+			wantErr: fmt.Errorf("can't get configuration template: %w",
+				internal.NewNotFoundError(fmt.Errorf("configuration template 'unknown' not found"))),
+		},
+		{
+			name:       "template of other user",
+			templateId: "other",
+			prepare:    func(m *mocks) {},
+			wantErr: fmt.Errorf("can't get configuration template: %w",
+				internal.NewNotFoundError(fmt.Errorf("configuration template 'other' not found"))),
+		},
+		{
+			name:       "catalog error",
+			templateId: "tmpl",
+			prepare: func(m *mocks) {
+				m.productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(nil, errors.New("some error"))
+			},
+			wantErr: internal.NewCatalogError(fmt.Errorf("can't get products from catalog: some error"), "msk_cl"),
+		},
+		{
+			name:       "nothing available",
+			templateId: "tmpl",
+			prepare: func(m *mocks) {
+				m.productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{}, nil)
+			},
+			wantErr: internal.NewNotFoundError(
+				fmt.Errorf("no component of template 'tmpl' is available in region 'msk_cl'"),
+			),
+		},
+		{
+			name:       "all available with same prices",
+			templateId: "tmpl",
+			prepare: func(m *mocks) {
+				m.productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 500), productInfo("200", 300)},
+				}, nil).Times(2)
+			},
+			check: func(t *testing.T, bsk *Basket, conf *basket_item.Item) {
+				assert.Equal(t, 3, conf.Count())
+				assert.Equal(t, basket_item.ConfTypeUser, conf.Additions().GetConfiguration().GetConfType())
+				assert.Len(t, bsk.Find(Finders.ByType(basket_item.TypeConfigurationProduct)), 2)
+				assert.Len(t, bsk.Find(Finders.ByType(basket_item.TypeConfigurationAssemblyService)), 1)
+				assert.Empty(t, conf.Problems())
+				assert.Empty(t, bsk.data.infos)
+				for _, item := range bsk.Find(Finders.ByType(basket_item.TypeConfigurationProduct)) {
+					assert.Empty(t, item.Infos())
+				}
+			},
+		},
+		{
+			name:       "re-priced and unavailable components",
+			templateId: "tmpl",
+			prepare: func(m *mocks) {
+				m.productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100", "200"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 600)},
+				}, nil)
+				m.productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+					Ids:     []string{"100"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{productInfo("100", 600)},
+				}, nil)
+			},
+			check: func(t *testing.T, bsk *Basket, conf *basket_item.Item) {
+				products := bsk.Find(Finders.ByType(basket_item.TypeConfigurationProduct))
+				if assert.Len(t, products, 1) {
+					info := products[0].Infos()[basket_item.InfoIdPriceChanged]
+					if assert.NotNil(t, info) {
+						assert.Equal(t, basket_item.PriceChangedInfoAddition{From: 500, To: 600}, info.Additionals().PriceChanged)
+					}
+				}
+
+				if assert.Len(t, conf.Problems(), 1) {
+					assert.Equal(t, basket_item.ProblemProductItemInConfigurationNotAvailable, conf.Problems()[0].Id())
+					assert.Equal(
+						t,
+						[]basket_item.ItemId{"200"},
+						conf.Problems()[0].Additions().ConfigurationProblemAdditions.NotAvailableProductItemIds,
+					)
+				}
+
+				if assert.Len(t, bsk.data.infos, 1) {
+					assert.Equal(t, basket_item.InfoIdPositionRemoved, bsk.data.infos[0].Info().Id())
+					assert.Equal(t, conf, bsk.data.infos[0].Item())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := &mocks{productApiMock: productmockv1.NewMockProductAPIClient(ctrl)}
+			tt.prepare(m)
+
+			repository := NewInMemoryConfigurationTemplateRepository()
+			assert.NoError(t, repository.Save(context.Background(), template))
+			assert.NoError(t, repository.Save(context.Background(), &ConfigurationTemplate{
+				Id:         "other",
+				UserId:     "other",
+				Components: template.Components,
+			}))
+
+			bsk := &Basket{
+				data: &BasketData{
+					spaceId:     "msk_cl",
+					priceColumn: catalog_types.PriceColumnRetail,
+					items:       make(map[basket_item.UniqId]*basket_item.Item),
+				},
+				productClient: m.productApiMock,
+			}
+			bsk.configuration = NewConfiguration(bsk, m.productApiMock, NewInMemoryConfigurationRegistry(1, true), nil)
+
+			got, err := NewConfigurationTemplates(repository).Instantiate(context.Background(), bsk, tt.templateId)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.check != nil {
+				tt.check(t, bsk, got)
+			}
+		})
+	}
+}
+
+func TestConfigurationTemplates_InstantiateTwice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+	productApiMock.EXPECT().FindFull(gomock.Any(), &productv1.FindFullRequest{
+		Ids:     []string{"100", "200"},
+		SpaceId: "msk_cl",
+	}).Return(&productv1.FindFullResponse{
+		Infos: []*productv1.FindFullResponse_FullInfo{
+			{Id: "100", Price: &productv1.ProductPriceByRegion{ProductId: "100", Prices: map[int32]*overallv1.Price{
+				1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: 500},
+			}}},
+			{Id: "200", Price: &productv1.ProductPriceByRegion{ProductId: "200", Prices: map[int32]*overallv1.Price{
+				1: {Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL, Price: 300},
+			}}},
+		},
+	}, nil).AnyTimes()
+
+	// шаблон собирается в исходной корзине, где уже есть конфигурация, из которой он был сохранен
+	bsk, sourceConfiguration := newTestConfigurationBasket(t)
+	bsk.productClient = productApiMock
+	bsk.configuration = NewConfiguration(bsk, productApiMock, NewInMemoryConfigurationRegistry(1, true), nil)
+	templates := NewConfigurationTemplates(NewInMemoryConfigurationTemplateRepository())
+	template, err := templates.Save(context.Background(), bsk, sourceConfiguration.UniqId(), "office")
+	assert.NoError(t, err)
+
+	first, err := templates.Instantiate(context.Background(), bsk, template.Id)
+	assert.NoError(t, err)
+	second, err := templates.Instantiate(context.Background(), bsk, template.Id)
+	assert.NoError(t, err)
+
+	assert.Len(t, bsk.Configuration().All(), 3)
+	assert.NotNil(t, bsk.FindOneById(sourceConfiguration.UniqId()))
+	assert.NotNil(t, bsk.FindOneById(first.UniqId()))
+	assert.NotNil(t, bsk.FindOneById(second.UniqId()))
+	assert.Equal(t, "1", first.Additions().GetConfiguration().GetConfId())
+	assert.Equal(t, "2", second.Additions().GetConfiguration().GetConfId())
+	assert.Len(t, bsk.Find(Finders.ByType(basket_item.TypeConfigurationProduct)), 6)
+}
+
+func TestInMemoryConfigurationTemplateRepository_FindByUserId(t *testing.T) {
+	repository := NewInMemoryConfigurationTemplateRepository()
+	now := time.Now()
+	first := &ConfigurationTemplate{Id: "1", UserId: "user", CreatedAt: now}
+	second := &ConfigurationTemplate{Id: "2", UserId: "user", CreatedAt: now.Add(-time.Hour)}
+	other := &ConfigurationTemplate{Id: "3", UserId: "other", CreatedAt: now}
+	for _, template := range []*ConfigurationTemplate{first, second, other} {
+		assert.NoError(t, repository.Save(context.Background(), template))
+	}
+
+	got, err := repository.FindByUserId(context.Background(), "user")
+	assert.NoError(t, err)
+	assert.Equal(t, []*ConfigurationTemplate{second, first}, got)
+}
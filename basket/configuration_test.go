@@ -15,16 +15,21 @@ import (
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	overallv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/overall/v1"
 	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	servicev1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/service/v1"
 	productmockv1 "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
 	"go.citilink.cloud/store_types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"testing"
+	"time"
 )
 
 func TestConfiguration_Add(t *testing.T) {
 	type fields struct {
-		bsk           *Basket
-		productClient productv1.ProductAPIClient
-		db            database.DB
+		bsk            *Basket
+		productClient  productv1.ProductAPIClient
+		db             database.DB
+		faultScenarios *FaultScenarioRegistry
 	}
 	type args struct {
 		ctx                   context.Context
@@ -85,6 +90,35 @@ func TestConfiguration_Add(t *testing.T) {
 				"msk_cl",
 			),
 		},
+		{
+			name: "catalog timeout fault scenario",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:        "msk_cl",
+						faultScenarios: []*FaultScenario{NewFaultScenario(FaultScenarioCatalogTimeout)},
+					},
+				},
+				faultScenarios: NewFaultScenarioRegistry(true),
+			},
+			args: args{
+				ctx:                   context.Background(),
+				confId:                basket_item.ConfId("10"),
+				confType:              basket_item.ConfType(1),
+				assemblyServiceItemId: "11",
+				confItems: []*basket_item.ConfItem{
+					{
+						ProductId: catalog_types.ProductId("100"),
+					},
+				},
+			},
+			prepare: func(productApiMock *productmockv1.MockProductAPIClient) {},
+			want:    nil,
+			err: fmt.Errorf("can't assemble item's configuration: %w", fmt.Errorf(
+				"can't get products from catalog: %w",
+				status.Error(codes.DeadlineExceeded, "fault scenario 'catalog_timeout'"),
+			)),
+		},
 		{
 			name: "can't find all products",
 			fields: fields{
@@ -231,11 +265,11 @@ func TestConfiguration_Add(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			conf := &Configuration{
 				basket:        tt.fields.bsk,
-				productClient: productApiMock,
-				db:            sqlxDB,
+				productClient: tt.fields.faultScenarios.WrapProductClient(productApiMock),
+				registry:      NewMssqlConfigurationRegistry(sqlxDB),
 			}
 
-			got, err := conf.Add(tt.args.ctx, tt.args.confId, tt.args.confType, tt.args.assemblyServiceItemId, tt.args.confItems)
+			got, err := conf.Add(tt.args.ctx, tt.args.confId, tt.args.confType, tt.args.assemblyServiceItemId, tt.args.confItems, "")
 			if tt.err != nil {
 				assert.EqualError(t, err, tt.err.Error())
 			}
@@ -268,6 +302,7 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 		wantErr     bool
 		err         error
 		mocks       func(ctrl *gomock.Controller) *mocks
+		check       func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item)
 	}{
 		{
 			name: "can't move item out",
@@ -283,13 +318,21 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 			},
 			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
 				itemsMapConfNotMutable := make(map[basket_item.UniqId]*basket_item.Item)
-				itemsMapConfNotMutable["1"] = basket_item.NewItem(
+				conf := basket_item.NewItem(
 					"11", basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
 					"msk_cl", catalog_types.PriceColumn(1),
 				)
+				itemProduct := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(itemProduct)
+				itemsMapConfNotMutable["1"] = itemProduct
+				itemsMapConfNotMutable["2"] = conf
 
 				return itemsMapConfNotMutable
 			},
+			wantErr: true,
 			err: internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item out"),
 				"Из шаблонной конфигурации нельзя удалять комплектующие."),
 
@@ -315,7 +358,7 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
 				itemsTemplateMap := make(map[basket_item.UniqId]*basket_item.Item)
 				itemsTemplateMap["1"] = basket_item.NewItem(
-					"11", "t", "", "", 1, 1, 0,
+					"11", basket_item.TypeConfigurationProduct, "", "", 1, 1, 0,
 					"msk_cl", catalog_types.PriceColumn(1),
 				)
 
@@ -323,7 +366,8 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 
 				return itemsTemplateMap
 			},
-			err: fmt.Errorf("no configuration in basket"),
+			wantErr: true,
+			err:     fmt.Errorf("no configuration in basket"),
 			mocks: func(ctrl *gomock.Controller) *mocks {
 				return &mocks{
 					productApiMock:  productmockv1.NewMockProductAPIClient(ctrl),
@@ -405,12 +449,14 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 				item.Additions().SetConfiguration(&basket_item.ConfiguratorItemAdditions{})
 
 				itemProduct.Additions().SetConfiguration(&basket_item.ConfiguratorItemAdditions{})
+				_ = item.AddChild(itemProduct)
 				itemsMapProduct["1"] = itemProduct
 				itemsMapProduct["2"] = item
 
 				return itemsMapProduct
 			},
-			err: fmt.Errorf("can't move product from configuration to basket: can't create item with item factory: test error"),
+			wantErr: true,
+			err:     fmt.Errorf("can't move product from configuration to basket: can't create item with item factory: test error"),
 			mocks: func(ctrl *gomock.Controller) *mocks {
 				return &mocks{
 					productApiMock:  productmockv1.NewMockProductAPIClient(ctrl),
@@ -437,6 +483,7 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 					"11", basket_item.TypeConfigurationAssemblyService, "n", "image", 1, 1, 0,
 					"msk_cl", catalog_types.PriceColumn(1),
 				)
+				itemProduct.SetMovableFromConfiguration(true)
 
 				item := basket_item.NewItem(
 					"11", basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
@@ -446,6 +493,7 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 				item.Additions().SetConfiguration(&basket_item.ConfiguratorItemAdditions{})
 
 				itemProduct.Additions().SetConfiguration(&basket_item.ConfiguratorItemAdditions{})
+				_ = item.AddChild(itemProduct)
 				itemsMapProduct["1"] = itemProduct
 				itemsMapProduct["2"] = item
 
@@ -458,6 +506,62 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 					itemFactoryMock: basket_item.NewMockItemFactory(ctrl),
 				}
 			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 1)
+				assert.NotNil(t, items["2"])
+			},
+		},
+		{
+			name: "success with template configuration in basket",
+			fields: func(itemFactoryMock *basket_item.MockItemFactory) fields {
+				return fields{
+					bsk: &Basket{
+						data: &BasketData{
+							spaceId:     "msk_cl",
+							priceColumn: catalog_types.PriceColumnRetail,
+						},
+						itemFactory: itemFactoryMock,
+					},
+				}
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				items := make(map[basket_item.UniqId]*basket_item.Item)
+				itemService := basket_item.NewItem(
+					"11", basket_item.TypeConfigurationAssemblyService, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				itemService.SetMovableFromConfiguration(true)
+
+				userConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				userConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				_ = userConf.AddChild(itemService)
+
+				templateConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				templateConf.Additions().SetConfiguration(
+					basket_item.NewConfiguratorItemAdditions("2", basket_item.ConfTypeTemplate),
+				)
+
+				items["1"] = itemService
+				items["2"] = userConf
+				items["3"] = templateConf
+
+				return items
+			},
+			mocks: func(ctrl *gomock.Controller) *mocks {
+				return &mocks{
+					productApiMock:  productmockv1.NewMockProductAPIClient(ctrl),
+					itemFactoryMock: basket_item.NewMockItemFactory(ctrl),
+				}
+			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 2)
+			},
 		},
 	}
 	for _, tt := range tests {
@@ -477,10 +581,13 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 			conf := &Configuration{
 				basket:        tt.fields(mocks.itemFactoryMock).bsk,
 				productClient: mocks.productApiMock,
-				db:            sqlxDB,
+				registry:      NewMssqlConfigurationRegistry(sqlxDB),
 			}
 			items := tt.basketItems()
-			conf.basket.data.items = items
+			conf.basket.data.items = make(map[basket_item.UniqId]*basket_item.Item, len(items))
+			for _, item := range items {
+				conf.basket.data.items[item.UniqId()] = item
+			}
 
 			err := conf.MoveItemFrom(context.Background(), items["1"].UniqId())
 			if tt.wantErr {
@@ -488,6 +595,12 @@ func TestConfiguration_MoveItemFrom(t *testing.T) {
 				if tt.err != nil {
 					assert.EqualError(t, err, tt.err.Error())
 				}
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, conf.basket.data.items)
 			}
 		})
 		db.Close()
@@ -505,7 +618,10 @@ func TestConfiguration_Disassemble(t *testing.T) {
 		name        string
 		fields      fields
 		basketItems func() map[basket_item.UniqId]*basket_item.Item
-		err         error
+		confKey     basket_item.UniqId
+		prepare     func(productApiMock *productmockv1.MockProductAPIClient)
+		err         func(items map[basket_item.UniqId]*basket_item.Item) error
+		check       func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, infos []*Info)
 	}{
 		{
 			name: "can't disassemble",
@@ -526,8 +642,11 @@ func TestConfiguration_Disassemble(t *testing.T) {
 
 				return itemsMapConfNotMutable
 			},
-			err: internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't disassemble"),
-				"Из шаблонной конфигурации нельзя удалять комплектующие."),
+			confKey: "11",
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't disassemble"),
+					"Шаблонную конфигурацию нельзя разобрать.")
+			},
 		},
 		{
 			name: "no configuration in basket",
@@ -548,7 +667,12 @@ func TestConfiguration_Disassemble(t *testing.T) {
 
 				return itemsTemplateMap
 			},
-			err: fmt.Errorf("no configuration in basket"),
+			confKey: "50",
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewNotFoundError(
+					fmt.Errorf("configuration '%s' not found in basket", items["50"].UniqId()),
+				)
+			},
 		},
 		{
 			name: "success with children",
@@ -579,6 +703,14 @@ func TestConfiguration_Disassemble(t *testing.T) {
 
 				return itemsMapConf
 			},
+			confKey: "1",
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, _ []*Info) {
+				assert.Len(t, items, 1)
+				for _, item := range items {
+					assert.Equal(t, basket_item.TypeProduct, item.Type())
+					assert.Equal(t, basket_item.ItemId("12"), item.ItemId())
+				}
+			},
 		},
 		{
 			name: "success",
@@ -601,6 +733,296 @@ func TestConfiguration_Disassemble(t *testing.T) {
 
 				return itemsMapConf
 			},
+			confKey: "11",
+		},
+		{
+			name: "only chosen configuration is disassembled",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				itemsMapConf := make(map[basket_item.UniqId]*basket_item.Item)
+				firstConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 2, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				firstConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				firstChild := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = firstConf.AddChild(firstChild)
+
+				secondConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				secondConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("2", basket_item.ConfTypeUser))
+				secondChild := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = secondConf.AddChild(secondChild)
+
+				itemsMapConf["1"] = firstConf
+				itemsMapConf["2"] = firstChild
+				itemsMapConf["3"] = secondConf
+				itemsMapConf["4"] = secondChild
+
+				return itemsMapConf
+			},
+			confKey: "1",
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, _ []*Info) {
+				assert.Len(t, items, 3)
+				products := Finders.ByType(basket_item.TypeProduct)(basket_item.ItemMap(items).ToSlice())
+				assert.Len(t, products, 1)
+				assert.Equal(t, 2, products[0].Count())
+				assert.Len(t, Finders.ByType(basket_item.TypeConfiguration)(basket_item.ItemMap(items).ToSlice()), 1)
+			},
+		},
+		{
+			name: "component services are kept as standalone services",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				itemsMapConf := make(map[basket_item.UniqId]*basket_item.Item)
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 2, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				product := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "Процессор", "image", 1, 500, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(product)
+				installService := basket_item.NewItem(
+					"21", basket_item.TypeConfigurationProductService, "Установка", "", 1, 100, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = product.AddChild(installService)
+				thermalPasteService := basket_item.NewItem(
+					"22", basket_item.TypeConfigurationProductService, "Термопаста", "", 1, 50, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = product.AddChild(thermalPasteService)
+				assemblyService := basket_item.NewItem(
+					"31", basket_item.TypeConfigurationAssemblyService, "Сборка", "", 1, 1000, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(assemblyService)
+
+				itemsMapConf["1"] = conf
+				itemsMapConf["2"] = product
+				itemsMapConf["3"] = installService
+				itemsMapConf["4"] = thermalPasteService
+				itemsMapConf["5"] = assemblyService
+
+				return itemsMapConf
+			},
+			confKey: "1",
+			prepare: func(productApiMock *productmockv1.MockProductAPIClient) {
+				productApiMock.EXPECT().FindServices(gomock.Any(), &productv1.FindServicesRequest{
+					ProductId: "12",
+					SpaceId:   "msk_cl",
+				}).Return(&productv1.FindServicesResponse{
+					SubcontractServices: map[string]*servicev1.SubcontractService{
+						"21": {Id: "21"},
+					},
+				}, nil)
+			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, infos []*Info) {
+				assert.Len(t, items, 2)
+				products := Finders.ByType(basket_item.TypeProduct)(basket_item.ItemMap(items).ToSlice())
+				if assert.Len(t, products, 1) {
+					assert.Equal(t, 2, products[0].Count())
+				}
+
+				services := Finders.ByType(basket_item.TypeSubcontractServiceForProduct)(basket_item.ItemMap(items).ToSlice())
+				if assert.Len(t, services, 1) {
+					assert.Equal(t, basket_item.ItemId("21"), services[0].ItemId())
+					assert.Equal(t, products[0].UniqId(), services[0].ParentUniqId())
+					assert.Equal(t, 2, services[0].Count())
+					assert.Equal(t, 100, services[0].Price())
+				}
+
+				removedItemIds := make([]basket_item.ItemId, 0, len(infos))
+				for _, info := range infos {
+					assert.Equal(t, basket_item.InfoIdPositionRemoved, info.Info().Id())
+					removedItemIds = append(removedItemIds, info.Item().ItemId())
+				}
+				assert.ElementsMatch(t, []basket_item.ItemId{"22", "31"}, removedItemIds)
+			},
+		},
+		{
+			name: "component services are moved to existing product line",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				itemsMapConf := make(map[basket_item.UniqId]*basket_item.Item)
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 2, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				product := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "Процессор", "image", 1, 500, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(product)
+				service := basket_item.NewItem(
+					"21", basket_item.TypeConfigurationProductService, "Установка", "", 1, 100, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = product.AddChild(service)
+				standaloneProduct := basket_item.NewItem(
+					"12", basket_item.TypeProduct, "Процессор", "image", 1, 500, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+
+				itemsMapConf["1"] = conf
+				itemsMapConf["2"] = product
+				itemsMapConf["3"] = service
+				itemsMapConf["4"] = standaloneProduct
+
+				return itemsMapConf
+			},
+			confKey: "1",
+			prepare: func(productApiMock *productmockv1.MockProductAPIClient) {
+				productApiMock.EXPECT().FindServices(gomock.Any(), gomock.Any()).
+					Return(&productv1.FindServicesResponse{
+						SubcontractServices: map[string]*servicev1.SubcontractService{
+							"21": {Id: "21"},
+						},
+					}, nil)
+			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, _ []*Info) {
+				assert.Len(t, items, 2)
+				assert.Empty(t, Finders.ByType(basket_item.TypeConfiguration)(basket_item.ItemMap(items).ToSlice()))
+				products := Finders.ByType(basket_item.TypeProduct)(basket_item.ItemMap(items).ToSlice())
+				if assert.Len(t, products, 1) {
+					assert.Equal(t, 3, products[0].Count())
+				}
+
+				services := Finders.ByType(basket_item.TypeSubcontractServiceForProduct)(basket_item.ItemMap(items).ToSlice())
+				if assert.Len(t, services, 1) {
+					assert.Equal(t, products[0].UniqId(), services[0].ParentUniqId())
+					assert.Equal(t, 3, services[0].Count())
+				}
+			},
+		},
+		{
+			name: "product has no standalone services in catalog",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				itemsMapConf := make(map[basket_item.UniqId]*basket_item.Item)
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				product := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "Процессор", "image", 1, 500, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(product)
+				service := basket_item.NewItem(
+					"21", basket_item.TypeConfigurationProductService, "Установка", "", 1, 100, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = product.AddChild(service)
+
+				itemsMapConf["1"] = conf
+				itemsMapConf["2"] = product
+				itemsMapConf["3"] = service
+
+				return itemsMapConf
+			},
+			confKey: "1",
+			prepare: func(productApiMock *productmockv1.MockProductAPIClient) {
+				productApiMock.EXPECT().FindServices(gomock.Any(), gomock.Any()).
+					Return(nil, status.Error(codes.NotFound, "not found"))
+			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item, infos []*Info) {
+				assert.Len(t, items, 1)
+				if assert.Len(t, infos, 1) {
+					assert.Equal(t, basket_item.InfoIdPositionRemoved, infos[0].Info().Id())
+					assert.Equal(t, basket_item.ItemId("21"), infos[0].Item().ItemId())
+				}
+			},
+		},
+		{
+			name: "catalog error on getting services",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				itemsMapConf := make(map[basket_item.UniqId]*basket_item.Item)
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				product := basket_item.NewItem(
+					"12", basket_item.TypeConfigurationProduct, "Процессор", "image", 1, 500, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(product)
+				service := basket_item.NewItem(
+					"21", basket_item.TypeConfigurationProductService, "Установка", "", 1, 100, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = product.AddChild(service)
+
+				itemsMapConf["1"] = conf
+				itemsMapConf["2"] = product
+				itemsMapConf["3"] = service
+
+				return itemsMapConf
+			},
+			confKey: "1",
+			prepare: func(productApiMock *productmockv1.MockProductAPIClient) {
+				productApiMock.EXPECT().FindServices(gomock.Any(), gomock.Any()).
+					Return(nil, status.Error(codes.Internal, "internal"))
+			},
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewCatalogError(
+					fmt.Errorf(
+						"can't get services of product from catalog microservice: %w",
+						status.Error(codes.Internal, "internal"),
+					),
+					"msk_cl",
+				)
+			},
 		},
 	}
 
@@ -615,19 +1037,31 @@ func TestConfiguration_Disassemble(t *testing.T) {
 		sqlxDB := sqlx.NewDb(db, "sqlmock")
 
 		productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+		if tt.prepare != nil {
+			tt.prepare(productApiMock)
+		}
 
 		t.Run(tt.name, func(t *testing.T) {
 			conf := &Configuration{
 				basket:        tt.fields.bsk,
 				productClient: productApiMock,
-				db:            sqlxDB,
+				registry:      NewMssqlConfigurationRegistry(sqlxDB),
 			}
 			items := tt.basketItems()
-			conf.basket.data.items = items
+			conf.basket.data.items = make(map[basket_item.UniqId]*basket_item.Item, len(items))
+			for _, item := range items {
+				conf.basket.data.items[item.UniqId()] = item
+			}
 
-			err := conf.Disassemble()
+			err := conf.Disassemble(context.Background(), items[tt.confKey].UniqId())
 			if tt.err != nil {
-				assert.EqualError(t, err, tt.err.Error())
+				assert.EqualError(t, err, tt.err(items).Error())
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, conf.basket.data.items, conf.basket.Infos())
 			}
 		})
 		db.Close()
@@ -649,8 +1083,10 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 		fields      fields
 		args        args
 		basketItems func() map[basket_item.UniqId]*basket_item.Item
+		confKey     basket_item.UniqId
 		wantErr     bool
-		err         error
+		err         func(items map[basket_item.UniqId]*basket_item.Item) error
+		check       func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item)
 	}{
 		{
 			name: "can't move item out",
@@ -671,9 +1107,12 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 
 				return itemsNotMutable
 			},
+			confKey: "1",
 			wantErr: true,
-			err: internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item in"),
-				"В шаблонную конфигурации нельзя добавлять комплектующие."),
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewLogicErrorWithMsg(errors.New("configuration is template and can't move item in"),
+					"В шаблонную конфигурации нельзя добавлять комплектующие.")
+			},
 		},
 		{
 			name: "no configuration in basket",
@@ -694,8 +1133,13 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 
 				return itemsTemplate
 			},
+			confKey: "1",
 			wantErr: true,
-			err:     fmt.Errorf("no configuration in basket"),
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewNotFoundError(
+					fmt.Errorf("configuration '%s' not found in basket", items["1"].UniqId()),
+				)
+			},
 		},
 		{
 			name: "item not found",
@@ -718,6 +1162,7 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 
 				return itemsConf
 			},
+			confKey: "1",
 			wantErr: true,
 		},
 		{
@@ -749,6 +1194,7 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 
 				return itemsMapProduct
 			},
+			confKey: "2",
 			wantErr: true,
 		},
 		{
@@ -780,17 +1226,214 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 
 				return itemsMapProduct
 			},
+			confKey: "2",
 			wantErr: true,
 		},
-	}
-	for _, tt := range tests {
-		ctrl := gomock.NewController(t)
+		{
+			name: "move item in chosen configuration",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				items := make(map[basket_item.UniqId]*basket_item.Item)
+				itemProduct := basket_item.NewItem(
+					"1", basket_item.TypeProduct, "n", "image", 2, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				itemProduct.SetMovableToConfiguration(true)
 
-		db, _, err := sqlmock.New()
-		if err != nil {
-			t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
-		}
-		sqlxDB := sqlx.NewDb(db, "sqlmock")
+				firstConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				firstConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+
+				secondConf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				secondConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("2", basket_item.ConfTypeUser))
+
+				items["1"] = itemProduct
+				items["2"] = firstConf
+				items["3"] = secondConf
+
+				return items
+			},
+			confKey: "3",
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 3)
+				for _, item := range items {
+					if item.Type() != basket_item.TypeConfigurationProduct {
+						continue
+					}
+
+					parent := items[item.ParentUniqId()]
+					assert.Equal(t, "2", parent.Additions().GetConfiguration().GetConfId())
+					assert.Equal(t, "2", item.Additions().GetConfiguration().GetConfId())
+					assert.Equal(t, 2, item.Count())
+				}
+			},
+		},
+		{
+			name: "partial move of item over max count",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				items := make(map[basket_item.UniqId]*basket_item.Item)
+				itemProduct := basket_item.NewItem(
+					"1", basket_item.TypeProduct, "n", "image", 13, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				itemProduct.SetMovableToConfiguration(true)
+
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+
+				items["1"] = itemProduct
+				items["2"] = conf
+
+				return items
+			},
+			confKey: "2",
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 3)
+				for _, item := range items {
+					switch item.Type() {
+					case basket_item.TypeConfigurationProduct:
+						assert.Equal(t, MaxCountOfProductItemsInConf, item.Count())
+					case basket_item.TypeProduct:
+						assert.Equal(t, 3, item.Count())
+						assert.NotNil(t, item.Infos()[basket_item.InfoIdPositionSplit])
+					}
+				}
+			},
+		},
+		{
+			name: "partial move into existing configuration product",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				items := make(map[basket_item.UniqId]*basket_item.Item)
+				itemProduct := basket_item.NewItem(
+					"1", basket_item.TypeProduct, "n", "image", 5, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				itemProduct.SetMovableToConfiguration(true)
+
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				confProduct := basket_item.NewItem(
+					"1", basket_item.TypeConfigurationProduct, "n", "image", 8, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(confProduct)
+
+				items["1"] = itemProduct
+				items["2"] = conf
+				items["3"] = confProduct
+
+				return items
+			},
+			confKey: "2",
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 3)
+				for _, item := range items {
+					switch item.Type() {
+					case basket_item.TypeConfigurationProduct:
+						assert.Equal(t, MaxCountOfProductItemsInConf, item.Count())
+					case basket_item.TypeProduct:
+						assert.Equal(t, 3, item.Count())
+						assert.NotNil(t, item.Infos()[basket_item.InfoIdPositionSplit])
+					}
+				}
+			},
+		},
+		{
+			name: "configuration already has max count of item",
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+					},
+				},
+			},
+			basketItems: func() map[basket_item.UniqId]*basket_item.Item {
+				items := make(map[basket_item.UniqId]*basket_item.Item)
+				itemProduct := basket_item.NewItem(
+					"1", basket_item.TypeProduct, "n", "image", 2, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				itemProduct.SetMovableToConfiguration(true)
+
+				conf := basket_item.NewItem(
+					basket_item.ConfSpecialItemId, basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				conf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+				confProduct := basket_item.NewItem(
+					"1", basket_item.TypeConfigurationProduct, "n", "image", MaxCountOfProductItemsInConf, 1, 0,
+					"msk_cl", catalog_types.PriceColumn(1),
+				)
+				_ = conf.AddChild(confProduct)
+
+				items["1"] = itemProduct
+				items["2"] = conf
+				items["3"] = confProduct
+
+				return items
+			},
+			confKey: "2",
+			wantErr: true,
+			err: func(items map[basket_item.UniqId]*basket_item.Item) error {
+				return internal.NewLogicErrorWithMsg(
+					fmt.Errorf("configuration already has max count of item 1"),
+					"В конфигурации не может быть больше 10 шт. одного товара.",
+				)
+			},
+			check: func(t *testing.T, items map[basket_item.UniqId]*basket_item.Item) {
+				assert.Len(t, items, 3)
+				for _, item := range items {
+					if item.Type() == basket_item.TypeProduct {
+						assert.Equal(t, 2, item.Count())
+						assert.Empty(t, item.Infos())
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		ctrl := gomock.NewController(t)
+
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		sqlxDB := sqlx.NewDb(db, "sqlmock")
 
 		productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
 
@@ -798,17 +1441,26 @@ func TestConfiguration_MoveItemIn(t *testing.T) {
 			conf := &Configuration{
 				basket:        tt.fields.bsk,
 				productClient: productApiMock,
-				db:            sqlxDB,
+				registry:      NewMssqlConfigurationRegistry(sqlxDB),
 			}
 			items := tt.basketItems()
-			conf.basket.data.items = items
+			conf.basket.data.items = make(map[basket_item.UniqId]*basket_item.Item, len(items))
+			for _, item := range items {
+				conf.basket.data.items[item.UniqId()] = item
+			}
 
-			err := conf.MoveItemIn(items["1"].UniqId())
+			err := conf.MoveItemIn(items[tt.confKey].UniqId(), items["1"].UniqId())
 			if tt.wantErr {
 				assert.Error(t, err, "")
 				if tt.err != nil {
-					assert.EqualError(t, err, tt.err.Error())
+					assert.EqualError(t, err, tt.err(items).Error())
 				}
+			} else {
+				assert.Nil(t, err)
+			}
+
+			if tt.check != nil {
+				tt.check(t, conf.basket.data.items)
 			}
 		})
 		db.Close()
@@ -824,6 +1476,7 @@ func TestConfiguration_Assemble(t *testing.T) {
 		bsk           *Basket
 		productClient productv1.ProductAPIClient
 		db            database.DB
+		confOptions   *ConfigurationOptions
 	}
 
 	itemsTemplateMap := make(map[basket_item.UniqId]*basket_item.Item)
@@ -837,6 +1490,16 @@ func TestConfiguration_Assemble(t *testing.T) {
 		"11", basket_item.TypeConfiguration, "n", "image", 1, 1, 0,
 		"msk_cl", catalog_types.PriceColumn(1),
 	)
+	confProduct := basket_item.NewItem(
+		"12", basket_item.TypeConfigurationProduct, "n", "image", 1, 1, 0,
+		"msk_cl", catalog_types.PriceColumn(1),
+	)
+	_ = itemsMapConf["11"].AddChild(confProduct)
+	itemsMapConf["12"] = confProduct
+	itemsMapConf["50"] = basket_item.NewItem(
+		"11", "t", "", "", 1, 1, 0,
+		"msk_cl", catalog_types.PriceColumn(1),
+	)
 
 	itemsProductMap := make(map[basket_item.UniqId]*basket_item.Item)
 	item := basket_item.NewItem(
@@ -849,6 +1512,14 @@ func TestConfiguration_Assemble(t *testing.T) {
 		catalog_types.CategoryId(12), []catalog_types.CreditProgram{""}, 1, 1,
 	))
 
+	reducedItem := basket_item.NewItem(
+		"11", basket_item.TypeProduct, "", "", 3, 1, 0,
+		"msk_cl", catalog_types.PriceColumn(1),
+	)
+	reducedItem.Additions().SetProduct(basket_item.NewProductItemAdditions(
+		catalog_types.CategoryId(12), []catalog_types.CreditProgram{""}, 1, 1,
+	))
+
 	type args struct {
 		ctx                   context.Context
 		assemblyServiceItemId string
@@ -863,9 +1534,10 @@ func TestConfiguration_Assemble(t *testing.T) {
 		prepare func(m *mocks, dbMock sqlmock.Sqlmock)
 		err     error
 		mocks   func(ctrl *gomock.Controller) *mocks
+		check   func(t *testing.T, bsk *Basket)
 	}{
 		{
-			name: "configuration already exists",
+			name: "items of existing configuration are not sent to configurator",
 			args: args{
 				ctx:                   context.Background(),
 				assemblyServiceItemId: "11",
@@ -886,7 +1558,14 @@ func TestConfiguration_Assemble(t *testing.T) {
 					},
 				},
 			},
-			err: fmt.Errorf("configuration already exists in basket"),
+			prepare: func(m *mocks, dbMock sqlmock.Sqlmock) {
+				dbMock.ExpectQuery("Configurator.set_temporary_configuration").
+					WithArgs(
+						sql.Named("conf_id", basket_item.DefaultConfId),
+						sql.Named("item_list", "<items><item><id>11</id><quantity>1</quantity></item></items>")).
+					WillReturnError(errors.New("test error"))
+			},
+			err: fmt.Errorf("can't register temporary configuration: error on query from db: test error"),
 			mocks: func(ctrl *gomock.Controller) *mocks {
 				return &mocks{
 					productApiMock: productmockv1.NewMockProductAPIClient(ctrl),
@@ -927,7 +1606,7 @@ func TestConfiguration_Assemble(t *testing.T) {
 					productApiMock: productmockv1.NewMockProductAPIClient(ctrl),
 				}
 			},
-			err: fmt.Errorf("error on query from db: test error"),
+			err: fmt.Errorf("can't register temporary configuration: error on query from db: test error"),
 		},
 		{
 			name: "struct scan error",
@@ -962,7 +1641,8 @@ func TestConfiguration_Assemble(t *testing.T) {
 							AddRow("11", "not bool", 1),
 					)
 			},
-			err: fmt.Errorf("error on scanned row: sql: Scan error on column index 1, name \"compatible\":" +
+			err: fmt.Errorf("can't register temporary configuration: error on scanned row: " +
+				"sql: Scan error on column index 1, name \"compatible\":" +
 				" sql/driver: couldn't convert \"not bool\" into type bool"),
 			mocks: func(ctrl *gomock.Controller) *mocks {
 				return &mocks{
@@ -1222,6 +1902,213 @@ func TestConfiguration_Assemble(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "count of changeable item is reduced",
+			args: args{
+				ctx:                   context.Background(),
+				assemblyServiceItemId: "11",
+				confItems: []*basket_item.ConfItem{
+					{
+						ProductId: catalog_types.ProductId("11"),
+						Services:  []*basket_item.ConfItemService{{ItemId: "id"}},
+					},
+				},
+				count: 1,
+			},
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+						items:       map[basket_item.UniqId]*basket_item.Item{reducedItem.UniqId(): reducedItem},
+					},
+				},
+			},
+			prepare: func(m *mocks, dbMock sqlmock.Sqlmock) {
+				priceMap := make(map[int32]*overallv1.Price)
+				priceMap[1] = &overallv1.Price{
+					Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL,
+					Price:  500,
+				}
+
+				dbMock.ExpectQuery("Configurator.set_temporary_configuration").
+					WithArgs(
+						sql.Named("conf_id", basket_item.DefaultConfId),
+						sql.Named("item_list", "<items><item><id>11</id><quantity>1</quantity></item></items>")).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"conf_id", "compatible", "assembly_type_id"}).
+							AddRow("11", true, 1),
+					)
+
+				m.productApiMock.EXPECT().FindFull(context.Background(), &productv1.FindFullRequest{
+					Ids:     []string{"11"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{
+						{
+							Id: "11",
+							Price: &productv1.ProductPriceByRegion{
+								ProductId: "11",
+								Prices:    priceMap,
+							},
+							Regional: &productv1.ProductRegional{
+								CreditPrograms: []string{"1"},
+							},
+						},
+					},
+				}, nil)
+			},
+			err: nil,
+			mocks: func(ctrl *gomock.Controller) *mocks {
+				return &mocks{
+					productApiMock: productmockv1.NewMockProductAPIClient(ctrl),
+				}
+			},
+			check: func(t *testing.T, bsk *Basket) {
+				assert.Equal(t, 2, reducedItem.Count())
+				assert.Contains(t, withoutAuditTime(bsk.data.AuditTail()), NewAuditEntry(
+					time.Time{}, AuditActorUser, AuditOperationChangeCount, reducedItem.UniqId(), "11", 3, 2, 1, 1,
+					AuditReasonUserRequest,
+				))
+			},
+		},
+		{
+			name: "incompatible by validator",
+			args: args{
+				ctx:                   context.Background(),
+				assemblyServiceItemId: "11",
+				confItems: []*basket_item.ConfItem{
+					{
+						ProductId: catalog_types.ProductId("100"),
+						Services:  []*basket_item.ConfItemService{{ItemId: "id"}},
+					},
+				},
+				count: 1,
+			},
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+						items:       itemsTemplateMap,
+					},
+				},
+				confOptions: NewConfigurationOptions(
+					NewConfigurationValidator(
+						nil,
+						NewCategoryCountRule(basket_item.PCCaseCategoryID, basket_item.MessageComponentPCCase, 1, 1),
+					),
+					false,
+				),
+			},
+			prepare: func(m *mocks, dbMock sqlmock.Sqlmock) {
+				priceMap := make(map[int32]*overallv1.Price)
+				priceMap[1] = &overallv1.Price{
+					Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL,
+					Price:  500,
+				}
+
+				dbMock.ExpectQuery("Configurator.set_temporary_configuration").
+					WithArgs(
+						sql.Named("conf_id", basket_item.DefaultConfId),
+						sql.Named("item_list", "<items><item><id>11</id><quantity>1</quantity></item></items>")).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"conf_id", "compatible", "assembly_type_id"}).
+							AddRow("11", false, 1),
+					)
+
+				m.productApiMock.EXPECT().FindFull(context.Background(), &productv1.FindFullRequest{
+					Ids:     []string{"100"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{
+						{
+							Id: "100",
+							Price: &productv1.ProductPriceByRegion{
+								ProductId: "100",
+								Prices:    priceMap,
+							},
+						},
+					},
+				}, nil)
+			},
+			err: fmt.Errorf("invalid configuration: в конфигурации должно быть не меньше 1 шт. комплектующей «корпус»"),
+			mocks: func(ctrl *gomock.Controller) *mocks {
+				return &mocks{
+					productApiMock: productmockv1.NewMockProductAPIClient(ctrl),
+				}
+			},
+		},
+		{
+			name: "incompatible by registry, but registry check is disabled",
+			args: args{
+				ctx:                   context.Background(),
+				assemblyServiceItemId: "11",
+				confItems: []*basket_item.ConfItem{
+					{
+						ProductId: catalog_types.ProductId("100"),
+						Services:  []*basket_item.ConfItemService{{ItemId: "id"}},
+					},
+				},
+				count: 1,
+			},
+			fields: fields{
+				bsk: &Basket{
+					data: &BasketData{
+						spaceId:     "msk_cl",
+						priceColumn: catalog_types.PriceColumnRetail,
+						items:       itemsTemplateMap,
+					},
+				},
+				confOptions: NewConfigurationOptions(
+					NewConfigurationValidator(
+						nil,
+						NewCategoryCountRule(basket_item.PCCaseCategoryID, basket_item.MessageComponentPCCase, 0, 1),
+					),
+					false,
+				),
+			},
+			prepare: func(m *mocks, dbMock sqlmock.Sqlmock) {
+				priceMap := make(map[int32]*overallv1.Price)
+				priceMap[1] = &overallv1.Price{
+					Column: overallv1.PriceColumn_PRICE_COLUMN_RETAIL,
+					Price:  500,
+				}
+
+				dbMock.ExpectQuery("Configurator.set_temporary_configuration").
+					WithArgs(
+						sql.Named("conf_id", basket_item.DefaultConfId),
+						sql.Named("item_list", "<items><item><id>11</id><quantity>1</quantity></item></items>")).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"conf_id", "compatible", "assembly_type_id"}).
+							AddRow("11", false, 1),
+					)
+
+				m.productApiMock.EXPECT().FindFull(context.Background(), &productv1.FindFullRequest{
+					Ids:     []string{"100"},
+					SpaceId: "msk_cl",
+				}).Return(&productv1.FindFullResponse{
+					Infos: []*productv1.FindFullResponse_FullInfo{
+						{
+							Id: "100",
+							Price: &productv1.ProductPriceByRegion{
+								ProductId: "100",
+								Prices:    priceMap,
+							},
+						},
+					},
+				}, nil)
+			},
+			err: nil,
+			mocks: func(ctrl *gomock.Controller) *mocks {
+				return &mocks{
+					productApiMock: productmockv1.NewMockProductAPIClient(ctrl),
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		ctrl := gomock.NewController(t)
@@ -1242,14 +2129,113 @@ func TestConfiguration_Assemble(t *testing.T) {
 			conf := &Configuration{
 				basket:        tt.fields.bsk,
 				productClient: mocks.productApiMock,
-				db:            sqlxDB,
+				registry:      NewMssqlConfigurationRegistry(sqlxDB),
+				confOptions:   tt.fields.confOptions,
 			}
 
-			err := conf.Assemble(tt.args.ctx, tt.args.assemblyServiceItemId, tt.args.confItems, tt.args.count)
+			_, err := conf.Assemble(tt.args.ctx, tt.args.assemblyServiceItemId, tt.args.confItems, tt.args.count, "")
 			if tt.err != nil {
 				assert.EqualError(t, err, tt.err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.check != nil {
+				tt.check(t, tt.fields.bsk)
 			}
 		})
 		db.Close()
 	}
 }
+
+func TestConfiguration_FindByConfId(t *testing.T) {
+	firstConf := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	firstConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("1", basket_item.ConfTypeUser))
+	secondConf := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	secondConf.Additions().SetConfiguration(basket_item.NewConfiguratorItemAdditions("2", basket_item.ConfTypeTemplate))
+	confWithoutAdditions := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+
+	conf := &Configuration{
+		basket: &Basket{
+			data: &BasketData{
+				items: map[basket_item.UniqId]*basket_item.Item{
+					firstConf.UniqId():            firstConf,
+					secondConf.UniqId():           secondConf,
+					confWithoutAdditions.UniqId(): confWithoutAdditions,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		confId basket_item.ConfId
+		want   *basket_item.Item
+	}{
+		{
+			name:   "first configuration",
+			confId: "1",
+			want:   firstConf,
+		},
+		{
+			name:   "second configuration",
+			confId: "2",
+			want:   secondConf,
+		},
+		{
+			name:   "not found",
+			confId: "3",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, conf.FindByConfId(tt.confId))
+		})
+	}
+}
+
+func TestConfiguration_FindOne(t *testing.T) {
+	confItem := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	productItem := basket_item.NewItem("1", basket_item.TypeProduct, "", "", 1, 1, 0, "msk_cl", 0)
+
+	conf := &Configuration{
+		basket: &Basket{
+			data: &BasketData{
+				items: map[basket_item.UniqId]*basket_item.Item{
+					confItem.UniqId():    confItem,
+					productItem.UniqId(): productItem,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		uniqId  basket_item.UniqId
+		want    *basket_item.Item
+		wantErr error
+	}{
+		{
+			name:   "configuration",
+			uniqId: confItem.UniqId(),
+			want:   confItem,
+		},
+		{
+			name:    "not configuration",
+			uniqId:  productItem.UniqId(),
+			wantErr: internal.NewNotFoundError(fmt.Errorf("configuration '%s' not found in basket", productItem.UniqId())),
+		},
+		{
+			name:    "not found",
+			uniqId:  "not_found",
+			wantErr: internal.NewNotFoundError(fmt.Errorf("configuration 'not_found' not found in basket")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := conf.FindOne(tt.uniqId)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package basket
+
+import (
+	"context"
+	"fmt"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/store_types"
+	"strings"
+)
+
+//go:generate mockgen -source=configuration_validator.go -destination=configuration_validator_mock.go -package=basket
+
+// ConfigurationProperty свойство комплектующей, которое используется в правилах совместимости
+type ConfigurationProperty string
+
+const (
+	// ConfigurationPropertySocket сокет процессора/материнской платы
+	ConfigurationPropertySocket ConfigurationProperty = "socket"
+)
+
+// ConfigurationComponent комплектующая конфигурации, над которой работают правила совместимости
+type ConfigurationComponent struct {
+	Item       *basket_item.Item
+	CategoryId catalog_types.CategoryId
+	// Кол-во комплектующей в одной сборке
+	Count      int
+	Properties map[ConfigurationProperty]string
+}
+
+// ConfigurationIncompatibility нарушение правила совместимости
+type ConfigurationIncompatibility struct {
+	MessageKey    basket_item.MessageKey
+	MessageParams []*basket_item.MessageParam
+	// Комплектующие, которые нарушают правило. Может быть пустым, если нарушение связано с отсутствием комплектующей
+	ItemIds []basket_item.ItemId
+}
+
+// ConfigurationRule правило совместимости комплектующих конфигурации
+type ConfigurationRule interface {
+	Check(components []*ConfigurationComponent) []*ConfigurationIncompatibility
+}
+
+// ConfigurationPropertiesProvider источник свойств комплектующих (например, каталог)
+type ConfigurationPropertiesProvider interface {
+	Properties(
+		ctx context.Context,
+		spaceId store_types.SpaceId,
+		itemIds []basket_item.ItemId,
+	) (map[basket_item.ItemId]map[ConfigurationProperty]string, error)
+}
+
+// ConfigurationCategories категории комплектующих, которые используются в стандартных правилах совместимости
+type ConfigurationCategories struct {
+	PCCase      catalog_types.CategoryId
+	Motherboard catalog_types.CategoryId
+	CPU         catalog_types.CategoryId
+}
+
+// DefaultConfigurationRules стандартный набор правил совместимости: ровно один корпус, ровно одна материнская плата и
+// совпадение сокета процессора и материнской платы. Правила для незаданных категорий не добавляются
+func DefaultConfigurationRules(categories ConfigurationCategories) []ConfigurationRule {
+	var rules []ConfigurationRule
+	if categories.PCCase != 0 {
+		rules = append(rules, NewCategoryCountRule(categories.PCCase, basket_item.MessageComponentPCCase, 1, 1))
+	}
+
+	if categories.Motherboard != 0 {
+		rules = append(rules, NewCategoryCountRule(
+			categories.Motherboard,
+			basket_item.MessageComponentMotherboard,
+			1,
+			1,
+		))
+	}
+
+	if categories.CPU != 0 && categories.Motherboard != 0 {
+		rules = append(rules, NewPropertyMatchRule(
+			ConfigurationPropertySocket,
+			categories.CPU,
+			categories.Motherboard,
+			basket_item.MessageConfigurationSocketMismatch,
+		))
+	}
+
+	return rules
+}
+
+// ConfigurationValidator проверяет совместимость комплектующих конфигурации по набору правил
+type ConfigurationValidator struct {
+	propertiesProvider ConfigurationPropertiesProvider
+	rules              []ConfigurationRule
+}
+
+// NewConfigurationValidator создает валидатор. Источник свойств может быть nil, тогда правила, которые опираются на
+// свойства комплектующих, не найдут нарушений
+func NewConfigurationValidator(
+	propertiesProvider ConfigurationPropertiesProvider,
+	rules ...ConfigurationRule,
+) *ConfigurationValidator {
+	return &ConfigurationValidator{propertiesProvider: propertiesProvider, rules: rules}
+}
+
+// Validate проверяет комплектующие конфигурации и возвращает проблемы, по одной на каждое нарушенное правило.
+// Проверяются только позиции с типом "товар в конфигурации", остальные позиции игнорируются
+func (v *ConfigurationValidator) Validate(
+	ctx context.Context,
+	spaceId store_types.SpaceId,
+	items []*basket_item.Item,
+) ([]*basket_item.Problem, error) {
+	components := make([]*ConfigurationComponent, 0, len(items))
+	itemIds := make([]basket_item.ItemId, 0, len(items))
+	for _, item := range items {
+		if item.Type() != basket_item.TypeConfigurationProduct {
+			continue
+		}
+
+		component := &ConfigurationComponent{
+			Item:  item,
+			Count: item.Count(),
+		}
+		if item.Additions().GetProduct() != nil {
+			component.CategoryId = item.Additions().GetProduct().CategoryId()
+		}
+
+		components = append(components, component)
+		itemIds = append(itemIds, item.ItemId())
+	}
+
+	if v.propertiesProvider != nil && len(itemIds) > 0 {
+		properties, err := v.propertiesProvider.Properties(ctx, spaceId, itemIds)
+		if err != nil {
+			return nil, fmt.Errorf("can't get properties of configuration components: %w", err)
+		}
+
+		for _, component := range components {
+			component.Properties = properties[component.Item.ItemId()]
+		}
+	}
+
+	var problems []*basket_item.Problem
+	for _, rule := range v.rules {
+		for _, incompatibility := range rule.Check(components) {
+			problem := basket_item.NewLocalizedProblem(
+				basket_item.ProblemConfigurationIncompatible,
+				incompatibility.MessageKey,
+				incompatibility.MessageParams...,
+			)
+			problem.Additions().ConfigurationProblemAdditions = basket_item.ConfigurationProblemAdditions{
+				IncompatibleProductItemIds: incompatibility.ItemIds,
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	return problems, nil
+}
+
+// categoryCountRule ограничивает кол-во комплектующих одной категории в сборке
+type categoryCountRule struct {
+	categoryId catalog_types.CategoryId
+	name       basket_item.MessageKey
+	min        int
+	max        int
+}
+
+// NewCategoryCountRule создает правило, по которому в сборке должно быть от min до max комплектующих категории.
+// name - ключ названия комплектующей в каталоге переводов. Если max равен 0, то верхняя граница не проверяется
+func NewCategoryCountRule(
+	categoryId catalog_types.CategoryId,
+	name basket_item.MessageKey,
+	min int,
+	max int,
+) ConfigurationRule {
+	return &categoryCountRule{categoryId: categoryId, name: name, min: min, max: max}
+}
+
+func (r *categoryCountRule) Check(components []*ConfigurationComponent) []*ConfigurationIncompatibility {
+	count := 0
+	var itemIds []basket_item.ItemId
+	for _, component := range components {
+		if component.CategoryId != r.categoryId {
+			continue
+		}
+
+		count += component.Count
+		itemIds = append(itemIds, component.Item.ItemId())
+	}
+
+	if count < r.min {
+		return []*ConfigurationIncompatibility{{
+			MessageKey:    basket_item.MessageConfigurationComponentMinCount,
+			MessageParams: r.messageParams(r.min),
+			ItemIds:       itemIds,
+		}}
+	}
+
+	if r.max > 0 && count > r.max {
+		return []*ConfigurationIncompatibility{{
+			MessageKey:    basket_item.MessageConfigurationComponentMaxCount,
+			MessageParams: r.messageParams(r.max),
+			ItemIds:       itemIds,
+		}}
+	}
+
+	return nil
+}
+
+func (r *categoryCountRule) messageParams(count int) []*basket_item.MessageParam {
+	return []*basket_item.MessageParam{
+		basket_item.NewIntMessageParam("count", count),
+		basket_item.NewKeyMessageParam("component", r.name),
+	}
+}
+
+// propertyMatchRule требует совпадения свойства у комплектующих двух категорий
+type propertyMatchRule struct {
+	property         ConfigurationProperty
+	firstCategoryId  catalog_types.CategoryId
+	secondCategoryId catalog_types.CategoryId
+	message          basket_item.MessageKey
+}
+
+// NewPropertyMatchRule создает правило, по которому свойство комплектующих первой категории должно совпадать со
+// свойством комплектующих второй категории. Если свойство у комплектующей неизвестно, то она не проверяется
+func NewPropertyMatchRule(
+	property ConfigurationProperty,
+	firstCategoryId catalog_types.CategoryId,
+	secondCategoryId catalog_types.CategoryId,
+	message basket_item.MessageKey,
+) ConfigurationRule {
+	return &propertyMatchRule{
+		property:         property,
+		firstCategoryId:  firstCategoryId,
+		secondCategoryId: secondCategoryId,
+		message:          message,
+	}
+}
+
+func (r *propertyMatchRule) Check(components []*ConfigurationComponent) []*ConfigurationIncompatibility {
+	var incompatibilities []*ConfigurationIncompatibility
+	for _, first := range components {
+		firstValue := first.Properties[r.property]
+		if first.CategoryId != r.firstCategoryId || firstValue == "" {
+			continue
+		}
+
+		for _, second := range components {
+			secondValue := second.Properties[r.property]
+			if second.CategoryId != r.secondCategoryId || secondValue == "" {
+				continue
+			}
+
+			if !strings.EqualFold(strings.TrimSpace(firstValue), strings.TrimSpace(secondValue)) {
+				incompatibilities = append(incompatibilities, &ConfigurationIncompatibility{
+					MessageKey: r.message,
+					ItemIds:    []basket_item.ItemId{first.Item.ItemId(), second.Item.ItemId()},
+				})
+			}
+		}
+	}
+
+	return incompatibilities
+}
+
+// IncompatibleConfigurationError ошибка несовместимости комплектующих конфигурации. Содержит проблемы по каждому
+// нарушенному правилу
+type IncompatibleConfigurationError struct {
+	problems []*basket_item.Problem
+}
+
+func NewIncompatibleConfigurationError(problems []*basket_item.Problem) *IncompatibleConfigurationError {
+	return &IncompatibleConfigurationError{problems: problems}
+}
+
+func (e *IncompatibleConfigurationError) Error() string {
+	messages := make([]string, 0, len(e.problems))
+	for _, problem := range e.problems {
+		messages = append(messages, problem.Message())
+	}
+
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(messages, "; "))
+}
+
+// Problems возвращает проблемы по каждому нарушенному правилу совместимости
+func (e *IncompatibleConfigurationError) Problems() []*basket_item.Problem {
+	return e.problems
+}
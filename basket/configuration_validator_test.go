@@ -0,0 +1,295 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"go.citilink.cloud/store_types"
+	"testing"
+)
+
+const (
+	testMotherboardCategoryId catalog_types.CategoryId = 10
+	testCPUCategoryId         catalog_types.CategoryId = 20
+)
+
+func newTestConfigurationProduct(itemId basket_item.ItemId, categoryId catalog_types.CategoryId, count int) *basket_item.Item {
+	item := basket_item.NewItem(
+		itemId, basket_item.TypeConfigurationProduct, "", "", count, 100, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	item.Additions().SetProduct(basket_item.NewProductItemAdditions(categoryId, nil, 0, 0))
+
+	return item
+}
+
+func TestCategoryCountRule_Check(t *testing.T) {
+	pcCase := newTestConfigurationProduct("1", basket_item.PCCaseCategoryID, 1)
+	secondPCCase := newTestConfigurationProduct("2", basket_item.PCCaseCategoryID, 1)
+	cpu := newTestConfigurationProduct("3", testCPUCategoryId, 1)
+
+	tests := []struct {
+		name       string
+		components []*ConfigurationComponent
+		want       []*ConfigurationIncompatibility
+	}{
+		{
+			name: "exactly one",
+			components: []*ConfigurationComponent{
+				{Item: pcCase, CategoryId: basket_item.PCCaseCategoryID, Count: 1},
+				{Item: cpu, CategoryId: testCPUCategoryId, Count: 1},
+			},
+			want: nil,
+		},
+		{
+			name: "missing",
+			components: []*ConfigurationComponent{
+				{Item: cpu, CategoryId: testCPUCategoryId, Count: 1},
+			},
+			want: []*ConfigurationIncompatibility{
+				{
+					MessageKey: basket_item.MessageConfigurationComponentMinCount,
+					MessageParams: []*basket_item.MessageParam{
+						basket_item.NewIntMessageParam("count", 1),
+						basket_item.NewKeyMessageParam("component", basket_item.MessageComponentPCCase),
+					},
+				},
+			},
+		},
+		{
+			name: "too many by positions",
+			components: []*ConfigurationComponent{
+				{Item: pcCase, CategoryId: basket_item.PCCaseCategoryID, Count: 1},
+				{Item: secondPCCase, CategoryId: basket_item.PCCaseCategoryID, Count: 1},
+			},
+			want: []*ConfigurationIncompatibility{
+				{
+					MessageKey: basket_item.MessageConfigurationComponentMaxCount,
+					MessageParams: []*basket_item.MessageParam{
+						basket_item.NewIntMessageParam("count", 1),
+						basket_item.NewKeyMessageParam("component", basket_item.MessageComponentPCCase),
+					},
+					ItemIds: []basket_item.ItemId{"1", "2"},
+				},
+			},
+		},
+		{
+			name: "too many by count",
+			components: []*ConfigurationComponent{
+				{Item: pcCase, CategoryId: basket_item.PCCaseCategoryID, Count: 2},
+			},
+			want: []*ConfigurationIncompatibility{
+				{
+					MessageKey: basket_item.MessageConfigurationComponentMaxCount,
+					MessageParams: []*basket_item.MessageParam{
+						basket_item.NewIntMessageParam("count", 1),
+						basket_item.NewKeyMessageParam("component", basket_item.MessageComponentPCCase),
+					},
+					ItemIds: []basket_item.ItemId{"1"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewCategoryCountRule(basket_item.PCCaseCategoryID, basket_item.MessageComponentPCCase, 1, 1)
+			assert.Equal(t, tt.want, rule.Check(tt.components))
+		})
+	}
+}
+
+func TestPropertyMatchRule_Check(t *testing.T) {
+	motherboard := newTestConfigurationProduct("1", testMotherboardCategoryId, 1)
+	cpu := newTestConfigurationProduct("2", testCPUCategoryId, 1)
+
+	tests := []struct {
+		name       string
+		components []*ConfigurationComponent
+		want       []*ConfigurationIncompatibility
+	}{
+		{
+			name: "match",
+			components: []*ConfigurationComponent{
+				{
+					Item:       cpu,
+					CategoryId: testCPUCategoryId,
+					Properties: map[ConfigurationProperty]string{ConfigurationPropertySocket: "LGA1700"},
+				},
+				{
+					Item:       motherboard,
+					CategoryId: testMotherboardCategoryId,
+					Properties: map[ConfigurationProperty]string{ConfigurationPropertySocket: " lga1700"},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "mismatch",
+			components: []*ConfigurationComponent{
+				{
+					Item:       cpu,
+					CategoryId: testCPUCategoryId,
+					Properties: map[ConfigurationProperty]string{ConfigurationPropertySocket: "AM5"},
+				},
+				{
+					Item:       motherboard,
+					CategoryId: testMotherboardCategoryId,
+					Properties: map[ConfigurationProperty]string{ConfigurationPropertySocket: "LGA1700"},
+				},
+			},
+			want: []*ConfigurationIncompatibility{
+				{
+					MessageKey: basket_item.MessageConfigurationSocketMismatch,
+					ItemIds:    []basket_item.ItemId{"2", "1"},
+				},
+			},
+		},
+		{
+			name: "unknown property",
+			components: []*ConfigurationComponent{
+				{Item: cpu, CategoryId: testCPUCategoryId},
+				{
+					Item:       motherboard,
+					CategoryId: testMotherboardCategoryId,
+					Properties: map[ConfigurationProperty]string{ConfigurationPropertySocket: "LGA1700"},
+				},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewPropertyMatchRule(
+				ConfigurationPropertySocket,
+				testCPUCategoryId,
+				testMotherboardCategoryId,
+				basket_item.MessageConfigurationSocketMismatch,
+			)
+			assert.Equal(t, tt.want, rule.Check(tt.components))
+		})
+	}
+}
+
+func TestDefaultConfigurationRules(t *testing.T) {
+	assert.Len(t, DefaultConfigurationRules(ConfigurationCategories{PCCase: basket_item.PCCaseCategoryID}), 1)
+	assert.Len(t, DefaultConfigurationRules(ConfigurationCategories{
+		PCCase:      basket_item.PCCaseCategoryID,
+		Motherboard: testMotherboardCategoryId,
+		CPU:         testCPUCategoryId,
+	}), 3)
+}
+
+func TestConfigurationValidator_Validate(t *testing.T) {
+	type mocks struct {
+		propertiesProvider *MockConfigurationPropertiesProvider
+	}
+
+	configurationItem := basket_item.NewConfigurationItem(0, "msk_cl", catalog_types.PriceColumnRetail)
+	pcCase := newTestConfigurationProduct("1", basket_item.PCCaseCategoryID, 1)
+	motherboard := newTestConfigurationProduct("2", testMotherboardCategoryId, 1)
+	cpu := newTestConfigurationProduct("3", testCPUCategoryId, 1)
+	items := []*basket_item.Item{configurationItem, pcCase, motherboard, cpu}
+
+	tests := []struct {
+		name         string
+		prepare      func(m *mocks)
+		wantProblems func() []*basket_item.Problem
+		wantErr      error
+	}{
+		{
+			name: "compatible",
+			prepare: func(m *mocks) {
+				m.propertiesProvider.EXPECT().
+					Properties(gomock.Any(), store_types.SpaceId("msk_cl"), []basket_item.ItemId{"1", "2", "3"}).
+					Return(map[basket_item.ItemId]map[ConfigurationProperty]string{
+						"2": {ConfigurationPropertySocket: "AM5"},
+						"3": {ConfigurationPropertySocket: "AM5"},
+					}, nil)
+			},
+			wantProblems: func() []*basket_item.Problem {
+				return nil
+			},
+		},
+		{
+			name: "socket mismatch",
+			prepare: func(m *mocks) {
+				m.propertiesProvider.EXPECT().
+					Properties(gomock.Any(), store_types.SpaceId("msk_cl"), []basket_item.ItemId{"1", "2", "3"}).
+					Return(map[basket_item.ItemId]map[ConfigurationProperty]string{
+						"2": {ConfigurationPropertySocket: "AM4"},
+						"3": {ConfigurationPropertySocket: "AM5"},
+					}, nil)
+			},
+			wantProblems: func() []*basket_item.Problem {
+				problem := basket_item.NewLocalizedProblem(
+					basket_item.ProblemConfigurationIncompatible,
+					basket_item.MessageConfigurationSocketMismatch,
+				)
+				problem.Additions().ConfigurationProblemAdditions = basket_item.ConfigurationProblemAdditions{
+					IncompatibleProductItemIds: []basket_item.ItemId{"3", "2"},
+				}
+
+				return []*basket_item.Problem{problem}
+			},
+		},
+		{
+			name: "properties provider error",
+			prepare: func(m *mocks) {
+				m.propertiesProvider.EXPECT().
+					Properties(gomock.Any(), store_types.SpaceId("msk_cl"), []basket_item.ItemId{"1", "2", "3"}).
+					Return(nil, errors.New("some error"))
+			},
+			wantProblems: func() []*basket_item.Problem {
+				return nil
+			},
+			wantErr: fmt.Errorf("can't get properties of configuration components: %w", errors.New("some error")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := &mocks{propertiesProvider: NewMockConfigurationPropertiesProvider(ctrl)}
+			tt.prepare(m)
+
+			validator := NewConfigurationValidator(m.propertiesProvider, DefaultConfigurationRules(ConfigurationCategories{
+				PCCase:      basket_item.PCCaseCategoryID,
+				Motherboard: testMotherboardCategoryId,
+				CPU:         testCPUCategoryId,
+			})...)
+
+			problems, err := validator.Validate(context.Background(), "msk_cl", items)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantProblems(), problems)
+		})
+	}
+}
+
+func TestConfigurationValidator_ValidateWithoutPropertiesProvider(t *testing.T) {
+	validator := NewConfigurationValidator(
+		nil,
+		NewCategoryCountRule(testMotherboardCategoryId, basket_item.MessageComponentMotherboard, 1, 1),
+	)
+
+	problems, err := validator.Validate(context.Background(), "msk_cl", []*basket_item.Item{
+		newTestConfigurationProduct("1", basket_item.PCCaseCategoryID, 1),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, problems, 1)
+	assert.Equal(t, basket_item.ProblemConfigurationIncompatible, problems[0].Id())
+	assert.Empty(t, problems[0].Additions().ConfigurationProblemAdditions.IncompatibleProductItemIds)
+	assert.Equal(t, "в конфигурации должно быть не меньше 1 шт. комплектующей «материнская плата»", problems[0].Message())
+}
+
+func TestIncompatibleConfigurationError_Error(t *testing.T) {
+	err := NewIncompatibleConfigurationError([]*basket_item.Problem{
+		basket_item.NewProblem(basket_item.ProblemConfigurationIncompatible, "first"),
+		basket_item.NewProblem(basket_item.ProblemConfigurationIncompatible, "second"),
+	})
+
+	assert.EqualError(t, err, "invalid configuration: first; second")
+	assert.Len(t, err.Problems(), 2)
+}
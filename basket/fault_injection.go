@@ -0,0 +1,318 @@
+package basket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultScenarioId сценарий внедрения сбоев, используется QA для воспроизведения проблем корзины
+type FaultScenarioId string
+
+const (
+	// FaultScenarioCatalogTimeout каталог не отвечает при получении данных товаров
+	FaultScenarioCatalogTimeout FaultScenarioId = "catalog_timeout"
+	// FaultScenarioPriceJump цены товаров выросли на 15%
+	FaultScenarioPriceJump FaultScenarioId = "price_jump"
+	// FaultScenarioItemVanished товары пропали из каталога региона
+	FaultScenarioItemVanished FaultScenarioId = "item_vanished"
+	// FaultScenarioPresentOutOfStock подарки закончились
+	FaultScenarioPresentOutOfStock FaultScenarioId = "present_out_of_stock"
+)
+
+// faultPriceJumpPercent на сколько процентов увеличиваются цены в сценарии FaultScenarioPriceJump
+const faultPriceJumpPercent = 15
+
+//go:generate go run ../tools/msgpackgen -output=fault_injection_msgpack.go -types=FaultScenario
+
+// FaultScenario сценарий внедрения сбоев, активированный для корзины. Сохраняется вместе с корзиной, поэтому сбои
+// воспроизводятся при каждом обращении к ней, пока сценарий не будет отменен
+type FaultScenario struct {
+	id FaultScenarioId `msgpackidx:"1,string"`
+	// идентификаторы позиций, к которым применяется сценарий. Пустой список означает все позиции корзины
+	itemIds []basket_item.ItemId `msgpackidx:"2,slice,elem=string"`
+}
+
+func NewFaultScenario(id FaultScenarioId, itemIds ...basket_item.ItemId) *FaultScenario {
+	return &FaultScenario{id: id, itemIds: itemIds}
+}
+
+func (s *FaultScenario) Id() FaultScenarioId {
+	return s.id
+}
+
+func (s *FaultScenario) ItemIds() []basket_item.ItemId {
+	return s.itemIds
+}
+
+// Covers проверяет, применяется ли сценарий к позиции с указанным идентификатором
+func (s *FaultScenario) Covers(itemId basket_item.ItemId) bool {
+	if len(s.itemIds) == 0 {
+		return true
+	}
+
+	for _, id := range s.itemIds {
+		if id == itemId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FaultScenarios сценарии внедрения сбоев, активированные для корзины
+func (b *BasketData) FaultScenarios() []*FaultScenario {
+	return b.faultScenarios
+}
+
+// FaultInjector искажения, которые вносит сценарий. Незаполненные искажения не применяются
+type FaultInjector struct {
+	// CallError ошибка, которую получает вызывающий код вместо ответа каталога на запрос данных товаров
+	CallError func(scenario *FaultScenario) error
+	// Product искажает данные товара из ответа каталога. Если возвращается false, товар пропадает из ответа
+	Product func(scenario *FaultScenario, info *productv1.FindFullResponse_FullInfo) bool
+	// Present возвращает false, если подарок не должен вернуться актуализатором
+	Present func(scenario *FaultScenario, aItem ActualizerItem) bool
+}
+
+// FaultScenarioRegistry реестр сценариев внедрения сбоев. Реестр оборачивает клиенты каталога и актуализатор, которые
+// используются при создании и обновлении позиций, и применяет к ним сценарии, активированные для корзины.
+//
+// Реестр передается в NewBasketFactory и обновления товаров и конфигураций, которые оборачивают им свои клиенты
+// каталога, а корзины, созданные фабрикой, оборачивают им данные актуализатора в Basket.Refresh. Фабрике товаров
+// передается уже обернутый клиент каталога. Реестр включается флагом конфигурации, выключенный реестр не оборачивает
+// клиенты и не позволяет активировать сценарии, поэтому в production, где внедрение сбоев не включено явно,
+// сценарии, сохраненные в корзине, ни на что не влияют
+type FaultScenarioRegistry struct {
+	enabled   bool
+	injectors map[FaultScenarioId]*FaultInjector
+}
+
+// NewFaultScenarioRegistry создает реестр со стандартными сценариями
+func NewFaultScenarioRegistry(enabled bool) *FaultScenarioRegistry {
+	r := &FaultScenarioRegistry{
+		enabled:   enabled,
+		injectors: make(map[FaultScenarioId]*FaultInjector),
+	}
+
+	r.Register(FaultScenarioCatalogTimeout, &FaultInjector{
+		CallError: func(scenario *FaultScenario) error {
+			return status.Error(codes.DeadlineExceeded, fmt.Sprintf("fault scenario '%s'", scenario.Id()))
+		},
+	})
+	r.Register(FaultScenarioPriceJump, &FaultInjector{
+		Product: func(_ *FaultScenario, info *productv1.FindFullResponse_FullInfo) bool {
+			for _, price := range info.GetPrice().GetPrices() {
+				price.Price += price.Price * faultPriceJumpPercent / 100
+			}
+
+			return true
+		},
+	})
+	r.Register(FaultScenarioItemVanished, &FaultInjector{
+		Product: func(_ *FaultScenario, _ *productv1.FindFullResponse_FullInfo) bool {
+			return false
+		},
+	})
+	r.Register(FaultScenarioPresentOutOfStock, &FaultInjector{
+		Present: func(_ *FaultScenario, _ ActualizerItem) bool {
+			return false
+		},
+	})
+
+	return r
+}
+
+// Register добавляет сценарий или заменяет искажения существующего
+func (r *FaultScenarioRegistry) Register(id FaultScenarioId, injector *FaultInjector) {
+	r.injectors[id] = injector
+}
+
+// Enabled включено ли внедрение сбоев
+func (r *FaultScenarioRegistry) Enabled() bool {
+	return r != nil && r.enabled
+}
+
+// Activate активирует сценарии для корзины, ранее активированные сценарии заменяются
+func (r *FaultScenarioRegistry) Activate(bsk *Basket, scenarios ...*FaultScenario) error {
+	if !r.Enabled() {
+		return errors.New("fault injection is disabled")
+	}
+
+	for _, scenario := range scenarios {
+		if _, ok := r.injectors[scenario.Id()]; !ok {
+			return internal.NewValidationError(fmt.Errorf("unknown fault scenario '%s'", scenario.Id()))
+		}
+	}
+
+	bsk.data.faultScenarios = scenarios
+
+	return nil
+}
+
+// Deactivate отменяет все сценарии корзины. Отмена возможна даже при выключенном реестре, чтобы очистить корзину от
+// сценариев, которые в ней сохранены
+func (r *FaultScenarioRegistry) Deactivate(bsk *Basket) {
+	bsk.data.faultScenarios = nil
+}
+
+// WrapProductClient оборачивает клиент каталога, через который обновляются и создаются позиции
+func (r *FaultScenarioRegistry) WrapProductClient(client productv1.ProductAPIClient) productv1.ProductAPIClient {
+	if !r.Enabled() {
+		return client
+	}
+
+	return &faultProductClient{ProductAPIClient: client, registry: r}
+}
+
+// WrapActualizerItems оборачивает данные актуализатора, передаваемые в Basket.Refresh
+func (r *FaultScenarioRegistry) WrapActualizerItems(bsk *Basket, actualizerItems ActualizerItems) ActualizerItems {
+	if !r.Enabled() || len(bsk.data.faultScenarios) == 0 {
+		return actualizerItems
+	}
+
+	items := &faultActualizerItems{ActualizerItems: actualizerItems, registry: r, scenarios: bsk.data.faultScenarios}
+	if presentChoices, ok := actualizerItems.(PresentChoiceActualizerItems); ok {
+		return &faultPresentChoiceActualizerItems{faultActualizerItems: items, presentChoices: presentChoices}
+	}
+
+	return items
+}
+
+// faultInjection сценарий корзины и его искажения
+type faultInjection struct {
+	scenario *FaultScenario
+	injector *FaultInjector
+}
+
+// injections возвращает искажения сценариев в порядке их активации, незарегистрированные сценарии пропускаются
+func (r *FaultScenarioRegistry) injections(scenarios []*FaultScenario) []faultInjection {
+	injections := make([]faultInjection, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		if injector, ok := r.injectors[scenario.Id()]; ok {
+			injections = append(injections, faultInjection{scenario: scenario, injector: injector})
+		}
+	}
+
+	return injections
+}
+
+type faultScenariosContextKey struct{}
+
+// withFaultScenarios передает сценарии корзины обернутым клиентам каталога
+func withFaultScenarios(ctx context.Context, scenarios []*FaultScenario) context.Context {
+	if len(scenarios) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, faultScenariosContextKey{}, scenarios)
+}
+
+func faultScenariosFromContext(ctx context.Context) []*FaultScenario {
+	scenarios, _ := ctx.Value(faultScenariosContextKey{}).([]*FaultScenario)
+
+	return scenarios
+}
+
+type faultProductClient struct {
+	productv1.ProductAPIClient
+	registry *FaultScenarioRegistry
+}
+
+func (c *faultProductClient) FindFull(
+	ctx context.Context,
+	in *productv1.FindFullRequest,
+	opts ...grpc.CallOption,
+) (*productv1.FindFullResponse, error) {
+	injections := c.registry.injections(faultScenariosFromContext(ctx))
+	for _, injection := range injections {
+		if injection.injector.CallError == nil {
+			continue
+		}
+
+		for _, id := range in.GetIds() {
+			if injection.scenario.Covers(basket_item.ItemId(id)) {
+				return nil, injection.injector.CallError(injection.scenario)
+			}
+		}
+	}
+
+	response, err := c.ProductAPIClient.FindFull(ctx, in, opts...)
+	if err != nil || len(injections) == 0 {
+		return response, err
+	}
+
+	infos := make([]*productv1.FindFullResponse_FullInfo, 0, len(response.GetInfos()))
+infosLoop:
+	for _, info := range response.GetInfos() {
+		for _, injection := range injections {
+			if injection.injector.Product != nil && injection.scenario.Covers(basket_item.ItemId(info.GetId())) &&
+				!injection.injector.Product(injection.scenario, info) {
+				continue infosLoop
+			}
+		}
+
+		infos = append(infos, info)
+	}
+	response.Infos = infos
+
+	return response, nil
+}
+
+type faultActualizerItems struct {
+	ActualizerItems
+	registry  *FaultScenarioRegistry
+	scenarios []*FaultScenario
+}
+
+func (i *faultActualizerItems) FindByItem(item *basket_item.Item) ActualizerItem {
+	aItem := i.ActualizerItems.FindByItem(item)
+	if aItem != nil && item.Type() == basket_item.TypePresent && !i.isAvailablePresent(aItem) {
+		// закончившийся подарок актуализатор не возвращает
+		return nil
+	}
+
+	return aItem
+}
+
+func (i *faultActualizerItems) FindByType(itemType basket_item.Type) []ActualizerItem {
+	aItems := i.ActualizerItems.FindByType(itemType)
+	if itemType != basket_item.TypePresent {
+		return aItems
+	}
+
+	available := make([]ActualizerItem, 0, len(aItems))
+	for _, aItem := range aItems {
+		if i.isAvailablePresent(aItem) {
+			available = append(available, aItem)
+		}
+	}
+
+	return available
+}
+
+func (i *faultActualizerItems) isAvailablePresent(aItem ActualizerItem) bool {
+	for _, injection := range i.registry.injections(i.scenarios) {
+		if injection.injector.Present != nil && injection.scenario.Covers(aItem.GetItemId()) &&
+			!injection.injector.Present(injection.scenario, aItem) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type faultPresentChoiceActualizerItems struct {
+	*faultActualizerItems
+	presentChoices PresentChoiceActualizerItems
+}
+
+func (i *faultPresentChoiceActualizerItems) FindPresentChoices(parent *basket_item.Item) []basket_item.ItemId {
+	return i.presentChoices.FindPresentChoices(parent)
+}
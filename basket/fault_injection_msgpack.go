@@ -0,0 +1,63 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+func (s *FaultScenario) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(2); err != nil {
+		return err
+	}
+
+	if err := e.EncodeString(string(s.id)); err != nil { // 1
+		return err
+	}
+	if err := e.EncodeArrayLen(len(s.itemIds)); err != nil { // 2
+		return err
+	}
+	for _, v := range s.itemIds {
+		if err := e.EncodeString(string(v)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *FaultScenario) DecodeMsgpack(d *msgpack.Decoder) error {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 0, "FaultScenario array len")
+	}
+
+	if length != 2 {
+		return internal.NewMsgPackDecodeError(fmt.Errorf("(basket.FaultScenario) incorrect len: %d", length), 0, "(basket.FaultScenario) incorrect len")
+	}
+
+	id, err := d.DecodeString() // 1
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 1, "FaultScenario id")
+	}
+	s.id = FaultScenarioId(id)
+
+	itemIdsLen, err := d.DecodeArrayLen() // 2
+	if err != nil {
+		return internal.NewMsgPackDecodeError(err, 2, "FaultScenario itemIds")
+	}
+	itemIds := make([]basket_item.ItemId, itemIdsLen)
+	for j := 0; j < itemIdsLen; j++ {
+		v, err := d.DecodeString()
+		if err != nil {
+			return internal.NewMsgPackDecodeError(err, 2, "FaultScenario itemIds")
+		}
+		itemIds[j] = basket_item.ItemId(v)
+	}
+	s.itemIds = itemIds
+
+	return nil
+}
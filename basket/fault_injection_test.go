@@ -0,0 +1,247 @@
+package basket
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	overallv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/overall/v1"
+	productv1 "go.citilink.cloud/order/internal/specs/grpcclient/gen/citilink/catalog/product/v1"
+	productmockv1 "go.citilink.cloud/order/internal/specs/grpcclient/mock/citilink/catalog/product/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestFaultScenario_Covers(t *testing.T) {
+	tests := []struct {
+		name     string
+		scenario *FaultScenario
+		itemId   basket_item.ItemId
+		want     bool
+	}{
+		{
+			name:     "all items",
+			scenario: NewFaultScenario(FaultScenarioPriceJump),
+			itemId:   "1",
+			want:     true,
+		},
+		{
+			name:     "listed item",
+			scenario: NewFaultScenario(FaultScenarioPriceJump, "1", "2"),
+			itemId:   "2",
+			want:     true,
+		},
+		{
+			name:     "other item",
+			scenario: NewFaultScenario(FaultScenarioPriceJump, "1", "2"),
+			itemId:   "3",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.scenario.Covers(tt.itemId))
+		})
+	}
+}
+
+func TestFaultScenarioRegistry_Activate(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		scenarios []*FaultScenario
+		wantErr   string
+	}{
+		{
+			name:      "ok",
+			enabled:   true,
+			scenarios: []*FaultScenario{NewFaultScenario(FaultScenarioPriceJump, "1")},
+		},
+		{
+			name:      "disabled",
+			scenarios: []*FaultScenario{NewFaultScenario(FaultScenarioPriceJump, "1")},
+			wantErr:   "fault injection is disabled",
+		},
+		{
+			name:      "unknown scenario",
+			enabled:   true,
+			scenarios: []*FaultScenario{NewFaultScenario("unknown")},
+			wantErr:   "unknown fault scenario 'unknown'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+
+			err := NewFaultScenarioRegistry(tt.enabled).Activate(bsk, tt.scenarios...)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Empty(t, bsk.FaultScenarios())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.scenarios, bsk.FaultScenarios())
+			}
+		})
+	}
+
+	t.Run("deactivate", func(t *testing.T) {
+		bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+		registry := NewFaultScenarioRegistry(true)
+		require.NoError(t, registry.Activate(bsk, NewFaultScenario(FaultScenarioCatalogTimeout)))
+
+		NewFaultScenarioRegistry(false).Deactivate(bsk)
+
+		assert.Empty(t, bsk.FaultScenarios())
+	})
+}
+
+func TestFaultScenarioRegistry_WrapProductClient(t *testing.T) {
+	request := &productv1.FindFullRequest{Ids: []string{"1", "2"}, SpaceId: "msk_cl"}
+	response := func() *productv1.FindFullResponse {
+		return &productv1.FindFullResponse{
+			Infos: []*productv1.FindFullResponse_FullInfo{
+				{
+					Id: "1",
+					Price: &productv1.ProductPriceByRegion{
+						Prices: map[int32]*overallv1.Price{1: {Price: 1000}},
+					},
+				},
+				{
+					Id: "2",
+					Price: &productv1.ProductPriceByRegion{
+						Prices: map[int32]*overallv1.Price{1: {Price: 200}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		scenarios []*FaultScenario
+		callsApi  bool
+		want      map[string]int
+		wantCode  codes.Code
+	}{
+		{
+			name:     "without scenarios",
+			callsApi: true,
+			want:     map[string]int{"1": 1000, "2": 200},
+		},
+		{
+			name:      "catalog timeout",
+			scenarios: []*FaultScenario{NewFaultScenario(FaultScenarioCatalogTimeout, "2")},
+			wantCode:  codes.DeadlineExceeded,
+		},
+		{
+			name:      "catalog timeout of other item",
+			scenarios: []*FaultScenario{NewFaultScenario(FaultScenarioCatalogTimeout, "3")},
+			callsApi:  true,
+			want:      map[string]int{"1": 1000, "2": 200},
+		},
+		{
+			name:      "price jump",
+			scenarios: []*FaultScenario{NewFaultScenario(FaultScenarioPriceJump, "1")},
+			callsApi:  true,
+			want:      map[string]int{"1": 1150, "2": 200},
+		},
+		{
+			name: "item vanished",
+			scenarios: []*FaultScenario{
+				NewFaultScenario(FaultScenarioPriceJump),
+				NewFaultScenario(FaultScenarioItemVanished, "2"),
+			},
+			callsApi: true,
+			want:     map[string]int{"1": 1150},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			productApiMock := productmockv1.NewMockProductAPIClient(ctrl)
+			if tt.callsApi {
+				productApiMock.EXPECT().FindFull(gomock.Any(), request).Return(response(), nil)
+			}
+			ctx := withFaultScenarios(context.Background(), tt.scenarios)
+
+			got, err := NewFaultScenarioRegistry(true).WrapProductClient(productApiMock).FindFull(ctx, request)
+
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			prices := make(map[string]int, len(got.GetInfos()))
+			for _, info := range got.GetInfos() {
+				prices[info.GetId()] = int(info.GetPrice().GetPrices()[1].GetPrice())
+			}
+			assert.Equal(t, tt.want, prices)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		productApiMock := productmockv1.NewMockProductAPIClient(gomock.NewController(t))
+
+		assert.True(t, NewFaultScenarioRegistry(false).WrapProductClient(productApiMock) == productApiMock)
+	})
+}
+
+func TestFaultScenarioRegistry_WrapActualizerItems(t *testing.T) {
+	product := basket_item.NewItem("1", basket_item.TypeProduct, "product", "", 1, 100, 0, "msk_cl", 1)
+	present := basket_item.NewItem("2", basket_item.TypePresent, "present", "", 1, 0, 0, "msk_cl", 1)
+
+	t.Run("present out of stock", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		productAItem := NewMockActualizerItem(ctrl)
+		presentAItem := NewMockActualizerItem(ctrl)
+		presentAItem.EXPECT().GetItemId().Return(basket_item.ItemId("2")).AnyTimes()
+		actualizerItems := NewMockActualizerItems(ctrl)
+		actualizerItems.EXPECT().FindByItem(product).Return(productAItem)
+		actualizerItems.EXPECT().FindByItem(present).Return(presentAItem)
+		actualizerItems.EXPECT().FindByType(basket_item.TypePresent).Return([]ActualizerItem{presentAItem})
+		bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+		registry := NewFaultScenarioRegistry(true)
+		require.NoError(t, registry.Activate(bsk, NewFaultScenario(FaultScenarioPresentOutOfStock)))
+
+		got := registry.WrapActualizerItems(bsk, actualizerItems)
+
+		assert.Equal(t, productAItem, got.FindByItem(product))
+		assert.Nil(t, got.FindByItem(present))
+		assert.Empty(t, got.FindByType(basket_item.TypePresent))
+		_, hasPresentChoices := got.(PresentChoiceActualizerItems)
+		assert.False(t, hasPresentChoices)
+	})
+
+	t.Run("present choices", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		actualizerItems := struct {
+			*MockActualizerItems
+			*MockPresentChoiceActualizerItems
+		}{NewMockActualizerItems(ctrl), NewMockPresentChoiceActualizerItems(ctrl)}
+		actualizerItems.MockPresentChoiceActualizerItems.EXPECT().FindPresentChoices(product).
+			Return([]basket_item.ItemId{"2", "3"})
+		bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+		registry := NewFaultScenarioRegistry(true)
+		require.NoError(t, registry.Activate(bsk, NewFaultScenario(FaultScenarioPresentOutOfStock, "3")))
+
+		got := registry.WrapActualizerItems(bsk, actualizerItems)
+
+		presentChoices, ok := got.(PresentChoiceActualizerItems)
+		require.True(t, ok)
+		assert.Equal(t, []basket_item.ItemId{"2", "3"}, presentChoices.FindPresentChoices(product))
+	})
+
+	t.Run("without scenarios", func(t *testing.T) {
+		actualizerItems := NewMockActualizerItems(gomock.NewController(t))
+		bsk := &Basket{data: NewBasketData("msk_cl", catalog_types.PriceColumnRetail, "")}
+
+		assert.True(t, NewFaultScenarioRegistry(true).WrapActualizerItems(bsk, actualizerItems) == actualizerItems)
+	})
+}
@@ -1,7 +1,9 @@
 package basket
 
 import (
+	"go.citilink.cloud/catalog_types"
 	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"strings"
 )
 
 type Finder func([]*basket_item.Item) basket_item.Items
@@ -55,13 +57,16 @@ func findChildrenOfRecursive(parent *basket_item.Item, items []*basket_item.Item
 }
 
 func (f *finders) ByType(itemTypes ...basket_item.Type) Finder {
+	types := make(map[basket_item.Type]struct{}, len(itemTypes))
+	for _, itemType := range itemTypes {
+		types[itemType] = struct{}{}
+	}
+
 	return func(items []*basket_item.Item) basket_item.Items {
 		var foundedItems []*basket_item.Item
 		for _, item := range items {
-			for _, itemType := range itemTypes {
-				if item.Type() == itemType {
-					foundedItems = append(foundedItems, item)
-				}
+			if _, ok := types[item.Type()]; ok {
+				foundedItems = append(foundedItems, item)
 			}
 		}
 
@@ -134,3 +139,168 @@ func (f *finders) FindUserAddedItems() Finder {
 		return foundedItems
 	}
 }
+
+// And находит позиции, которые подходят под все переданные поиски
+func (f *finders) And(finders ...Finder) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		foundedItems := basket_item.Items(items)
+		for _, finder := range finders {
+			foundedItems = finder(foundedItems)
+		}
+
+		return foundedItems
+	}
+}
+
+// Or находит позиции, которые подходят хотя бы под один из переданных поисков. Порядок позиций сохраняется
+func (f *finders) Or(finders ...Finder) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		foundedIds := make(map[basket_item.UniqId]struct{})
+		for _, finder := range finders {
+			for _, item := range finder(items) {
+				foundedIds[item.UniqId()] = struct{}{}
+			}
+		}
+
+		return filterItems(items, func(item *basket_item.Item) bool {
+			_, ok := foundedIds[item.UniqId()]
+			return ok
+		})
+	}
+}
+
+// Not находит позиции, которые не подходят под переданный поиск
+func (f *finders) Not(finder Finder) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		foundedIds := make(map[basket_item.UniqId]struct{})
+		for _, item := range finder(items) {
+			foundedIds[item.UniqId()] = struct{}{}
+		}
+
+		return filterItems(items, func(item *basket_item.Item) bool {
+			_, ok := foundedIds[item.UniqId()]
+			return !ok
+		})
+	}
+}
+
+// Selected находит позиции, выбранные для покупки
+func (f *finders) Selected() Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.IsSelected()
+		})
+	}
+}
+
+// ByProblemIds находит позиции, у которых есть хотя бы одна из переданных проблем
+func (f *finders) ByProblemIds(problemIds ...basket_item.ProblemId) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			for _, problem := range item.Problems() {
+				for _, problemId := range problemIds {
+					if problem.Id() == problemId {
+						return true
+					}
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+// ByCategoryIds находит товары из переданных категорий
+func (f *finders) ByCategoryIds(categoryIds ...catalog_types.CategoryId) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			if item.Additions().GetProduct() == nil {
+				return false
+			}
+
+			for _, categoryId := range categoryIds {
+				if item.Additions().GetProduct().CategoryId() == categoryId {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+// ByBrands находит товары переданных брендов, бренд сравнивается без учета регистра
+func (f *finders) ByBrands(brands ...string) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			if item.Additions().GetProduct() == nil {
+				return false
+			}
+
+			for _, brand := range brands {
+				if strings.EqualFold(item.Additions().GetProduct().BrandName(), brand) {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+}
+
+// ByPriceRange находит позиции с ценой за единицу в диапазоне [minPrice, maxPrice]. Если maxPrice равен 0, то
+// верхняя граница не ограничена
+func (f *finders) ByPriceRange(minPrice, maxPrice int) Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.Price() >= minPrice && (maxPrice == 0 || item.Price() <= maxPrice)
+		})
+	}
+}
+
+// Marked находит маркированные товары
+func (f *finders) Marked() Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.Additions().GetProduct() != nil && item.Additions().GetProduct().IsMarked()
+		})
+	}
+}
+
+// FnsTracked находит прослеживаемые товары
+func (f *finders) FnsTracked() Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.Additions().GetProduct() != nil && item.Additions().GetProduct().IsFnsTracked()
+		})
+	}
+}
+
+// PrepaymentMandatory находит позиции, для которых обязательна предоплата
+func (f *finders) PrepaymentMandatory() Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.IsPrepaymentMandatory()
+		})
+	}
+}
+
+// PartOfConfiguration находит позиции в составе конфигураций
+func (f *finders) PartOfConfiguration() Finder {
+	return func(items []*basket_item.Item) basket_item.Items {
+		return filterItems(items, func(item *basket_item.Item) bool {
+			return item.Type().IsPartOfConfiguration()
+		})
+	}
+}
+
+func filterItems(items []*basket_item.Item, predicate func(item *basket_item.Item) bool) basket_item.Items {
+	var foundedItems []*basket_item.Item
+	for _, item := range items {
+		if predicate(item) {
+			foundedItems = append(foundedItems, item)
+		}
+	}
+
+	return foundedItems
+}
@@ -0,0 +1,269 @@
+package basket
+
+import (
+	"fmt"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"strconv"
+	"strings"
+)
+
+// ParseFinderQuery разбирает строковый запрос поиска позиций, предназначен для отладочных инструментов. Пример
+// запроса:
+//
+//	type:product AND (problem:1 OR NOT selected:true)
+//
+// Запрос состоит из условий вида ключ:значение, объединенных операторами AND, OR и NOT (без учета регистра) и
+// скобками. NOT связывает сильнее AND, а AND сильнее OR. Значения не могут содержать пробелов и скобок.
+//
+// Поддерживаемые условия:
+//   - type:<тип позиции> - Finders.ByType
+//   - id:<идентификатор товара/услуги> - Finders.ByItemIds
+//   - problem:<идентификатор проблемы> - Finders.ByProblemIds
+//   - category:<идентификатор категории> - Finders.ByCategoryIds
+//   - brand:<бренд> - Finders.ByBrands
+//   - price:<от>-<до> - Finders.ByPriceRange, любая из границ может быть опущена
+//   - selected, marked, fns, prepayment, conf со значением true или false - Finders.Selected, Finders.Marked,
+//     Finders.FnsTracked, Finders.PrepaymentMandatory и Finders.PartOfConfiguration соответственно
+func ParseFinderQuery(query string) (Finder, error) {
+	parser := &finderQueryParser{tokens: tokenizeFinderQuery(query)}
+	if len(parser.tokens) == 0 {
+		return nil, internal.NewValidationError(fmt.Errorf("finder query is empty"))
+	}
+
+	finder, err := parser.parseOr()
+	if err != nil {
+		return nil, internal.NewValidationError(fmt.Errorf("can't parse finder query '%s': %w", query, err))
+	}
+
+	if !parser.isEnd() {
+		return nil, internal.NewValidationError(
+			fmt.Errorf("can't parse finder query '%s': unexpected '%s'", query, parser.peek()),
+		)
+	}
+
+	return finder, nil
+}
+
+func tokenizeFinderQuery(query string) []string {
+	var tokens []string
+	var token strings.Builder
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			token.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+type finderQueryParser struct {
+	tokens   []string
+	position int
+}
+
+func (p *finderQueryParser) isEnd() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *finderQueryParser) peek() string {
+	if p.isEnd() {
+		return ""
+	}
+
+	return p.tokens[p.position]
+}
+
+func (p *finderQueryParser) next() string {
+	token := p.peek()
+	p.position++
+
+	return token
+}
+
+func (p *finderQueryParser) isOperator(operator string) bool {
+	return strings.EqualFold(p.peek(), operator)
+}
+
+func (p *finderQueryParser) parseOr() (Finder, error) {
+	finder, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	finders := []Finder{finder}
+	for p.isOperator("OR") {
+		p.next()
+		finder, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		finders = append(finders, finder)
+	}
+
+	if len(finders) == 1 {
+		return finders[0], nil
+	}
+
+	return Finders.Or(finders...), nil
+}
+
+func (p *finderQueryParser) parseAnd() (Finder, error) {
+	finder, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	finders := []Finder{finder}
+	for p.isOperator("AND") {
+		p.next()
+		finder, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		finders = append(finders, finder)
+	}
+
+	if len(finders) == 1 {
+		return finders[0], nil
+	}
+
+	return Finders.And(finders...), nil
+}
+
+func (p *finderQueryParser) parseNot() (Finder, error) {
+	if p.isOperator("NOT") {
+		p.next()
+		finder, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return Finders.Not(finder), nil
+	}
+
+	if p.peek() == "(" {
+		p.next()
+		finder, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.next() != ")" {
+			return nil, fmt.Errorf("expected ')'")
+		}
+
+		return finder, nil
+	}
+
+	if p.isEnd() {
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+
+	return parseFinderQueryCondition(p.next())
+}
+
+func parseFinderQueryCondition(condition string) (Finder, error) {
+	parts := strings.SplitN(condition, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("condition '%s' must be in format key:value", condition)
+	}
+
+	key, value := parts[0], parts[1]
+	switch strings.ToLower(key) {
+	case "type":
+		itemType := basket_item.Type(value)
+		err := itemType.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("unknown item type '%s'", value)
+		}
+
+		return Finders.ByType(itemType), nil
+	case "id":
+		return Finders.ByItemIds(basket_item.ItemId(value)), nil
+	case "problem":
+		problemId, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid problem id '%s'", value)
+		}
+
+		return Finders.ByProblemIds(basket_item.ProblemId(problemId)), nil
+	case "category":
+		categoryId, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category id '%s'", value)
+		}
+
+		return Finders.ByCategoryIds(catalog_types.CategoryId(categoryId)), nil
+	case "brand":
+		return Finders.ByBrands(value), nil
+	case "price":
+		return parseFinderQueryPriceRange(value)
+	case "selected":
+		return parseFinderQueryFlag(value, Finders.Selected())
+	case "marked":
+		return parseFinderQueryFlag(value, Finders.Marked())
+	case "fns":
+		return parseFinderQueryFlag(value, Finders.FnsTracked())
+	case "prepayment":
+		return parseFinderQueryFlag(value, Finders.PrepaymentMandatory())
+	case "conf":
+		return parseFinderQueryFlag(value, Finders.PartOfConfiguration())
+	default:
+		return nil, fmt.Errorf("unknown condition '%s'", key)
+	}
+}
+
+func parseFinderQueryPriceRange(value string) (Finder, error) {
+	bounds := strings.SplitN(value, "-", 2)
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("price range '%s' must be in format from-to", value)
+	}
+
+	from, to := bounds[0], bounds[1]
+	var minPrice, maxPrice int
+	var err error
+	if from != "" {
+		minPrice, err = strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price '%s'", from)
+		}
+	}
+
+	if to != "" {
+		maxPrice, err = strconv.Atoi(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price '%s'", to)
+		}
+	}
+
+	return Finders.ByPriceRange(minPrice, maxPrice), nil
+}
+
+func parseFinderQueryFlag(value string, finder Finder) (Finder, error) {
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid flag value '%s'", value)
+	}
+
+	if !flag {
+		return Finders.Not(finder), nil
+	}
+
+	return finder, nil
+}
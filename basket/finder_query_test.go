@@ -0,0 +1,104 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"testing"
+)
+
+func TestParseFinderQuery(t *testing.T) {
+	items := newTestFinderItems()
+	all := basket_item.Items{items["phone"], items["shoes"], items["service"], items["cpu"]}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    basket_item.Items
+		wantErr string
+	}{
+		{
+			name:  "type and problem",
+			query: "type:product AND problem:1",
+			want:  basket_item.Items{items["shoes"]},
+		},
+		{
+			name:  "or with lower case operators",
+			query: "brand:apple or conf:true",
+			want:  basket_item.Items{items["phone"], items["cpu"]},
+		},
+		{
+			name:  "and binds stronger than or",
+			query: "type:product AND marked:true OR type:product_in_configuration",
+			want:  basket_item.Items{items["shoes"], items["cpu"]},
+		},
+		{
+			name:  "parentheses and not",
+			query: "NOT (selected:false OR price:-5000)",
+			want:  basket_item.Items{items["phone"], items["cpu"]},
+		},
+		{
+			name:  "flags",
+			query: "fns:true AND prepayment:true AND category:10",
+			want:  basket_item.Items{items["phone"]},
+		},
+		{
+			name:  "id and price range",
+			query: "id:1 OR price:9000-11000",
+			want:  basket_item.Items{items["phone"], items["cpu"]},
+		},
+		{
+			name:    "empty query",
+			query:   " ",
+			wantErr: "finder query is empty",
+		},
+		{
+			name:    "unknown condition",
+			query:   "color:red",
+			wantErr: "can't parse finder query 'color:red': unknown condition 'color'",
+		},
+		{
+			name:    "unknown type",
+			query:   "type:car",
+			wantErr: "can't parse finder query 'type:car': unknown item type 'car'",
+		},
+		{
+			name:    "condition without value",
+			query:   "type:product AND selected",
+			wantErr: "can't parse finder query 'type:product AND selected': condition 'selected' must be in format key:value",
+		},
+		{
+			name:    "unclosed parenthesis",
+			query:   "(type:product",
+			wantErr: "can't parse finder query '(type:product': expected ')'",
+		},
+		{
+			name:    "dangling operator",
+			query:   "type:product AND",
+			wantErr: "can't parse finder query 'type:product AND': unexpected end of query",
+		},
+		{
+			name:    "unexpected token",
+			query:   "type:product type:present",
+			wantErr: "can't parse finder query 'type:product type:present': unexpected 'type:present'",
+		},
+		{
+			name:    "invalid flag",
+			query:   "marked:yes",
+			wantErr: "can't parse finder query 'marked:yes': invalid flag value 'yes'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finder, err := ParseFinderQuery(tt.query)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, finder)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, finder(all))
+		})
+	}
+}
@@ -632,3 +632,76 @@ func Test_finders_FindUserAddedItems(t *testing.T) {
 		})
 	}
 }
+
+func newTestFinderItems() map[string]*basket_item.Item {
+	items := map[string]*basket_item.Item{
+		"phone": basket_item.NewItem(
+			"1", basket_item.TypeProduct, "phone", "", 1, 20000, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"shoes": basket_item.NewItem(
+			"2", basket_item.TypeProduct, "shoes", "", 1, 5000, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"service": basket_item.NewItem(
+			"3", basket_item.TypeSubcontractServiceForProduct, "service", "", 1, 500, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"cpu": basket_item.NewItem(
+			"4", basket_item.TypeConfigurationProduct, "cpu", "", 1, 10000, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+	}
+
+	items["phone"].Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	items["phone"].Additions().GetProduct().SetCategoryId(10)
+	items["phone"].Additions().GetProduct().SetBrandName("Apple")
+	items["phone"].Additions().GetProduct().SetIsFnsTracked(true)
+	items["phone"].SetPrepaymentMandatory(true)
+	items["shoes"].Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	items["shoes"].Additions().GetProduct().SetCategoryId(20)
+	items["shoes"].Additions().GetProduct().SetIsMarked(true)
+	items["shoes"].AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "нет в наличии"))
+	items["service"].SetIsSelected(false)
+
+	return items
+}
+
+func Test_finders_Predicates(t *testing.T) {
+	items := newTestFinderItems()
+	all := basket_item.Items{items["phone"], items["shoes"], items["service"], items["cpu"]}
+
+	tests := []struct {
+		name   string
+		finder Finder
+		want   basket_item.Items
+	}{
+		{"selected", Finders.Selected(), basket_item.Items{items["phone"], items["shoes"], items["cpu"]}},
+		{"problem", Finders.ByProblemIds(basket_item.ProblemNotAvailable), basket_item.Items{items["shoes"]}},
+		{"category", Finders.ByCategoryIds(20, 30), basket_item.Items{items["shoes"]}},
+		{"brand", Finders.ByBrands("apple"), basket_item.Items{items["phone"]}},
+		{"price range", Finders.ByPriceRange(1000, 10000), basket_item.Items{items["shoes"], items["cpu"]}},
+		{"price from", Finders.ByPriceRange(10000, 0), basket_item.Items{items["phone"], items["cpu"]}},
+		{"marked", Finders.Marked(), basket_item.Items{items["shoes"]}},
+		{"fns tracked", Finders.FnsTracked(), basket_item.Items{items["phone"]}},
+		{"prepayment mandatory", Finders.PrepaymentMandatory(), basket_item.Items{items["phone"]}},
+		{"part of configuration", Finders.PartOfConfiguration(), basket_item.Items{items["cpu"]}},
+		{
+			"and",
+			Finders.And(Finders.ByType(basket_item.TypeProduct), Finders.ByPriceRange(0, 10000)),
+			basket_item.Items{items["shoes"]},
+		},
+		{
+			"or",
+			Finders.Or(Finders.PartOfConfiguration(), Finders.Marked()),
+			basket_item.Items{items["shoes"], items["cpu"]},
+		},
+		{"not", Finders.Not(Finders.Selected()), basket_item.Items{items["service"]}},
+		{"nothing found", Finders.ByBrands("Samsung"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.finder(all))
+		})
+	}
+}
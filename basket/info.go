@@ -8,9 +8,12 @@ func NewInfo(item *basket_item.Item, info *basket_item.Info) *Info {
 	return &Info{item: item, info: info}
 }
 
+//go:generate go run ../tools/msgpackgen -output=info_msgpack.go -types=Info
+
+//msgpack:len-err incorrect len
 type Info struct {
-	item *basket_item.Item // 1
-	info *basket_item.Info // 2
+	item *basket_item.Item `msgpackidx:"1"`
+	info *basket_item.Info `msgpackidx:"2"`
 }
 
 func (i *Info) Item() *basket_item.Item {
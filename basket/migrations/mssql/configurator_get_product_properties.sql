@@ -0,0 +1,30 @@
+-- Свойства комплектующих, по которым ConfigurationValidator проверяет совместимость конфигураций
+-- (см. basket/configuration_properties_mssql.go)
+IF OBJECT_ID(N'Configurator.product_properties', N'U') IS NULL
+BEGIN
+    CREATE TABLE Configurator.product_properties
+    (
+        item_id  VARCHAR(50)   NOT NULL,
+        property VARCHAR(50)   NOT NULL,
+        value    NVARCHAR(255) NOT NULL,
+        CONSTRAINT PK_Configurator_product_properties PRIMARY KEY (item_id, property)
+    );
+END
+GO
+
+-- Возвращает свойства комплектующих. @item_ids - идентификаторы товаров через "|". Свойства комплектующих не зависят
+-- от региона, @space_id оставлен для совместимости сигнатуры с остальными процедурами конфигуратора
+CREATE OR ALTER PROCEDURE Configurator.get_product_properties
+    @item_ids VARCHAR(MAX),
+    @space_id VARCHAR(50)
+AS
+BEGIN
+    SET NOCOUNT ON;
+
+    SELECT p.item_id,
+           p.property,
+           p.value
+    FROM Configurator.product_properties AS p
+             INNER JOIN STRING_SPLIT(@item_ids, '|') AS ids ON ids.value = p.item_id;
+END
+GO
@@ -18,12 +18,15 @@ type configurationItemRefresher struct {
 	facadeProductClient facade_productv1.ProductAPIClient
 }
 
+// NewConfigurationItemRefresher создает обновление конфигураций. Клиент каталога оборачивается реестром сценариев
+// внедрения сбоев, выключенный или nil реестр оставляет клиент без изменений
 func NewConfigurationItemRefresher(
 	productClient productv1.ProductAPIClient,
 	facadeProductClient facade_productv1.ProductAPIClient,
+	faultScenarios *basket.FaultScenarioRegistry,
 ) *configurationItemRefresher {
 	return &configurationItemRefresher{
-		productClient:       productClient,
+		productClient:       faultScenarios.WrapProductClient(productClient),
 		facadeProductClient: facadeProductClient,
 	}
 }
@@ -42,8 +45,7 @@ func (c *configurationItemRefresher) Refresh(
 ) error {
 	logger = logger.With(citizap.SpaceId(string(bsk.SpaceId())))
 
-	// На текущий момент у нас в корзине может быть только одна конфигурация, то есть можно сделать и conf := items[0].
-	// Но тогда надо будет делать на всякий случай проверку на len(items) > 0, а это уже смотрится не так лаконично
+	// В корзине может быть несколько конфигураций, каждая из них обновляется независимо от остальных
 	for _, conf := range items {
 		// Учитывая текущее состояние конфигурации, мы можем проверять только товары в конфигурации, услугами и прочим
 		// занимается актуалайзер, да это костыль, но пока без него никак.
@@ -108,6 +110,7 @@ func (c *configurationItemRefresher) Refresh(
 		}
 
 		var notAvailableProductItemIds []basket_item.ItemId
+		isDisassembled := false
 		for _, productItem := range productItems {
 			var productInfo *productv1.FindFullResponse_FullInfo
 			if info, ok := itemsDetails[string(productItem.ItemId())]; ok {
@@ -132,12 +135,15 @@ func (c *configurationItemRefresher) Refresh(
 					citizap.SpaceId(string(productItem.SpaceId())),
 				)
 
-				err = bsk.Configuration().Disassemble()
-				if err != nil {
-					return fmt.Errorf("can't dissassemble configuration: %w", err)
+				if !isDisassembled {
+					err = bsk.Configuration().Disassemble(ctx, conf.UniqId())
+					if err != nil {
+						return fmt.Errorf("can't dissassemble configuration: %w", err)
+					}
+					isDisassembled = true
 				}
 
-				err := bsk.Remove(productItem, false)
+				err := bsk.RemoveBySystem(productItem, basket.AuditReasonNotInCatalog)
 				if err != nil {
 					return fmt.Errorf("can't remove product: %w", err)
 				}
@@ -145,10 +151,9 @@ func (c *configurationItemRefresher) Refresh(
 				bsk.AddInfo(
 					basket.NewInfo(
 						productItem,
-						basket_item.NewInfo(
+						basket_item.NewLocalizedInfo(
 							basket_item.InfoIdPositionRemoved,
-							"Позиция удалена в связи с отсутствием в выбранном городе. "+
-								"Конфигурация, в которой присутствовала данная позиция, разобрана.",
+							basket_item.MessageConfigurationPartRemovedInCity,
 						),
 					),
 				)
@@ -171,9 +176,9 @@ func (c *configurationItemRefresher) Refresh(
 			if price, ok := productInfo.GetPrice().GetPrices()[int32(productItem.PriceColumn())]; ok {
 				if productItem.Price() != int(price.GetPrice()) {
 					if productItem.IsSelected() {
-						info := basket_item.NewInfo(
+						info := basket_item.NewLocalizedInfo(
 							basket_item.InfoIdPriceChanged,
-							"цена на комплектующую конфигурации изменилась",
+							basket_item.MessageConfigurationPartPriceChanged,
 						)
 						info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 							From: productItem.Price(),
@@ -182,7 +187,7 @@ func (c *configurationItemRefresher) Refresh(
 						productItem.AddInfo(info)
 					}
 
-					productItem.SetPrice(int(price.GetPrice()))
+					bsk.ChangePrice(productItem, int(price.GetPrice()))
 					productItem.SetBonus(basket_item.CalculateBonus(bsk.User(), productInfo.GetPrice()))
 				}
 			} else {
@@ -194,12 +199,17 @@ func (c *configurationItemRefresher) Refresh(
 			}
 		}
 
+		// разобранной конфигурации больше нет в корзине
+		if isDisassembled {
+			continue
+		}
+
 		// в случае, если какие-то товары стали недоступны, то эту проблему мы добавляем к самой конфигурации, так как
 		// только удалением самой конфигурации можно решить данную проблему
 		if len(notAvailableProductItemIds) > 0 {
-			problem := basket_item.NewProblem(
+			problem := basket_item.NewLocalizedProblem(
 				basket_item.ProblemProductItemInConfigurationNotAvailable,
-				"одна из позиций в конфигурации недоступна",
+				basket_item.MessageConfigurationPartNotAvailable,
 			)
 			problem.Additions().ConfigurationProblemAdditions = basket_item.ConfigurationProblemAdditions{
 				NotAvailableProductItemIds: notAvailableProductItemIds,
@@ -207,6 +217,14 @@ func (c *configurationItemRefresher) Refresh(
 			conf.AddProblem(problem)
 		}
 
+		// несовместимость комплектующих так же решается только разборкой или удалением конфигурации, поэтому проблемы
+		// добавляются к самой конфигурации
+		incompatibilityProblems, err := bsk.Configuration().Validate(ctx, conf.UniqId())
+		if err != nil {
+			return fmt.Errorf("can't validate configuration: %w", err)
+		}
+		conf.AddProblem(incompatibilityProblems...)
+
 		conf.Rules().SetMaxCount(maxCountConf)
 	}
 
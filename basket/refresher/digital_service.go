@@ -28,7 +28,7 @@ func (f *digitalServiceItemRefresher) Refreshable(item *basket_item.Item) bool {
 func (f *digitalServiceItemRefresher) Refresh(
 	ctx context.Context,
 	items []*basket_item.Item,
-	_ basket.RefresherBasket,
+	bsk basket.RefresherBasket,
 	_ *zap.Logger,
 ) error {
 	errGroup, ctx := errgroup.WithContext(ctx)
@@ -42,7 +42,10 @@ func (f *digitalServiceItemRefresher) Refresh(
 			if err != nil {
 				s, _ := status.FromError(err)
 				if s.Code() == codes.NotFound {
-					item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "цифровая услуга не предоставляется"))
+					item.AddProblem(basket_item.NewLocalizedProblem(
+						basket_item.ProblemNotAvailable,
+						basket_item.MessageDigitalServiceNotProvided,
+					))
 					return nil
 				} else {
 					return internal.NewCatalogError(
@@ -59,13 +62,16 @@ func (f *digitalServiceItemRefresher) Refresh(
 			}
 
 			if price.Price == 0 {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-					"цифровая услуга недоступна"))
+				item.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+					basket_item.MessageDigitalServiceNotAvailable))
 			}
 
 			if item.Price() != int(price.GetPrice()) {
 				if item.IsSelected() {
-					info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу изменилась")
+					info := basket_item.NewLocalizedInfo(
+						basket_item.InfoIdPriceChanged,
+						basket_item.MessageServicePriceChanged,
+					)
 					info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 						From: item.Price(),
 						To:   int(price.GetPrice()),
@@ -73,7 +79,7 @@ func (f *digitalServiceItemRefresher) Refresh(
 					item.AddInfo(info)
 				}
 
-				item.SetPrice(int(price.GetPrice()))
+				bsk.ChangePrice(item, int(price.GetPrice()))
 			}
 			return nil
 		})
@@ -30,7 +30,7 @@ func (i *insuranceOfPropertyServiceItemRefresher) Refreshable(item *basket_item.
 func (i *insuranceOfPropertyServiceItemRefresher) Refresh(
 	ctx context.Context,
 	items []*basket_item.Item,
-	_ basket.RefresherBasket,
+	bsk basket.RefresherBasket,
 	_ *zap.Logger,
 ) error {
 	errGroup, ctx := errgroup.WithContext(ctx)
@@ -44,9 +44,9 @@ func (i *insuranceOfPropertyServiceItemRefresher) Refresh(
 			if err != nil {
 				s, _ := status.FromError(err)
 				if s.Code() == codes.NotFound {
-					item.AddProblem(basket_item.NewProblem(
+					item.AddProblem(basket_item.NewLocalizedProblem(
 						basket_item.ProblemNotAvailable,
-						"услуга страхования имущества не предоставляется"))
+						basket_item.MessagePropertyInsuranceNotProvided))
 					return nil
 				} else {
 					return internal.NewCatalogError(
@@ -63,13 +63,16 @@ func (i *insuranceOfPropertyServiceItemRefresher) Refresh(
 			}
 
 			if price.Price == 0 {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-					"услуга страхования имущества недоступна"))
+				item.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+					basket_item.MessagePropertyInsuranceNotAvailable))
 			}
 
 			if item.Price() != int(price.GetPrice()) {
 				if item.IsSelected() {
-					info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу изменилась")
+					info := basket_item.NewLocalizedInfo(
+						basket_item.InfoIdPriceChanged,
+						basket_item.MessageServicePriceChanged,
+					)
 					info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 						From: item.Price(),
 						To:   int(price.GetPrice()),
@@ -77,7 +80,7 @@ func (i *insuranceOfPropertyServiceItemRefresher) Refresh(
 					item.AddInfo(info)
 				}
 
-				item.SetPrice(int(price.GetPrice()))
+				bsk.ChangePrice(item, int(price.GetPrice()))
 			}
 			return nil
 		})
@@ -55,7 +55,10 @@ func (i *insuranceServiceForProductItemRefresher) Refresh(
 				}
 
 				if st.Code() == codes.NotFound {
-					item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "товара с услугой нет в наличии"))
+					item.AddProblem(basket_item.NewLocalizedProblem(
+						basket_item.ProblemNotAvailable,
+						basket_item.MessageProductWithServiceNotAvailable,
+					))
 					return nil
 				}
 
@@ -67,13 +70,19 @@ func (i *insuranceServiceForProductItemRefresher) Refresh(
 
 			insuranceService, ok := response.GetInsuranceServices()[string(item.ItemId())]
 			if !ok {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга страхования товара не предоставляется"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessageProductInsuranceNotProvided,
+				))
 				return nil
 			}
 
 			avail, ok := insuranceService.GetAvailability()[int32(item.PriceColumn())]
 			if !ok || !avail {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга страхования товара не предоставляется"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessageProductInsuranceNotProvided,
+				))
 				return nil
 			}
 
@@ -89,13 +98,16 @@ func (i *insuranceServiceForProductItemRefresher) Refresh(
 			}
 
 			if price.Price == 0 {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-					"услуга страхования товара недоступна"))
+				item.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+					basket_item.MessageProductInsuranceNotAvailable))
 			}
 
 			if item.Price() != int(price.GetPrice()) {
 				if item.IsSelected() {
-					info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу изменилась")
+					info := basket_item.NewLocalizedInfo(
+						basket_item.InfoIdPriceChanged,
+						basket_item.MessageServicePriceChanged,
+					)
 					info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 						From: item.Price(),
 						To:   int(price.GetPrice()),
@@ -103,7 +115,7 @@ func (i *insuranceServiceForProductItemRefresher) Refresh(
 					item.AddInfo(info)
 				}
 
-				item.SetPrice(int(price.GetPrice()))
+				bsk.ChangePrice(item, int(price.GetPrice()))
 			}
 			return nil
 		})
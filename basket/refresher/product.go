@@ -39,10 +39,11 @@ func NewProductItemRefresher(
 	commodityGroupChecker mssql.AvailabilityChecker,
 	configurationCalculator assembly.Calculator,
 	metrics *metrics.Metrics,
+	faultScenarios *basket.FaultScenarioRegistry,
 ) *productItemRefresher {
 	return &productItemRefresher{
 		isMarkingEnabled:        isMarkingEnabled,
-		productClient:           productClient,
+		productClient:           faultScenarios.WrapProductClient(productClient),
 		facadeProductClient:     facadeProductClient,
 		b2b:                     b2b,
 		commodityGroupChecker:   commodityGroupChecker,
@@ -232,7 +233,7 @@ itemsLoop:
 			zap.String("uniq_id", string(item.UniqId())),
 			citizap.SpaceId(string(item.SpaceId())),
 		)
-		err := bsk.Remove(item, false)
+		err := bsk.RemoveBySystem(item, basket.AuditReasonNotInCatalog)
 		if err != nil {
 			return fmt.Errorf("can't remove product: %w", err)
 		}
@@ -240,9 +241,9 @@ itemsLoop:
 		bsk.AddInfo(
 			basket.NewInfo(
 				item,
-				basket_item.NewInfo(
+				basket_item.NewLocalizedInfo(
 					basket_item.InfoIdPositionRemoved,
-					"Позиция удалена в связи с отсутствием в выбранном городе",
+					basket_item.MessagePositionRemovedInCity,
 				),
 			),
 		)
@@ -309,9 +310,9 @@ itemsLoop:
 							productAdditions := item.Additions().GetProduct()
 							if productAdditions.MarkedPurchaseReason() == basket_item.MarkedPurchaseReasonForResale && !hasEdoOrGis {
 								// если отсутствует ЭДО или ГИС
-								item.AddProblem(basket_item.NewProblem(
+								item.AddProblem(basket_item.NewLocalizedProblem(
 									basket_item.ProblemPurchaseReasonNotAvailableForUser,
-									"отсутствует ЭДО или ГИС",
+									basket_item.MessageMarkingDocumentFlowMissing,
 								))
 								return nil
 							}
@@ -356,14 +357,12 @@ itemsLoop:
 							// Если цель приобретения для товара выбрана "перепродажа", а товарная группа недоступна
 							// для перепродажи, то добавляем еще и проблему.
 							if item.Additions().GetProduct().MarkedPurchaseReason() == basket_item.MarkedPurchaseReasonForResale {
-								item.AddProblem(basket_item.NewProblem(
-									basket_item.ProblemPurchaseReasonNotAvailableForUser, allowResale.Message(),
-								))
+								item.AddProblem(allowResale.Problem())
 							}
 						}
 					}
 				} else {
-					err := bsk.Remove(item, false)
+					err := bsk.RemoveBySystem(item, basket.AuditReasonNotAllowedForUser)
 					if err != nil {
 						return fmt.Errorf("can't remove marked product for b2b: %w", err)
 					}
@@ -382,7 +381,10 @@ itemsLoop:
 			// Если товар найден, но он не в наличии, то так же помечаем позицию, как недоступную к покупке
 			isAvailable := productsAvailability[string(item.ItemId())]
 			if !isAvailable {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "товара нет в наличии"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessageProductNotAvailable,
+				))
 				continue
 			}
 
@@ -403,30 +405,33 @@ itemsLoop:
 					}
 
 					if !item.IgnoreFairPriceChanged() && item.IsSelected() {
-						info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на товар изменилась")
+						info := basket_item.NewLocalizedInfo(
+							basket_item.InfoIdPriceChanged,
+							basket_item.MessageProductPriceChanged,
+						)
 						info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 							From: item.Price(),
 							To:   int(price.GetPrice()),
 						}
 						item.AddInfo(info)
 					}
-					item.SetPrice(int(price.GetPrice()))
+					bsk.ChangePrice(item, int(price.GetPrice()))
 				}
 				item.SetBonus(basket_item.CalculateBonus(bsk.User(), productInfo.GetPrice()))
 
 				if price.Price == 0 {
 					item.AddProblem(
-						basket_item.NewProblem(
+						basket_item.NewLocalizedProblem(
 							basket_item.ProblemNotAvailable,
-							"покупка товара недоступна",
+							basket_item.MessageProductNotPurchasable,
 						),
 					)
 				}
 			} else {
 				item.AddProblem(
-					basket_item.NewProblem(
+					basket_item.NewLocalizedProblem(
 						basket_item.ProblemNotAvailable,
-						"покупка товара недоступна",
+						basket_item.MessageProductNotPurchasable,
 					),
 				)
 
@@ -450,9 +455,9 @@ itemsLoop:
 			productAdditions := item.Additions().GetProduct()
 			if item.Count() > total && (productAdditions.AvailTotal() != total || !productAdditions.IsCountMoreThenAvailChecked()) {
 				if item.IsSelected() {
-					info := basket_item.NewInfo(
+					info := basket_item.NewLocalizedInfo(
 						basket_item.InfoIdCountMoreThanAvail,
-						"Запрашиваемое кол-во больше, чем товара в наличии.",
+						basket_item.MessageCountMoreThanAvail,
 					)
 					info.Additionals().CountMoreThenAvail = basket_item.CountMoreThenAvailInfoAdditions{
 						AvailCount: total,
@@ -61,7 +61,10 @@ func (i *subcontractServiceForProductItemRefresher) Refresh(
 				}
 
 				if st.Code() == codes.NotFound {
-					item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "товара с услугой нет в наличии"))
+					item.AddProblem(basket_item.NewLocalizedProblem(
+						basket_item.ProblemNotAvailable,
+						basket_item.MessageProductWithServiceNotAvailable,
+					))
 					return nil
 				}
 
@@ -73,7 +76,10 @@ func (i *subcontractServiceForProductItemRefresher) Refresh(
 
 			subcontractService, ok := response.GetSubcontractServices()[string(item.ItemId())]
 			if !ok {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга субподряда не предоставляется"))
+				item.AddProblem(basket_item.NewLocalizedProblem(
+					basket_item.ProblemNotAvailable,
+					basket_item.MessageSubcontractServiceNotProvided,
+				))
 				return nil
 			}
 
@@ -83,13 +89,16 @@ func (i *subcontractServiceForProductItemRefresher) Refresh(
 			}
 
 			if price.Price == 0 {
-				item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable,
-					"услуга страхования товара недоступна"))
+				item.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailable,
+					basket_item.MessageSubcontractServiceNotAvailable))
 			}
 
 			if item.Price() != int(price.GetPrice()) {
 				if item.IsSelected() {
-					info := basket_item.NewInfo(basket_item.InfoIdPriceChanged, "цена на услугу изменилась")
+					info := basket_item.NewLocalizedInfo(
+						basket_item.InfoIdPriceChanged,
+						basket_item.MessageServicePriceChanged,
+					)
 					info.Additionals().PriceChanged = basket_item.PriceChangedInfoAddition{
 						From: item.Price(),
 						To:   int(price.GetPrice()),
@@ -97,7 +106,7 @@ func (i *subcontractServiceForProductItemRefresher) Refresh(
 					item.AddInfo(info)
 				}
 
-				item.SetPrice(int(price.GetPrice()))
+				bsk.ChangePrice(item, int(price.GetPrice()))
 
 				return nil
 			}
@@ -117,8 +126,8 @@ func (i *subcontractServiceForProductItemRefresher) Refresh(
 				}
 
 				if !res {
-					item.AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailableInSelectedCity,
-						"услуга субподряда недоступна в выбранном городе"))
+					item.AddProblem(basket_item.NewLocalizedProblem(basket_item.ProblemNotAvailableInSelectedCity,
+						basket_item.MessageSubcontractServiceNotAvailableInCity))
 				}
 			}
 			return nil
@@ -0,0 +1,237 @@
+package basket
+
+import (
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"sort"
+	"strings"
+)
+
+// TreeSorter сравнивает две позиции одного уровня дерева корзины. Возвращает отрицательное число, если левая позиция
+// должна идти раньше правой, положительное - если позже, и 0, если порядок позиций сортировкой не определен
+type TreeSorter func(left, right *basket_item.Item) int
+
+var TreeSorters = &treeSorters{}
+
+type treeSorters struct{}
+
+// ByTypePriority сортирует позиции по приоритету типа: товары, услуги, конфигурации и их состав
+func (s *treeSorters) ByTypePriority() TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		return left.SortTypeValue() - right.SortTypeValue()
+	}
+}
+
+// ByName сортирует позиции по наименованию
+func (s *treeSorters) ByName() TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		return strings.Compare(left.Name(), right.Name())
+	}
+}
+
+// ByPosition сортирует позиции в порядке, заданном пользователем (см. Basket.Move)
+func (s *treeSorters) ByPosition() TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		return left.Position() - right.Position()
+	}
+}
+
+// ByAddedTime сортирует позиции по времени добавления в корзину
+func (s *treeSorters) ByAddedTime() TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		switch {
+		case left.AddedAt().Before(right.AddedAt()):
+			return -1
+		case left.AddedAt().After(right.AddedAt()):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// ByCategoryPath сортирует позиции по пути категории товара. Позиции без категории (услуги, конфигурации) идут после
+// позиций с категорией
+func (s *treeSorters) ByCategoryPath() TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		leftPath, rightPath := categoryPathOf(left), categoryPathOf(right)
+		switch {
+		case leftPath == rightPath:
+			return 0
+		case leftPath == "":
+			return 1
+		case rightPath == "":
+			return -1
+		default:
+			return strings.Compare(leftPath, rightPath)
+		}
+	}
+}
+
+// Chain применяет сортировки по очереди: следующая сортировка используется, если предыдущая не определила порядок
+func (s *treeSorters) Chain(sorters ...TreeSorter) TreeSorter {
+	return func(left, right *basket_item.Item) int {
+		for _, sorter := range sorters {
+			if result := sorter(left, right); result != 0 {
+				return result
+			}
+		}
+
+		return 0
+	}
+}
+
+// Default сортировка по умолчанию, совпадает с порядком basket_item.Items.Sort
+func (s *treeSorters) Default() TreeSorter {
+	return s.Chain(s.ByTypePriority(), s.ByPosition(), s.ByName())
+}
+
+func categoryPathOf(item *basket_item.Item) string {
+	if item.Additions().GetProduct() == nil {
+		return ""
+	}
+
+	return item.Additions().GetProduct().CategoryPath()
+}
+
+// TreeNode узел дерева корзины: позиция и ее дочерние позиции
+type TreeNode struct {
+	Item     *basket_item.Item
+	Children []*TreeNode
+}
+
+// Cost стоимость поддерева, считается так же, как BasketData.Cost: учитываются только выбранные для покупки позиции
+// в наличии. Позиции в составе конфигурации не учитываются, так как их стоимость уже входит в цену самой конфигурации
+func (n *TreeNode) Cost() int {
+	cost := 0
+	n.walk(func(item *basket_item.Item) {
+		if item.IsSelected() && !item.Type().IsPartOfConfiguration() && !isNotAvailable(item) {
+			cost += item.Cost()
+		}
+	})
+
+	return cost
+}
+
+// Bonus бонусы за поддерево. Как и в Cost, позиции в составе конфигурации не учитываются
+func (n *TreeNode) Bonus() int {
+	bonus := 0
+	n.walk(func(item *basket_item.Item) {
+		if !item.Type().IsPartOfConfiguration() {
+			bonus += item.Bonus() * item.Count()
+		}
+	})
+
+	return bonus
+}
+
+// Problems проблемы всех позиций поддерева
+func (n *TreeNode) Problems() []*Problem {
+	var problems []*Problem
+	n.walk(func(item *basket_item.Item) {
+		for _, problem := range item.Problems() {
+			problems = append(problems, NewProblem(item, problem))
+		}
+	})
+
+	return problems
+}
+
+// IsRemovable можно ли удалить поддерево целиком без принудительного удаления. Конфигурация удаляется всегда вместе
+// со своим составом, остальные позиции - только если удалить можно каждую позицию поддерева (см. Basket.Remove)
+func (n *TreeNode) IsRemovable() bool {
+	if n.Item.Type() == basket_item.TypeConfiguration {
+		return true
+	}
+
+	if !n.Item.Spec().IsDeletable() {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if !child.IsRemovable() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSelectable можно ли выбрать поддерево для покупки. Выбирается только корневая позиция, дочерние позиции выбираются
+// вместе с родительской. Поддерево нельзя выбрать, если одна из его позиций недоступна
+func (n *TreeNode) IsSelectable() bool {
+	if n.Item.IsChild() {
+		return false
+	}
+
+	isSelectable := true
+	n.walk(func(item *basket_item.Item) {
+		if isNotAvailable(item) {
+			isSelectable = false
+		}
+	})
+
+	return isSelectable
+}
+
+// isNotAvailable есть ли у позиции проблема недоступности
+func isNotAvailable(item *basket_item.Item) bool {
+	for _, problem := range item.Problems() {
+		if problem.Id() == basket_item.ProblemNotAvailable {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *TreeNode) walk(fn func(item *basket_item.Item)) {
+	fn(n.Item)
+	for _, child := range n.Children {
+		child.walk(fn)
+	}
+}
+
+// buildTree строит дерево позиций. Корнями дерева становятся позиции без родителя, а также позиции, родитель которых
+// отсутствует среди переданных позиций
+func buildTree(items basket_item.Items, sorter TreeSorter) []*TreeNode {
+	nodes := make(map[basket_item.UniqId]*TreeNode, len(items))
+	for _, item := range items {
+		nodes[item.UniqId()] = &TreeNode{Item: item}
+	}
+
+	var roots []*TreeNode
+	for _, item := range items {
+		node := nodes[item.UniqId()]
+		parent, ok := nodes[item.ParentUniqId()]
+		if !item.IsChild() || !ok {
+			roots = append(roots, node)
+			continue
+		}
+
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortTreeNodes(roots, sorter)
+
+	return roots
+}
+
+func sortTreeNodes(nodes []*TreeNode, sorter TreeSorter) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		result := sorter(nodes[i].Item, nodes[j].Item)
+		if result == 0 {
+			// позиции, порядок которых сортировка не определила, идут в порядке позиций в корзине, а при одинаковом
+			// порядковом номере (например, у позиций, сохраненных до его появления) - по идентификатору
+			result = TreeSorters.ByPosition()(nodes[i].Item, nodes[j].Item)
+		}
+		if result == 0 {
+			return nodes[i].Item.UniqId() < nodes[j].Item.UniqId()
+		}
+
+		return result < 0
+	})
+
+	for _, node := range nodes {
+		sortTreeNodes(node.Children, sorter)
+	}
+}
@@ -0,0 +1,164 @@
+package basket
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.citilink.cloud/catalog_types"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
+	"testing"
+)
+
+func newTestTreeBasket(t *testing.T) (*Basket, map[string]*basket_item.Item) {
+	items := map[string]*basket_item.Item{
+		"laptop": basket_item.NewItem(
+			"1", basket_item.TypeProduct, "b laptop", "", 2, 1000, 10,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"install": basket_item.NewItem(
+			"2", basket_item.TypeSubcontractServiceForProduct, "install", "", 2, 100, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"mouse": basket_item.NewItem(
+			"3", basket_item.TypeProduct, "a mouse", "", 1, 50, 1,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+		"conf": basket_item.NewConfigurationItem(700, "msk_cl", catalog_types.PriceColumnRetail),
+		"cpu": basket_item.NewItem(
+			"4", basket_item.TypeConfigurationProduct, "cpu", "", 1, 700, 0,
+			"msk_cl", catalog_types.PriceColumnRetail,
+		),
+	}
+	items["laptop"].Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	items["laptop"].Additions().GetProduct().SetCategoryPath("computers/laptops")
+	items["mouse"].Additions().SetProduct(&basket_item.ProductItemAdditions{})
+	items["mouse"].Additions().GetProduct().SetCategoryPath("peripherals/mice")
+	assert.NoError(t, items["laptop"].AddChild(items["install"]))
+	assert.NoError(t, items["conf"].AddChild(items["cpu"]))
+
+	bsk := &Basket{
+		data: &BasketData{
+			spaceId:     "msk_cl",
+			priceColumn: catalog_types.PriceColumnRetail,
+			items:       make(map[basket_item.UniqId]*basket_item.Item),
+		},
+	}
+	for _, item := range items {
+		bsk.data.items[item.UniqId()] = item
+	}
+
+	return bsk, items
+}
+
+func TestBasket_Tree(t *testing.T) {
+	bsk, items := newTestTreeBasket(t)
+
+	tree := bsk.Tree()
+	if !assert.Len(t, tree, 3) {
+		return
+	}
+
+	assert.Equal(t, items["mouse"], tree[0].Item)
+	assert.Equal(t, items["laptop"], tree[1].Item)
+	assert.Equal(t, items["conf"], tree[2].Item)
+
+	laptop := tree[1]
+	if assert.Len(t, laptop.Children, 1) {
+		assert.Equal(t, items["install"], laptop.Children[0].Item)
+	}
+	assert.Equal(t, 2200, laptop.Cost())
+	assert.Equal(t, 20, laptop.Bonus())
+	assert.True(t, laptop.IsRemovable())
+	assert.True(t, laptop.IsSelectable())
+	assert.False(t, laptop.Children[0].IsSelectable())
+
+	conf := tree[2]
+	if assert.Len(t, conf.Children, 1) {
+		assert.Equal(t, items["cpu"], conf.Children[0].Item)
+		assert.False(t, conf.Children[0].IsRemovable())
+	}
+	assert.Equal(t, 700, conf.Cost())
+	assert.True(t, conf.IsRemovable())
+}
+
+func TestBasket_TreeSorters(t *testing.T) {
+	bsk, items := newTestTreeBasket(t)
+
+	tree := bsk.Tree(TreeSorters.ByCategoryPath(), TreeSorters.ByName())
+	if assert.Len(t, tree, 3) {
+		assert.Equal(t, items["laptop"], tree[0].Item)
+		assert.Equal(t, items["mouse"], tree[1].Item)
+		assert.Equal(t, items["conf"], tree[2].Item)
+	}
+}
+
+func TestBasket_TreeOrderByPosition(t *testing.T) {
+	bsk, items := newTestTreeBasket(t)
+	items["laptop"].SetPosition(2)
+	items["mouse"].SetPosition(1)
+	items["conf"].SetPosition(3)
+
+	// сортировка по категории не упорядочивает конфигурацию и товар без категории, порядок задает позиция в корзине
+	items["mouse"].Additions().GetProduct().SetCategoryPath("")
+	tree := bsk.Tree(TreeSorters.ByCategoryPath())
+	if assert.Len(t, tree, 3) {
+		assert.Equal(t, items["laptop"], tree[0].Item)
+		assert.Equal(t, items["mouse"], tree[1].Item)
+		assert.Equal(t, items["conf"], tree[2].Item)
+	}
+
+	items["mouse"].SetPosition(4)
+	tree = bsk.Tree(TreeSorters.ByCategoryPath())
+	if assert.Len(t, tree, 3) {
+		assert.Equal(t, items["conf"], tree[1].Item)
+		assert.Equal(t, items["mouse"], tree[2].Item)
+	}
+}
+
+func TestTreeNode_Cost(t *testing.T) {
+	bsk, items := newTestTreeBasket(t)
+	items["install"].AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга недоступна"))
+	items["conf"].SetIsSelected(false)
+
+	for _, node := range bsk.Tree() {
+		switch node.Item {
+		case items["laptop"]:
+			// недоступная услуга не учитывается, как и в стоимости корзины
+			assert.Equal(t, 2000, node.Cost())
+		case items["conf"]:
+			assert.Equal(t, 0, node.Cost())
+		}
+	}
+}
+
+func TestTreeNode_Problems(t *testing.T) {
+	bsk, items := newTestTreeBasket(t)
+	items["install"].AddProblem(basket_item.NewProblem(basket_item.ProblemNotAvailable, "услуга недоступна"))
+
+	for _, node := range bsk.Tree() {
+		if node.Item != items["laptop"] {
+			assert.Empty(t, node.Problems())
+			continue
+		}
+
+		if assert.Len(t, node.Problems(), 1) {
+			assert.Equal(t, items["install"], node.Problems()[0].Item())
+		}
+		assert.False(t, node.IsSelectable())
+	}
+}
+
+func TestBuildTree_Orphan(t *testing.T) {
+	parent := basket_item.NewItem(
+		"1", basket_item.TypeProduct, "", "", 1, 100, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	child := basket_item.NewItem(
+		"2", basket_item.TypeSubcontractServiceForProduct, "", "", 1, 10, 0,
+		"msk_cl", catalog_types.PriceColumnRetail,
+	)
+	assert.NoError(t, parent.AddChild(child))
+
+	tree := buildTree(basket_item.Items{child}, TreeSorters.Default())
+	if assert.Len(t, tree, 1) {
+		assert.Equal(t, child, tree[0].Item)
+	}
+}
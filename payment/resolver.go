@@ -12,10 +12,9 @@ import (
 	"go.citilink.cloud/order/internal"
 	"go.citilink.cloud/order/internal/metrics"
 	"go.citilink.cloud/order/internal/order"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	"go.uber.org/zap"
 	"gopkg.in/vmihailenco/msgpack.v2"
-	"hash/fnv"
-	"strconv"
 )
 
 type Resolver interface {
@@ -62,10 +61,7 @@ func (r *CompositeResolver) Resolve(ctx context.Context, ordr order.Order) (Reso
 	}
 
 	logger := r.loggerFactory.Create(ctx)
-	h := fnv.New64a()
-	// ошибка глушится, т.к. не влияет на дальнейшую работу
-	_, _ = h.Write([]byte("Resolve:" + ordr.CompileCacheKey(ordr.Fingerprint())))
-	cacheKey := strconv.FormatUint(h.Sum64(), 10)
+	cacheKey := resolveCacheKey(ordr, bsk)
 
 	encodedResolvedIds, err := r.cache.Get(ctx, cacheKey)
 	if err != nil {
@@ -111,16 +107,40 @@ func (r *CompositeResolver) Resolve(ctx context.Context, ordr order.Order) (Reso
 	return resolvedIdMap, nil
 }
 
+// scopedFingerprinter корзина, которая умеет считать отпечаток отдельной области (см. basket.Basket)
+type scopedFingerprinter interface {
+	ScopedFingerprint(scope basket_item.FingerprintScope) string
+}
+
+// resolveCacheKey ключ кэша доступных способов оплаты. Если корзина умеет считать отпечаток области оплаты, ключ
+// строится по нему, и изменения корзины, не влияющие на оплату, не сбрасывают кэш
+func resolveCacheKey(ordr order.Order, bsk order.Basket) string {
+	fingerprint := ordr.Fingerprint()
+	if scoped, ok := bsk.(scopedFingerprinter); ok {
+		fingerprint = scoped.ScopedFingerprint(basket_item.FingerprintScopePayment)
+	}
+
+	return basket_item.NewFingerprintBuilder(basket_item.FingerprintScopePayment).
+		Add("key", "Resolve:"+ordr.CompileCacheKey(fingerprint)).
+		String()
+}
+
 func (r *CompositeResolver) Add(resolver ...Resolver) {
 	r.resolvers = append(r.resolvers, resolver...)
 }
 
+//go:generate go run ../tools/msgpackgen -output=resolver_msgpack.go -types=ResolvedId
+
+//msgpack:min 3
+//msgpack:open
+//msgpack:len-err len doesn't match
+//msgpack:len-format len doesn't match %d
 type ResolvedId struct {
-	id        order.PaymentId                 // 1
-	status    order.AllowStatus               // 2
-	reasons   []*order.DisallowReasonWithInfo // 3
-	isDefault bool                            // 4
-	isChosen  bool                            // 5
+	id        order.PaymentId                 `msgpackidx:"1,int"`
+	status    order.AllowStatus               `msgpackidx:"2,int"`
+	reasons   []*order.DisallowReasonWithInfo `msgpackidx:"3"`
+	isDefault bool                            `msgpackidx:"4,bool"`
+	isChosen  bool                            `msgpackidx:"5,bool"`
 }
 
 func (i *ResolvedId) Id() order.PaymentId {
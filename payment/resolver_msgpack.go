@@ -1,3 +1,5 @@
+// Code generated by msgpackgen. DO NOT EDIT.
+
 package payment
 
 import (
@@ -11,6 +13,7 @@ func (i *ResolvedId) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeArrayLen(5); err != nil {
 		return err
 	}
+
 	if err := e.EncodeInt(int(i.id)); err != nil { // 1
 		return err
 	}
@@ -31,9 +34,8 @@ func (i *ResolvedId) EncodeMsgpack(e *msgpack.Encoder) error {
 }
 
 func (i *ResolvedId) DecodeMsgpack(d *msgpack.Decoder) error {
-	var err error
-	var length int
-	if length, err = d.DecodeArrayLen(); err != nil {
+	length, err := d.DecodeArrayLen()
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 0, "ResolvedId array len")
 	}
 
@@ -41,35 +43,41 @@ func (i *ResolvedId) DecodeMsgpack(d *msgpack.Decoder) error {
 		return internal.NewMsgPackDecodeError(fmt.Errorf("len doesn't match %d", length), 0, "len doesn't match")
 	}
 
-	if v, err := d.DecodeInt(); err != nil { // 1
+	id, err := d.DecodeInt() // 1
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 1, "ResolvedId id")
-	} else {
-		i.id = order.PaymentId(v)
 	}
+	i.id = order.PaymentId(id)
 
-	if v, err := d.DecodeInt(); err != nil { // 2
+	status, err := d.DecodeInt() // 2
+	if err != nil {
 		return internal.NewMsgPackDecodeError(err, 2, "ResolvedId status")
-	} else {
-		i.status = order.AllowStatus(v)
 	}
+	i.status = order.AllowStatus(status)
 
 	if err := d.Decode(&i.reasons); err != nil { // 3
 		return internal.NewMsgPackDecodeError(err, 3, "ResolvedId reasons")
 	}
 
 	if length > 3 {
-		if v, err := d.DecodeBool(); err != nil { // 4
+		isDefault, err := d.DecodeBool() // 4
+		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 4, "ResolvedId isDefault")
-		} else {
-			i.isDefault = v
 		}
+		i.isDefault = isDefault
 	}
 
-	if length == 5 {
-		if v, err := d.DecodeBool(); err != nil { // 5
+	if length > 4 {
+		isChosen, err := d.DecodeBool() // 5
+		if err != nil {
 			return internal.NewMsgPackDecodeError(err, 5, "ResolvedId isChosen")
-		} else {
-			i.isChosen = v
+		}
+		i.isChosen = isChosen
+	}
+
+	for j := 5; j < length; j++ {
+		if err := d.Skip(); err != nil {
+			return internal.NewMsgPackDecodeError(err, j+1, "ResolvedId unknown field")
 		}
 	}
 
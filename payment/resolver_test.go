@@ -12,6 +12,7 @@ import (
 	"go.citilink.cloud/order/internal"
 	"go.citilink.cloud/order/internal/metrics"
 	"go.citilink.cloud/order/internal/order"
+	"go.citilink.cloud/order/internal/order/basket/basket_item"
 	"go.citilink.cloud/order/internal/order/mock"
 	"go.uber.org/zap"
 	"gopkg.in/vmihailenco/msgpack.v2"
@@ -378,6 +379,33 @@ func TestCompositeResolver_Resolve(t *testing.T) {
 	}
 }
 
+type scopedFingerprintBasket struct {
+	*order.MockBasket
+	fingerprint string
+}
+
+func (b *scopedFingerprintBasket) ScopedFingerprint(scope basket_item.FingerprintScope) string {
+	return b.fingerprint + ":" + string(scope)
+}
+
+func TestResolveCacheKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	o := order.NewMockOrder(ctrl)
+	o.EXPECT().Fingerprint().Return("order").AnyTimes()
+	o.EXPECT().CompileCacheKey(gomock.Any()).DoAndReturn(func(fingerprint string) string {
+		return "key:" + fingerprint
+	}).AnyTimes()
+
+	orderKey := resolveCacheKey(o, order.NewMockBasket(ctrl))
+	scopedKey := resolveCacheKey(o, &scopedFingerprintBasket{MockBasket: order.NewMockBasket(ctrl), fingerprint: "basket"})
+
+	assert.True(t, basket_item.IsCurrentFingerprintVersion(orderKey))
+	assert.NotEqual(t, orderKey, scopedKey)
+	assert.Equal(t, scopedKey, basket_item.NewFingerprintBuilder(basket_item.FingerprintScopePayment).
+		Add("key", "Resolve:key:basket:payment").
+		String())
+}
+
 func TestNewResolvedPaymentId(t *testing.T) {
 	type args struct {
 		id order.PaymentId
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var baseImports = map[string]string{
+	"fmt":      "fmt",
+	"internal": "go.citilink.cloud/order/internal",
+	"msgpack":  "gopkg.in/vmihailenco/msgpack.v2",
+	"time":     "time",
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(format string, args ...interface{}) {
+	fmt.Fprintf(&w.buf, format, args...)
+	w.buf.WriteByte('\n')
+}
+
+func generate(packageName string, structs []*structInfo) ([]byte, error) {
+	body := &writer{}
+	imports := make(map[string]string)
+	for name, path := range baseImports {
+		imports[name] = path
+	}
+
+	for _, info := range structs {
+		for name, path := range info.Imports {
+			if existPath, ok := imports[name]; ok && existPath != path {
+				return nil, fmt.Errorf("import name %s is used for %s and %s", name, existPath, path)
+			}
+			imports[name] = path
+		}
+
+		generateEncoder(body, info)
+		generateDecoder(body, packageName, info)
+	}
+
+	source := &writer{}
+	source.line(generatedHeader)
+	source.line("")
+	source.line("package %s", packageName)
+	source.line("")
+	source.line("import (")
+	for _, name := range usedImports(body.buf.Bytes(), imports) {
+		path := imports[name]
+		if filepath.Base(path) == name || (name == "msgpack" && path == baseImports["msgpack"]) {
+			source.line("%s", strconv.Quote(path))
+		} else {
+			source.line("%s %s", name, strconv.Quote(path))
+		}
+	}
+	source.line(")")
+	source.line("")
+	source.buf.Write(body.buf.Bytes())
+
+	formatted, err := format.Source(source.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format generated source: %w\n%s", err, source.buf.String())
+	}
+
+	return formatted, nil
+}
+
+// usedImports возвращает имена пакетов, на которые есть ссылки в сгенерированном коде, в порядке путей импорта
+func usedImports(body []byte, imports map[string]string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+string(body), 0)
+	if err != nil {
+		// ошибка в сгенерированном коде будет выведена при форматировании
+		names := make([]string, 0, len(imports))
+		for name := range imports {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return names
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	var names []string
+	for name := range imports {
+		if used[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return imports[names[i]] < imports[names[j]]
+	})
+
+	return names
+}
+
+func generateEncoder(w *writer, info *structInfo) {
+	r := info.Receiver
+	w.line("func (%s *%s) EncodeMsgpack(e *msgpack.Encoder) error {", r, info.Name)
+	w.line("if err := e.EncodeArrayLen(%d); err != nil {", info.Length())
+	w.line("return err")
+	w.line("}")
+	w.line("")
+
+	for _, field := range info.Fields {
+		if field.Deleted {
+			w.line("if err := e.%s; err != nil { // %d deleted", encodeZero(field.Kind), field.Index)
+			w.line("return err")
+			w.line("}")
+			continue
+		}
+
+		name := localName(info, field)
+		value := r + "." + field.Name
+		if field.Get != "" {
+			value = r + "." + field.Get + "()"
+		}
+
+		switch field.Kind {
+		case kindAny:
+			if field.Get == "" {
+				value = "&" + value
+			}
+			encodeCall(w, fmt.Sprintf("e.Encode(%s)", value), field.Index)
+		case kindString, kindInt, kindInt64, kindBool:
+			encodeCall(w, encodeScalar(field.Kind, value, field.TypeExpr), field.Index)
+		case kindUnixNano:
+			w.line("var %s int64", name)
+			w.line("if !%s.IsZero() {", value)
+			w.line("%s = %s.UnixNano()", name, value)
+			w.line("}")
+			encodeCall(w, fmt.Sprintf("e.EncodeInt64(%s)", name), field.Index)
+		case kindRFC3339:
+			encodeCall(w, fmt.Sprintf("e.EncodeString(%s.Format(time.RFC3339))", value), field.Index)
+		case kindSlice:
+			if field.Get != "" {
+				w.line("%s := %s", name, value)
+				value = name
+			}
+			encodeCall(w, fmt.Sprintf("e.EncodeArrayLen(len(%s))", value), field.Index)
+			w.line("for _, v := range %s {", value)
+			elemCall := "e.Encode(v)"
+			if field.Elem != kindAny {
+				elemCall = encodeScalar(field.Elem, "v", field.ElemTypeExpr)
+			}
+			w.line("if err := %s; err != nil {", elemCall)
+			w.line("return err")
+			w.line("}")
+			w.line("}")
+		}
+	}
+
+	w.line("")
+	w.line("return nil")
+	w.line("}")
+	w.line("")
+}
+
+func encodeCall(w *writer, call string, index int) {
+	w.line("if err := %s; err != nil { // %d", call, index)
+	w.line("return err")
+	w.line("}")
+}
+
+func encodeZero(kind string) string {
+	switch kind {
+	case kindString:
+		return `EncodeString("")`
+	case kindBool:
+		return "EncodeBool(false)"
+	case kindArray:
+		return "EncodeArrayLen(0)"
+	case kindNil:
+		return "EncodeNil()"
+	default:
+		return "EncodeInt(0)"
+	}
+}
+
+var scalarMethods = map[string]string{
+	kindString: "String",
+	kindInt:    "Int",
+	kindInt64:  "Int64",
+	kindBool:   "Bool",
+}
+
+func encodeScalar(kind string, value string, typeExpr string) string {
+	if typeExpr != kind {
+		value = kind + "(" + value + ")"
+	}
+
+	return fmt.Sprintf("e.Encode%s(%s)", scalarMethods[kind], value)
+}
+
+func convert(value string, typeExpr string, kind string) string {
+	if typeExpr == kind {
+		return value
+	}
+
+	return typeExpr + "(" + value + ")"
+}
+
+func generateDecoder(w *writer, packageName string, info *structInfo) {
+	r := info.Receiver
+	w.line("func (%s *%s) DecodeMsgpack(d *msgpack.Decoder) error {", r, info.Name)
+	w.line("length, err := d.DecodeArrayLen()")
+	w.line("if err != nil {")
+	w.line("return %s", decodeError(info, nil, "err", 0, info.Name+" array len"))
+	w.line("}")
+
+	if !info.Unchecked {
+		lengthDesc := info.LenErr
+		if lengthDesc == "" {
+			lengthDesc = fmt.Sprintf("(%s.%s) incorrect len", packageName, info.Name)
+		}
+		lengthFormat := info.LenFormat
+		if lengthFormat == "" {
+			lengthFormat = lengthDesc + ": %d"
+		}
+
+		var condition string
+		switch {
+		case info.Open:
+			condition = fmt.Sprintf("length < %d", info.Min)
+		case info.Min == info.Length():
+			condition = fmt.Sprintf("length != %d", info.Length())
+		default:
+			condition = fmt.Sprintf("length < %d || length > %d", info.Min, info.Length())
+		}
+		w.line("")
+		w.line("if %s {", condition)
+		lengthErr := fmt.Sprintf("fmt.Errorf(%q, length)", lengthFormat)
+		w.line("return %s", decodeError(info, nil, lengthErr, 0, lengthDesc))
+		w.line("}")
+	}
+
+	for _, field := range info.Fields {
+		w.line("")
+		optional := field.Index > info.Min
+		if optional {
+			w.line("if length > %d {", field.Index-1)
+		}
+
+		decodeField(w, info, field)
+
+		if optional {
+			if field.Default != "" {
+				w.line("} else {")
+				assign(w, r, field, field.Default)
+			}
+			w.line("}")
+		}
+	}
+
+	if info.Open {
+		w.line("")
+		w.line("for j := %d; j < length; j++ {", info.Length())
+		w.line("if err := d.Skip(); err != nil {")
+		w.line("return %s", decodeError(info, nil, "err", -1, info.Name+" unknown field"))
+		w.line("}")
+		w.line("}")
+	}
+
+	if info.AfterDecode != "" {
+		w.line("")
+		w.line("%s.%s()", r, info.AfterDecode)
+	}
+
+	w.line("")
+	w.line("return nil")
+	w.line("}")
+	w.line("")
+}
+
+// decodeError возвращает выражение ошибки декодирования. Индекс -1 означает текущий индекс j. Для структур и полей
+// с директивой decode-err ошибка оборачивается без описания поля
+func decodeError(info *structInfo, field *fieldInfo, err string, index int, desc string) string {
+	if info.DecodeErr || (field != nil && field.DecodeErr) {
+		return fmt.Sprintf("internal.NewDecodeErr(%s)", err)
+	}
+
+	indexExpr := strconv.Itoa(index)
+	if index < 0 {
+		indexExpr = "j + 1"
+	}
+
+	return fmt.Sprintf("internal.NewMsgPackDecodeError(%s, %s, %q)", err, indexExpr, desc)
+}
+
+// fieldError возвращает выражение ошибки декодирования поля
+func fieldError(info *structInfo, field *fieldInfo) string {
+	desc := field.Err
+	switch {
+	case desc != "":
+	case field.Deleted:
+		desc = info.Name + " deleted"
+	default:
+		desc = info.Name + " " + field.Name
+	}
+
+	return decodeError(info, field, "err", field.Index, desc)
+}
+
+// elemError возвращает выражение ошибки декодирования элемента slice
+func elemError(info *structInfo, field *fieldInfo) string {
+	if field.ElemErr == "" {
+		return fieldError(info, field)
+	}
+
+	return decodeError(info, field, "err", field.ElemErrIndex, field.ElemErr)
+}
+
+// parseError возвращает выражение ошибки разбора времени
+func parseError(info *structInfo, field *fieldInfo) string {
+	if field.ParseErr == "" {
+		return fieldError(info, field)
+	}
+
+	return decodeError(info, field, "err", field.Index, field.ParseErr)
+}
+
+func assign(w *writer, receiver string, field *fieldInfo, value string) {
+	if field.Set != "" {
+		w.line("%s.%s(%s)", receiver, field.Set, value)
+	} else {
+		w.line("%s.%s = %s", receiver, field.Name, value)
+	}
+}
+
+// reservedNames имена, которые заняты в кодеках или не могут быть именами переменных
+var reservedNames = map[string]bool{
+	"length": true, "err": true, "v": true, "j": true, "d": true, "e": true,
+	"append": true, "len": true, "make": true, "nil": true, "true": true, "false": true,
+	"string": true, "int": true, "int64": true, "bool": true,
+}
+
+// localName имя локальной переменной для поля: имя поля с маленькой буквы, не совпадающее с остальными
+// переменными кодеков, пакетами и типами, на которые ссылается поле
+func localName(info *structInfo, field *fieldInfo) string {
+	name := lowerFirst(field.Name)
+	if reservedNames[name] || token.IsKeyword(name) || name == info.Receiver || isImportName(info, name) ||
+		usesIdent(field.TypeExpr, name) || usesIdent(field.ElemTypeExpr, name) {
+		return name + "Value"
+	}
+
+	return name
+}
+
+func isImportName(info *structInfo, name string) bool {
+	_, ok := baseImports[name]
+	if !ok {
+		_, ok = info.Imports[name]
+	}
+
+	return ok
+}
+
+// usesIdent проверяет, есть ли идентификатор в выражении типа
+func usesIdent(typeExpr string, name string) bool {
+	expr, err := parser.ParseExpr(typeExpr)
+	if err != nil {
+		return false
+	}
+
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Name == name {
+			found = true
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// lowerFirst переводит в нижний регистр первую букву имени, а если имя начинается с аббревиатуры, то всю
+// аббревиатуру: IsOEM -> isOEM, ID -> id, URLPath -> urlPath
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+
+	switch {
+	case upper == 0:
+		return name
+	case upper == 1 || upper == len(runes):
+		// одна заглавная буква или имя целиком из заглавных
+	default:
+		// последняя заглавная буква относится к следующему слову
+		upper--
+	}
+
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// decodeDeleted читает удаленное поле: значение отбрасывается, но его вид проверяется так же, как при записи
+func decodeDeleted(w *writer, info *structInfo, field *fieldInfo) {
+	fail := fieldError(info, field)
+	switch field.Kind {
+	case kindArray:
+		name := fmt.Sprintf("deleted%dLen", field.Index)
+		w.line("%s, err := d.DecodeArrayLen() // %d deleted", name, field.Index)
+		w.line("if err != nil {")
+		w.line("return %s", fail)
+		w.line("}")
+		w.line("for j := 0; j < %s; j++ {", name)
+		w.line("if err := d.Skip(); err != nil {")
+		w.line("return %s", elemError(info, field))
+		w.line("}")
+		w.line("}")
+	case kindNil:
+		w.line("if err := d.DecodeNil(); err != nil { // %d deleted", field.Index)
+		w.line("return %s", fail)
+		w.line("}")
+	default:
+		w.line("if _, err := d.Decode%s(); err != nil { // %d deleted", scalarMethods[field.Kind], field.Index)
+		w.line("return %s", fail)
+		w.line("}")
+	}
+}
+
+func decodeField(w *writer, info *structInfo, field *fieldInfo) {
+	r := info.Receiver
+	if field.Deleted {
+		decodeDeleted(w, info, field)
+
+		return
+	}
+
+	name := localName(info, field)
+	fail := fieldError(info, field)
+	switch field.Kind {
+	case kindAny:
+		if field.Set == "" {
+			w.line("if err := d.Decode(&%s.%s); err != nil { // %d", r, field.Name, field.Index)
+			w.line("return %s", fail)
+			w.line("}")
+
+			return
+		}
+
+		w.line("var %s %s", name, field.TypeExpr)
+		w.line("if err := d.Decode(&%s); err != nil { // %d", name, field.Index)
+		w.line("return %s", fail)
+		w.line("}")
+		assign(w, r, field, name)
+	case kindString, kindInt, kindInt64, kindBool:
+		w.line("%s, err := d.Decode%s() // %d", name, scalarMethods[field.Kind], field.Index)
+		w.line("if err != nil {")
+		w.line("return %s", fail)
+		w.line("}")
+		assign(w, r, field, convert(name, field.TypeExpr, field.Kind))
+	case kindUnixNano:
+		w.line("%s, err := d.DecodeInt64() // %d", name, field.Index)
+		w.line("if err != nil {")
+		w.line("return %s", fail)
+		w.line("}")
+		w.line("if %s != 0 {", name)
+		assign(w, r, field, fmt.Sprintf("time.Unix(0, %s).UTC()", name))
+		w.line("}")
+	case kindRFC3339:
+		parsed := "parsed" + strings.ToUpper(name[:1]) + name[1:]
+		w.line("%s, err := d.DecodeString() // %d", name, field.Index)
+		w.line("if err != nil {")
+		w.line("return %s", fail)
+		w.line("}")
+		w.line("%s, err := time.Parse(time.RFC3339, %s)", parsed, name)
+		w.line("if err != nil {")
+		w.line("// ранее время сохранялось в формате ANSIC")
+		w.line("%s, err = time.Parse(time.ANSIC, %s)", parsed, name)
+		w.line("if err != nil {")
+		w.line("return %s", parseError(info, field))
+		w.line("}")
+		w.line("}")
+		assign(w, r, field, parsed)
+	case kindSlice:
+		w.line("%sLen, err := d.DecodeArrayLen() // %d", name, field.Index)
+		w.line("if err != nil {")
+		w.line("return %s", fail)
+		w.line("}")
+		if field.NilEmpty {
+			w.line("var %s %s", name, field.TypeExpr)
+			w.line("if %sLen > 0 {", name)
+			w.line("%s = make(%s, %sLen)", name, field.TypeExpr, name)
+			w.line("}")
+		} else {
+			w.line("%s := make(%s, %sLen)", name, field.TypeExpr, name)
+		}
+		w.line("for j := 0; j < %sLen; j++ {", name)
+		if field.Elem == kindAny {
+			w.line("if err := d.Decode(&%s[j]); err != nil {", name)
+			w.line("return %s", elemError(info, field))
+			w.line("}")
+		} else {
+			w.line("v, err := d.Decode%s()", scalarMethods[field.Elem])
+			w.line("if err != nil {")
+			w.line("return %s", elemError(info, field))
+			w.line("}")
+			w.line("%s[j] = %s", name, convert("v", field.ElemTypeExpr, field.Elem))
+		}
+		w.line("}")
+		assign(w, r, field, name)
+	}
+}
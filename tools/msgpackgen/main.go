@@ -0,0 +1,90 @@
+// msgpackgen генерирует позиционные msgpack кодеки (EncodeMsgpack/DecodeMsgpack) для структур по тегам полей.
+//
+// Структура кодируется массивом, позиция поля в массиве задается тегом msgpackidx:
+//
+//	type Rules struct {
+//		maxCount int `msgpackidx:"1,int,get=MaxCount,set=SetMaxCount"`
+//	}
+//
+// Формат тега: msgpackidx:"<номер с 1>[,<вид>][,<опция>...]". Виды полей:
+//   - any (по умолчанию) - e.Encode/d.Decode, для вложенных структур, map и т.д.
+//   - string, int, int64, bool - значение приводится к базовому типу и обратно к типу поля
+//   - slice - массив, элементы которого кодируются видом из опции elem (any по умолчанию)
+//   - unixnano - time.Time в виде кол-ва наносекунд, нулевое время кодируется как 0
+//   - rfc3339 - time.Time строкой в формате RFC3339, при чтении также принимается устаревший формат ANSIC
+//
+// Опции:
+//   - get=<метод>, set=<метод> - чтение и запись поля через методы (например, защищенные мьютексом)
+//   - elem=<вид> - вид элементов slice: any, string, int, int64, bool
+//   - nilempty - пустой slice декодируется в nil
+//   - default=<выражение> - значение поля, если в закодированном массиве нет его позиции
+//
+// Директивы в комментарии к структуре:
+//   - //msgpack:deleted <номер> <вид> - удаленное поле: при записи занимает позицию нулевым значением вида (int,
+//     string, bool, array, nil), при чтении значение отбрасывается после проверки вида
+//   - //msgpack:min <кол-во> - минимальная длина массива, поля после нее считаются добавленными позже и при чтении
+//     более коротких массивов остаются нулевыми (или получают значение default). По умолчанию длина должна совпадать
+//   - //msgpack:open - при чтении лишние элементы в конце массива пропускаются
+//   - //msgpack:after-decode <метод> - метод без аргументов, вызываемый после успешного декодирования
+//   - //msgpack:unchecked - длина массива при чтении не проверяется
+//   - //msgpack:len-err <описание> - описание ошибки чтения и проверки длины массива
+//   - //msgpack:len-format <формат> - текст ошибки несовпадения длины, формат с единственным %d
+//   - //msgpack:decode-err [<номер>...] - ошибки чтения структуры (или перечисленных полей) оборачиваются
+//     internal.NewDecodeErr без номера поля
+//   - //msgpack:err <номер> <описание> - описание ошибки чтения поля
+//   - //msgpack:elem-err <номер> <позиция> <описание> - позиция и описание ошибки чтения элемента slice
+//   - //msgpack:parse-err <номер> <описание> - описание ошибки разбора времени поля rfc3339
+//
+// Генератор запускается через go:generate из каталога пакета:
+//
+//	//go:generate go run ../tools/msgpackgen -output=basket_data_msgpack.go -types=BasketData
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	output := flag.String("output", "", "файл, в который записываются кодеки")
+	typeNames := flag.String("types", "", "список структур через запятую")
+	dir := flag.String("dir", ".", "каталог пакета")
+	flag.Parse()
+
+	if *output == "" || *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	err := run(*dir, *output, strings.Split(*typeNames, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "msgpackgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, output string, typeNames []string) error {
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		return err
+	}
+
+	structs := make([]*structInfo, 0, len(typeNames))
+	for _, typeName := range typeNames {
+		info, err := pkg.structInfo(strings.TrimSpace(typeName))
+		if err != nil {
+			return err
+		}
+		structs = append(structs, info)
+	}
+
+	source, err := generate(pkg.name, structs)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), source, 0o644)
+}
@@ -0,0 +1,527 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	tagKey          = "msgpackidx"
+	generatedHeader = "// Code generated by msgpackgen. DO NOT EDIT."
+)
+
+const (
+	kindAny      = "any"
+	kindString   = "string"
+	kindInt      = "int"
+	kindInt64    = "int64"
+	kindBool     = "bool"
+	kindSlice    = "slice"
+	kindUnixNano = "unixnano"
+	kindRFC3339  = "rfc3339"
+
+	// виды, допустимые только для удаленных полей
+	kindArray = "array"
+	kindNil   = "nil"
+)
+
+var fieldKinds = map[string]bool{
+	kindAny: true, kindString: true, kindInt: true, kindInt64: true, kindBool: true, kindSlice: true,
+	kindUnixNano: true, kindRFC3339: true,
+}
+
+var elemKinds = map[string]bool{kindAny: true, kindString: true, kindInt: true, kindInt64: true, kindBool: true}
+
+var deletedKinds = map[string]bool{kindInt: true, kindString: true, kindBool: true, kindArray: true, kindNil: true}
+
+type packageInfo struct {
+	name  string
+	fset  *token.FileSet
+	files []*ast.File
+}
+
+type structInfo struct {
+	Name     string
+	Receiver string
+	Fields   []*fieldInfo
+	// минимальная длина массива при декодировании
+	Min         int
+	Open        bool
+	AfterDecode string
+	// длина массива при декодировании не проверяется
+	Unchecked bool
+	// описание ошибки неверной длины массива и формат ее текста
+	LenErr    string
+	LenFormat string
+	// все ошибки декодирования оборачиваются internal.NewDecodeErr без описания поля
+	DecodeErr bool
+	// пакеты, на которые ссылаются типы полей: имя -> путь импорта
+	Imports map[string]string
+
+	// директивы полей применяются после разбора всех полей, так как удаленные поля описываются тоже директивами
+	fieldDirectives []*fieldDirective
+}
+
+// fieldDirective директива, уточняющая поле с номером index
+type fieldDirective struct {
+	text  string
+	index int
+	apply func(field *fieldInfo) error
+}
+
+func (s *structInfo) Length() int {
+	return len(s.Fields)
+}
+
+type fieldInfo struct {
+	Index    int
+	Name     string
+	Kind     string
+	Elem     string
+	TypeExpr string
+	// тип элемента slice
+	ElemTypeExpr string
+	Get          string
+	Set          string
+	Default      string
+	NilEmpty     bool
+	Deleted      bool
+	// описание ошибок декодирования поля, для slice - ошибки чтения длины
+	Err string
+	// описание и номер в ошибках декодирования элементов slice
+	ElemErr      string
+	ElemErrIndex int
+	// описание ошибки разбора времени
+	ParseErr string
+	// ошибки декодирования поля оборачиваются internal.NewDecodeErr без описания поля
+	DecodeErr bool
+}
+
+func parsePackage(dir string) (*packageInfo, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	pkg := &packageInfo{fset: token.NewFileSet()}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		source, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// ранее сгенерированные файлы пропускаются: они могут быть устаревшими и не нужны для разбора структур
+		if bytes.HasPrefix(source, []byte(generatedHeader)) {
+			continue
+		}
+
+		file, err := parser.ParseFile(pkg.fset, path, source, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		pkg.files = append(pkg.files, file)
+	}
+
+	if len(pkg.files) == 0 {
+		return nil, fmt.Errorf("no go files found in %s", dir)
+	}
+
+	return pkg, nil
+}
+
+func (p *packageInfo) structInfo(name string) (*structInfo, error) {
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if typeSpec.Name.Name != name {
+					continue
+				}
+
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					return nil, fmt.Errorf("type %s is not a struct", name)
+				}
+
+				doc := typeSpec.Doc
+				if doc == nil {
+					doc = genDecl.Doc
+				}
+
+				info, err := p.parseStruct(file, name, doc, structType)
+				if err != nil {
+					return nil, fmt.Errorf("type %s: %w", name, err)
+				}
+
+				return info, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found", name)
+}
+
+func (p *packageInfo) parseStruct(
+	file *ast.File,
+	name string,
+	doc *ast.CommentGroup,
+	structType *ast.StructType,
+) (*structInfo, error) {
+	info := &structInfo{
+		Name:     name,
+		Receiver: p.receiverName(name),
+		Imports:  make(map[string]string),
+	}
+
+	fields := make(map[int]*fieldInfo)
+	addField := func(field *fieldInfo) error {
+		if field.Index < 1 {
+			return fmt.Errorf("field %s: index must start from 1", field.Name)
+		}
+		if _, ok := fields[field.Index]; ok {
+			return fmt.Errorf("index %d is used twice", field.Index)
+		}
+		fields[field.Index] = field
+
+		return nil
+	}
+
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		tagValue, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, ok := reflect.StructTag(tagValue).Lookup(tagKey)
+		if !ok {
+			continue
+		}
+
+		if len(field.Names) != 1 {
+			return nil, fmt.Errorf("tag %s must be set on a single named field", tagKey)
+		}
+
+		parsed, err := parseTag(field.Names[0].Name, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.TypeExpr = p.exprString(field.Type)
+		if parsed.Kind == kindSlice {
+			arrayType, ok := field.Type.(*ast.ArrayType)
+			if !ok || arrayType.Len != nil {
+				return nil, fmt.Errorf("field %s: kind slice requires slice type", parsed.Name)
+			}
+			parsed.ElemTypeExpr = p.exprString(arrayType.Elt)
+		}
+		collectImports(file, field.Type, info.Imports)
+
+		if err := addField(parsed); err != nil {
+			return nil, err
+		}
+	}
+
+	if doc != nil {
+		for _, comment := range doc.List {
+			err := parseDirective(info, comment.Text, addField)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields with tag %s", tagKey)
+	}
+
+	for _, directive := range info.fieldDirectives {
+		field, ok := fields[directive.index]
+		if !ok {
+			return nil, fmt.Errorf("directive '%s': index %d not found", directive.text, directive.index)
+		}
+
+		err := directive.apply(field)
+		if err != nil {
+			return nil, fmt.Errorf("directive '%s': %w", directive.text, err)
+		}
+	}
+
+	for index := 1; index <= len(fields); index++ {
+		field, ok := fields[index]
+		if !ok {
+			return nil, fmt.Errorf("index %d is missing, mark removed fields with //msgpack:deleted", index)
+		}
+		info.Fields = append(info.Fields, field)
+	}
+
+	if info.Min == 0 {
+		info.Min = info.Length()
+	}
+	if info.Min > info.Length() {
+		return nil, fmt.Errorf("min length %d is greater than fields count %d", info.Min, info.Length())
+	}
+
+	for _, field := range info.Fields {
+		if field.Default != "" && field.Index <= info.Min {
+			return nil, fmt.Errorf("field %s: default is useless for mandatory field", field.Name)
+		}
+	}
+
+	return info, nil
+}
+
+func parseTag(name string, tag string) (*fieldInfo, error) {
+	parts := strings.Split(tag, ",")
+	index, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("field %s: invalid index '%s'", name, parts[0])
+	}
+
+	field := &fieldInfo{Index: index, Name: name, Kind: kindAny, Elem: kindAny}
+	for _, option := range parts[1:] {
+		keyValue := strings.SplitN(option, "=", 2)
+		key := keyValue[0]
+		value := ""
+		if len(keyValue) == 2 {
+			value = keyValue[1]
+		}
+
+		switch {
+		case len(keyValue) == 1 && fieldKinds[key]:
+			field.Kind = key
+		case key == "nilempty" && len(keyValue) == 1:
+			field.NilEmpty = true
+		case key == "get" && value != "":
+			field.Get = value
+		case key == "set" && value != "":
+			field.Set = value
+		case key == "default" && value != "":
+			field.Default = value
+		case key == "elem" && elemKinds[value]:
+			field.Elem = value
+		default:
+			return nil, fmt.Errorf("field %s: unknown option '%s'", name, option)
+		}
+	}
+
+	if field.Kind != kindSlice && (field.Elem != kindAny || field.NilEmpty) {
+		return nil, fmt.Errorf("field %s: options elem and nilempty are allowed only for slice", name)
+	}
+
+	return field, nil
+}
+
+func parseDirective(info *structInfo, text string, addField func(field *fieldInfo) error) error {
+	if !strings.HasPrefix(text, "//msgpack:") {
+		return nil
+	}
+
+	args := strings.Fields(strings.TrimPrefix(text, "//msgpack:"))
+	if len(args) == 0 {
+		return fmt.Errorf("empty directive")
+	}
+
+	switch {
+	case args[0] == "deleted" && len(args) == 3:
+		index, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("directive '%s': invalid index", text)
+		}
+		if !deletedKinds[args[2]] {
+			return fmt.Errorf("directive '%s': unknown kind", text)
+		}
+
+		return addField(&fieldInfo{Index: index, Kind: args[2], Deleted: true})
+	case args[0] == "min" && len(args) == 2:
+		min, err := strconv.Atoi(args[1])
+		if err != nil || min < 1 {
+			return fmt.Errorf("directive '%s': invalid length", text)
+		}
+		info.Min = min
+	case args[0] == "open" && len(args) == 1:
+		info.Open = true
+	case args[0] == "after-decode" && len(args) == 2:
+		info.AfterDecode = args[1]
+	case args[0] == "unchecked" && len(args) == 1:
+		info.Unchecked = true
+	case args[0] == "len-err" && len(args) > 1:
+		info.LenErr = strings.Join(args[1:], " ")
+	case args[0] == "len-format" && len(args) > 1:
+		info.LenFormat = strings.Join(args[1:], " ")
+		if strings.Count(info.LenFormat, "%") != 1 || !strings.Contains(info.LenFormat, "%d") {
+			return fmt.Errorf("directive '%s': format must contain a single %%d", text)
+		}
+	case args[0] == "decode-err" && len(args) == 1:
+		info.DecodeErr = true
+	case args[0] == "decode-err":
+		for _, arg := range args[1:] {
+			err := addFieldDirective(info, text, arg, func(field *fieldInfo) error {
+				field.DecodeErr = true
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case args[0] == "err" && len(args) > 2:
+		desc := strings.Join(args[2:], " ")
+
+		return addFieldDirective(info, text, args[1], func(field *fieldInfo) error {
+			field.Err = desc
+
+			return nil
+		})
+	case args[0] == "elem-err" && len(args) > 3:
+		elemIndex, err := strconv.Atoi(args[2])
+		if err != nil {
+			return fmt.Errorf("directive '%s': invalid index", text)
+		}
+		desc := strings.Join(args[3:], " ")
+
+		return addFieldDirective(info, text, args[1], func(field *fieldInfo) error {
+			if field.Kind != kindSlice && field.Kind != kindArray {
+				return fmt.Errorf("field %d is not a slice", field.Index)
+			}
+			field.ElemErr = desc
+			field.ElemErrIndex = elemIndex
+
+			return nil
+		})
+	case args[0] == "parse-err" && len(args) > 2:
+		desc := strings.Join(args[2:], " ")
+
+		return addFieldDirective(info, text, args[1], func(field *fieldInfo) error {
+			if field.Kind != kindRFC3339 {
+				return fmt.Errorf("field %d is not %s", field.Index, kindRFC3339)
+			}
+			field.ParseErr = desc
+
+			return nil
+		})
+	default:
+		return fmt.Errorf("unknown directive '%s'", text)
+	}
+
+	return nil
+}
+
+func addFieldDirective(info *structInfo, text string, index string, apply func(field *fieldInfo) error) error {
+	fieldIndex, err := strconv.Atoi(index)
+	if err != nil {
+		return fmt.Errorf("directive '%s': invalid index", text)
+	}
+
+	info.fieldDirectives = append(info.fieldDirectives, &fieldDirective{text: text, index: fieldIndex, apply: apply})
+
+	return nil
+}
+
+// receiverName возвращает имя получателя, которое чаще всего используется в методах типа, чтобы сгенерированный код
+// не отличался от написанного вручную
+func (p *packageInfo) receiverName(typeName string) string {
+	counts := make(map[string]int)
+	for _, file := range p.files {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 || len(funcDecl.Recv.List[0].Names) != 1 {
+				continue
+			}
+
+			recvType := funcDecl.Recv.List[0].Type
+			if star, ok := recvType.(*ast.StarExpr); ok {
+				recvType = star.X
+			}
+
+			ident, ok := recvType.(*ast.Ident)
+			if !ok || ident.Name != typeName {
+				continue
+			}
+
+			counts[funcDecl.Recv.List[0].Names[0].Name]++
+		}
+	}
+
+	receiver := ""
+	for name, count := range counts {
+		// имена e и d заняты кодировщиком и декодировщиком
+		if name == "e" || name == "d" || name == "_" {
+			continue
+		}
+		if count > counts[receiver] || (count == counts[receiver] && name < receiver) {
+			receiver = name
+		}
+	}
+
+	if receiver == "" {
+		receiver = strings.ToLower(typeName[:1])
+		if receiver == "e" || receiver == "d" {
+			receiver = "r"
+		}
+	}
+
+	return receiver
+}
+
+func (p *packageInfo) exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, p.fset, expr)
+
+	return buf.String()
+}
+
+func collectImports(file *ast.File, expr ast.Expr, imports map[string]string) {
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			name := filepath.Base(path)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+
+			if name == ident.Name {
+				imports[name] = path
+			}
+		}
+
+		return true
+	})
+}
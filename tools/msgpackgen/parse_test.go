@@ -0,0 +1,207 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    *fieldInfo
+		wantErr string
+	}{
+		{
+			name: "default kind",
+			tag:  "1",
+			want: &fieldInfo{Index: 1, Name: "field", Kind: kindAny, Elem: kindAny},
+		},
+		{
+			name: "all options",
+			tag:  "2,slice,nilempty,elem=string,get=Get,set=Set",
+			want: &fieldInfo{
+				Index: 2, Name: "field", Kind: kindSlice, Elem: kindString, Get: "Get", Set: "Set", NilEmpty: true,
+			},
+		},
+		{
+			name: "default value",
+			tag:  "3,bool,default=true",
+			want: &fieldInfo{Index: 3, Name: "field", Kind: kindBool, Elem: kindAny, Default: "true"},
+		},
+		{
+			name:    "invalid index",
+			tag:     "a,int",
+			wantErr: "field field: invalid index 'a'",
+		},
+		{
+			name:    "unknown option",
+			tag:     "1,float",
+			wantErr: "field field: unknown option 'float'",
+		},
+		{
+			name:    "elem without slice",
+			tag:     "1,int,elem=string",
+			wantErr: "field field: options elem and nilempty are allowed only for slice",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTag("field", tt.tag)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackageInfo_StructInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{
+			name: "missing index",
+			source: "type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"\tname string `msgpackidx:\"3,string\"`\n" +
+				"}\n",
+			wantErr: "type Rules: index 2 is missing, mark removed fields with //msgpack:deleted",
+		},
+		{
+			name: "index used twice",
+			source: "// Rules правила\n" +
+				"//msgpack:deleted 1 int\n" +
+				"type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"}\n",
+			wantErr: "type Rules: index 1 is used twice",
+		},
+		{
+			name: "default for mandatory field",
+			source: "type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int,default=1\"`\n" +
+				"}\n",
+			wantErr: "type Rules: field count: default is useless for mandatory field",
+		},
+		{
+			name: "unknown directive",
+			source: "//msgpack:closed\n" +
+				"type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"}\n",
+			wantErr: "type Rules: unknown directive '//msgpack:closed'",
+		},
+		{
+			name: "error description for unknown index",
+			source: "//msgpack:err 2 Rules name\n" +
+				"type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"}\n",
+			wantErr: "type Rules: directive '//msgpack:err 2 Rules name': index 2 not found",
+		},
+		{
+			name: "element error for scalar field",
+			source: "//msgpack:elem-err 1 1 Rules count\n" +
+				"type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"}\n",
+			wantErr: "type Rules: directive '//msgpack:elem-err 1 1 Rules count': field 1 is not a slice",
+		},
+		{
+			name: "length format without count",
+			source: "//msgpack:len-format Rules incorrect len\n" +
+				"type Rules struct {\n" +
+				"\tcount int `msgpackidx:\"1,int\"`\n" +
+				"}\n",
+			wantErr: "type Rules: directive '//msgpack:len-format Rules incorrect len': " +
+				"format must contain a single %d",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parseTestPackage(t, tt.source)
+
+			_, err := pkg.structInfo("Rules")
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	source := "package rules\n\n" +
+		"import \"time\"\n\n" +
+		"// Rules правила\n" +
+		"//msgpack:deleted 2 string\n" +
+		"//msgpack:min 2\n" +
+		"//msgpack:open\n" +
+		"type Rules struct {\n" +
+		"\tcount int `msgpackidx:\"1,int,get=Count\"`\n" +
+		"\tupdatedAt time.Time `msgpackidx:\"3,unixnano\"`\n" +
+		"}\n\n" +
+		"//msgpack:len-err Limits len doesn't match\n" +
+		"//msgpack:err 1 Limits DecodeInt\n" +
+		"//msgpack:elem-err 2 3 Limits ids\n" +
+		"//msgpack:decode-err 3\n" +
+		"type Limits struct {\n" +
+		"\tID int `msgpackidx:\"1,int\"`\n" +
+		"\tids []int `msgpackidx:\"2,slice,elem=int\"`\n" +
+		"\tupdatedAt time.Time `msgpackidx:\"3,rfc3339\"`\n" +
+		"}\n\n" +
+		"func (r *Rules) Count() int {\n\treturn r.count\n}\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rules.go"), []byte(source), 0o644))
+
+	assert.NoError(t, run(dir, "rules_msgpack.go", []string{"Rules", "Limits"}))
+
+	generated, err := os.ReadFile(filepath.Join(dir, "rules_msgpack.go"))
+	assert.NoError(t, err)
+	for _, want := range []string{
+		generatedHeader,
+		"func (r *Rules) EncodeMsgpack(e *msgpack.Encoder) error {",
+		"if err := e.EncodeArrayLen(3); err != nil {",
+		"if err := e.EncodeInt(r.Count()); err != nil { // 1",
+		"if err := e.EncodeString(\"\"); err != nil { // 2 deleted",
+		"func (r *Rules) DecodeMsgpack(d *msgpack.Decoder) error {",
+		"if length < 2 {",
+		"(rules.Rules) incorrect len",
+		"\"Rules deleted\"",
+		"\"Rules unknown field\"",
+		"id, err := d.DecodeInt() // 1",
+		"\"Limits len doesn't match\"",
+		"internal.NewMsgPackDecodeError(err, 1, \"Limits DecodeInt\")",
+		"internal.NewMsgPackDecodeError(err, 3, \"Limits ids\")",
+		"return internal.NewDecodeErr(err)",
+	} {
+		assert.True(t, strings.Contains(string(generated), want), want)
+	}
+
+	// повторная генерация не должна учитывать ранее сгенерированный файл
+	assert.NoError(t, run(dir, "rules_msgpack.go", []string{"Rules", "Limits"}))
+	regenerated, err := os.ReadFile(filepath.Join(dir, "rules_msgpack.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(generated), string(regenerated))
+}
+
+func parseTestPackage(t *testing.T, source string) *packageInfo {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "rules.go"), []byte("package rules\n\n"+source), 0o644)
+	assert.NoError(t, err)
+
+	pkg, err := parsePackage(dir)
+	assert.NoError(t, err)
+
+	return pkg
+}